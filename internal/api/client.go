@@ -0,0 +1,82 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/clincha-org/proxmox-api/pkg/proxmox"
+)
+
+// The proxmox-api library only covers nodes and node networks. The functions in this package
+// extend it with the other endpoints the provider needs while reusing the authenticated
+// *proxmox.Client that the provider creates, so there is still only one login per provider.
+
+const ClusterPath string = "cluster"
+
+// StatusError is returned by doRequest when Proxmox answers with a status other than 200 OK
+type StatusError struct {
+	Status     string
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("status-error: %s %s", e.Status, e.Body)
+}
+
+// doRequest sends a request to the Proxmox API and unwraps the response into result.
+// A nil payload sends no body and a nil result ignores the response body.
+func doRequest(client *proxmox.Client, method string, path string, payload any, result any) error {
+	var requestBody io.Reader
+	if payload != nil {
+		jsonData, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("marshal-request: %w", err)
+		}
+		requestBody = bytes.NewBuffer(jsonData)
+	}
+
+	request, err := http.NewRequest(method, client.Host+proxmox.ApiPath+path, requestBody)
+	if err != nil {
+		return fmt.Errorf("build-request: %w", err)
+	}
+
+	request.AddCookie(&http.Cookie{Name: "PVEAuthCookie", Value: client.Ticket.Data.Ticket})
+	request.Header.Set("CSRFPreventionToken", client.Ticket.Data.CSRFPreventionToken)
+	if payload != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+
+	response, err := client.HTTPClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("do-request: %w", err)
+	}
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return fmt.Errorf("read-response: %w", err)
+	}
+
+	err = response.Body.Close()
+	if err != nil {
+		return fmt.Errorf("close-response: %w", err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return &StatusError{Status: response.Status, StatusCode: response.StatusCode, Body: string(body)}
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	err = json.Unmarshal(body, result)
+	if err != nil {
+		return fmt.Errorf("unmarshal-response: %w", err)
+	}
+
+	return nil
+}
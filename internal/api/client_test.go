@@ -0,0 +1,185 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/clincha-org/proxmox-api/pkg/proxmox"
+)
+
+const testUPID = "UPID:pve:00001234:00005678:66A0B1C2:test:100:root@pam:"
+
+// fakeProxmox is a local HTTP stand-in for the parts of the Proxmox API that the tests need.
+// Collections are registered with the name of the attribute that identifies their members and
+// support list, get, create, update (including the delete parameter) and delete.
+type fakeProxmox struct {
+	mu          sync.Mutex
+	collections map[string]string
+	objects     map[string]map[string]map[string]any
+	handlers    map[string]http.HandlerFunc
+	prefixes    map[string]http.HandlerFunc
+	requests    []string
+}
+
+func newFakeProxmox() *fakeProxmox {
+	return &fakeProxmox{
+		collections: map[string]string{},
+		objects:     map[string]map[string]map[string]any{},
+		handlers:    map[string]http.HandlerFunc{},
+		prefixes:    map[string]http.HandlerFunc{},
+	}
+}
+
+// collection registers a path that behaves like a Proxmox CRUD collection
+func (f *fakeProxmox) collection(path string, idKey string) {
+	f.collections[path] = idKey
+	f.objects[path] = map[string]map[string]any{}
+}
+
+// handle registers a custom handler for a method and path, for example "PUT cluster/sdn"
+func (f *fakeProxmox) handle(route string, handler http.HandlerFunc) {
+	f.handlers[route] = handler
+}
+
+// handlePrefix registers a handler for every request whose path starts with the prefix, for example "nodes/pve/qemu"
+func (f *fakeProxmox) handlePrefix(prefix string, handler http.HandlerFunc) {
+	f.prefixes[prefix] = handler
+}
+
+// seen reports whether a request with the method and path was received
+func (f *fakeProxmox) seen(route string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, request := range f.requests {
+		if request == route {
+			return true
+		}
+	}
+	return false
+}
+
+func writeData(w http.ResponseWriter, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{"data": data})
+}
+
+func (f *fakeProxmox) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, strings.TrimSuffix(proxmox.ApiPath, "/"))
+	path = strings.Trim(path, "/")
+	route := r.Method + " " + path
+
+	f.mu.Lock()
+	f.requests = append(f.requests, route)
+	f.mu.Unlock()
+
+	if route == "POST "+proxmox.AuthenticationTicketPath {
+		writeData(w, map[string]any{"ticket": "ticket", "CSRFPreventionToken": "token", "username": "root@pam"})
+		return
+	}
+
+	if handler, ok := f.handlers[route]; ok {
+		handler(w, r)
+		return
+	}
+
+	for prefix, handler := range f.prefixes {
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			handler(w, r)
+			return
+		}
+	}
+
+	if strings.HasPrefix(path, proxmox.NodesPath+"/") && strings.Contains(path, TasksPath+"/") && strings.HasSuffix(path, "/status") {
+		writeData(w, TaskStatus{Status: "stopped", ExitStatus: "OK"})
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if idKey, ok := f.collections[path]; ok {
+		switch r.Method {
+		case "GET":
+			list := []map[string]any{}
+			for _, object := range f.objects[path] {
+				list = append(list, object)
+			}
+			writeData(w, list)
+		case "POST":
+			body := readBody(r)
+			id, _ := body[idKey].(string)
+			if _, exists := f.objects[path][id]; exists || id == "" {
+				http.Error(w, "already exists", http.StatusInternalServerError)
+				return
+			}
+			f.objects[path][id] = body
+			writeData(w, nil)
+		default:
+			http.Error(w, "method not allowed", http.StatusNotImplemented)
+		}
+		return
+	}
+
+	index := strings.LastIndex(path, "/")
+	if index > 0 {
+		collection, id := path[:index], path[index+1:]
+		if _, ok := f.collections[collection]; ok {
+			object, exists := f.objects[collection][id]
+			if !exists {
+				http.Error(w, id+" does not exist", http.StatusInternalServerError)
+				return
+			}
+			switch r.Method {
+			case "GET":
+				writeData(w, object)
+			case "PUT":
+				body := readBody(r)
+				if deletes, ok := body["delete"].(string); ok {
+					for _, key := range strings.Split(deletes, ",") {
+						delete(object, key)
+					}
+					delete(body, "delete")
+				}
+				for key, value := range body {
+					object[key] = value
+				}
+				writeData(w, nil)
+			case "DELETE":
+				delete(f.objects[collection], id)
+				writeData(w, nil)
+			default:
+				http.Error(w, "method not allowed", http.StatusNotImplemented)
+			}
+			return
+		}
+	}
+
+	http.Error(w, "no handler for "+route, http.StatusNotImplemented)
+}
+
+func readBody(r *http.Request) map[string]any {
+	body := map[string]any{}
+	data, _ := io.ReadAll(r.Body)
+	_ = json.Unmarshal(data, &body)
+	return body
+}
+
+// newTestClient starts the stand-in and returns a client that is logged in to it
+func newTestClient(t *testing.T, fake *fakeProxmox) *proxmox.Client {
+	t.Helper()
+
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	client, err := proxmox.NewClient(server.URL, "root@pam", "vagrant")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return client
+}
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/clincha-org/proxmox-api/pkg/proxmox"
+)
+
+const NextIDPath string = ClusterPath + "/nextid"
+
+// GetNextVMID returns the lowest VMID that is not used by a virtual machine or container in the cluster.
+// Proxmox does not reserve it, so it can be taken by someone else before it is used.
+func GetNextVMID(client *proxmox.Client) (int64, error) {
+	nextIDModel := NextIDResponse{}
+	err := doRequest(client, "GET", NextIDPath, nil, &nextIDModel)
+	if err != nil {
+		return 0, fmt.Errorf("GetNextVMID: %w", err)
+	}
+
+	return int64(nextIDModel.Data), nil
+}
+
+// IsVMIDFree reports whether the VMID is not used by a virtual machine or container in the cluster
+func IsVMIDFree(client *proxmox.Client, vmid int64) (bool, error) {
+	nextIDModel := NextIDResponse{}
+	err := doRequest(client, "GET", NextIDPath+"?vmid="+strconv.FormatInt(vmid, 10), nil, &nextIDModel)
+
+	// Proxmox rejects a VMID that is in use as an invalid parameter
+	var statusError *StatusError
+	if errors.As(err, &statusError) && statusError.StatusCode == http.StatusBadRequest {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("IsVMIDFree-%d: %w", vmid, err)
+	}
+
+	return true, nil
+}
+
+const ClusterResourcesPath string = ClusterPath + "/resources"
+
+// GetClusterResources lists the resources of every node in the cluster. The type is one of vm, storage, node,
+// sdn or pool, an empty type returns every resource. The vm type includes both virtual machines and containers.
+func GetClusterResources(client *proxmox.Client, resourceType string) ([]ClusterResource, error) {
+	path := ClusterResourcesPath
+	if resourceType != "" {
+		path += "?type=" + url.QueryEscape(resourceType)
+	}
+
+	resourcesModel := ClusterResourcesResponse{}
+	err := doRequest(client, "GET", path, nil, &resourcesModel)
+	if err != nil {
+		return nil, fmt.Errorf("GetClusterResources-%s: %w", resourceType, err)
+	}
+
+	return resourcesModel.Data, nil
+}
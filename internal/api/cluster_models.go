@@ -0,0 +1,40 @@
+package api
+
+// NextIDResponse The response from Proxmox when the next free VMID is returned. Proxmox encodes it as a string.
+type NextIDResponse struct {
+	Data Int `json:"data"`
+}
+
+// ClusterResourcesResponse The response from Proxmox when the resources of the cluster are returned
+type ClusterResourcesResponse struct {
+	Data []ClusterResource `json:"data"`
+}
+
+// ClusterResource A resource in the cluster, for example qemu/100, lxc/101, storage/pve/local or node/pve.
+// Type is qemu, lxc, storage, node, sdn or pool, and which fields are set depends on it. VMID is only set
+// for guests, Storage for storage and SDN for SDN zones.
+type ClusterResource struct {
+	ID         string  `json:"id"`
+	Type       string  `json:"type"`
+	Node       string  `json:"node,omitempty"`
+	Status     string  `json:"status,omitempty"`
+	Name       string  `json:"name,omitempty"`
+	VMID       Int     `json:"vmid,omitempty"`
+	Pool       string  `json:"pool,omitempty"`
+	Tags       string  `json:"tags,omitempty"`
+	Template   Bool    `json:"template,omitempty"`
+	HAState    string  `json:"hastate,omitempty"`
+	Storage    string  `json:"storage,omitempty"`
+	PluginType string  `json:"plugintype,omitempty"`
+	Content    string  `json:"content,omitempty"`
+	Shared     Bool    `json:"shared,omitempty"`
+	SDN        string  `json:"sdn,omitempty"`
+	Level      string  `json:"level,omitempty"`
+	CPU        float64 `json:"cpu,omitempty"`
+	MaxCPU     Int     `json:"maxcpu,omitempty"`
+	Mem        Int     `json:"mem,omitempty"`
+	MaxMem     Int     `json:"maxmem,omitempty"`
+	Disk       Int     `json:"disk,omitempty"`
+	MaxDisk    Int     `json:"maxdisk,omitempty"`
+	Uptime     Int     `json:"uptime,omitempty"`
+}
@@ -0,0 +1,103 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+// handleNextID serves /cluster/nextid for a cluster that uses the VMIDs
+func handleNextID(fake *fakeProxmox, used map[int64]bool) {
+	fake.handle("GET "+NextIDPath, func(w http.ResponseWriter, r *http.Request) {
+		if requested := r.URL.Query().Get("vmid"); requested != "" {
+			vmid, _ := strconv.ParseInt(requested, 10, 64)
+			if used[vmid] {
+				http.Error(w, `{"errors":{"vmid":"VM `+requested+` already exists"},"data":null}`, http.StatusBadRequest)
+				return
+			}
+			writeData(w, requested)
+			return
+		}
+		vmid := int64(100)
+		for used[vmid] {
+			vmid++
+		}
+		writeData(w, strconv.FormatInt(vmid, 10))
+	})
+}
+
+func TestGetNextVMID(t *testing.T) {
+	fake := newFakeProxmox()
+	handleNextID(fake, map[int64]bool{100: true, 101: true, 103: true})
+	client := newTestClient(t, fake)
+
+	vmid, err := GetNextVMID(client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vmid != 102 {
+		t.Errorf("Incorrect next VMID returned: %d", vmid)
+	}
+
+	free, err := IsVMIDFree(client, 103)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if free {
+		t.Errorf("Expected VMID 103 to be in use")
+	}
+	free, err = IsVMIDFree(client, 104)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !free {
+		t.Errorf("Expected VMID 104 to be free")
+	}
+}
+
+func TestGetClusterResources(t *testing.T) {
+	fake := newFakeProxmox()
+	fake.handle("GET "+ClusterResourcesPath, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("type") == "storage" {
+			w.Write([]byte(`{"data":[
+				{"id":"storage/pve/local-lvm","type":"storage","node":"pve","storage":"local-lvm","plugintype":"lvmthin","content":"images,rootdir","shared":0,"status":"available","disk":1073741824,"maxdisk":107374182400}
+			]}`))
+			return
+		}
+		if r.URL.Query().Get("type") != "vm" {
+			http.Error(w, "unexpected type "+r.URL.Query().Get("type"), http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{"data":[
+			{"id":"qemu/100","type":"qemu","node":"pve","vmid":100,"name":"web","status":"running","pool":"prod","tags":"debian;web","template":0,"maxmem":2147483648,"maxdisk":34359738368,"uptime":3600},
+			{"id":"lxc/101","type":"lxc","node":"pve2","vmid":"101","name":"dns","status":"stopped","maxmem":536870912,"maxdisk":8589934592,"uptime":0},
+			{"id":"qemu/9000","type":"qemu","node":"pve","vmid":9000,"name":"debian-template","status":"stopped","template":1}
+		]}`))
+	})
+	client := newTestClient(t, fake)
+
+	resources, err := GetClusterResources(client, "vm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resources) != 3 {
+		t.Fatalf("Expected 3 resources, got %d", len(resources))
+	}
+	if resources[0].VMID != 100 || resources[0].Pool != "prod" || resources[0].Tags != "debian;web" || resources[0].MaxMem != 2147483648 || resources[0].Uptime != 3600 {
+		t.Errorf("Incorrect virtual machine returned: %+v", resources[0])
+	}
+	if resources[1].Type != "lxc" || resources[1].VMID != 101 || resources[1].Node != "pve2" {
+		t.Errorf("Incorrect container returned: %+v", resources[1])
+	}
+	if !resources[2].Template || resources[0].Template {
+		t.Errorf("Incorrect template flags returned: %+v", resources)
+	}
+
+	resources, err = GetClusterResources(client, "storage")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resources) != 1 || resources[0].Storage != "local-lvm" || resources[0].PluginType != "lvmthin" || resources[0].Shared || resources[0].MaxDisk != 107374182400 {
+		t.Errorf("Incorrect storage returned: %+v", resources)
+	}
+}
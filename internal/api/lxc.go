@@ -0,0 +1,172 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/clincha-org/proxmox-api/pkg/proxmox"
+)
+
+const LxcPath string = "/lxc"
+
+func containerPath(node string, vmid int64) string {
+	return proxmox.NodesPath + "/" + url.PathEscape(node) + LxcPath + "/" + strconv.FormatInt(vmid, 10)
+}
+
+func GetContainers(client *proxmox.Client, node string) ([]ContainerStatus, error) {
+	containersModel := ContainersResponse{}
+	err := doRequest(client, "GET", proxmox.NodesPath+"/"+url.PathEscape(node)+LxcPath, nil, &containersModel)
+	if err != nil {
+		return nil, fmt.Errorf("GetContainers-%s: %w", node, err)
+	}
+
+	return containersModel.Data, nil
+}
+
+func GetContainerConfig(client *proxmox.Client, node string, vmid int64) (ContainerConfig, error) {
+	configModel := ContainerConfigResponse{}
+	err := doRequest(client, "GET", containerPath(node, vmid)+"/config", nil, &configModel)
+	if err != nil {
+		return ContainerConfig{}, fmt.Errorf("GetContainerConfig-%s-%d: %w", node, vmid, err)
+	}
+
+	return configModel.Data, nil
+}
+
+// CreateContainer starts the creation of a container from an OS template and returns the UPID of the task
+func CreateContainer(client *proxmox.Client, node string, containerRequest *ContainerRequest) (string, error) {
+	task := TaskResponse{}
+	err := doRequest(client, "POST", proxmox.NodesPath+"/"+url.PathEscape(node)+LxcPath, containerRequest, &task)
+	if err != nil {
+		return "", fmt.Errorf("CreateContainer-%s: %w", node, err)
+	}
+
+	return task.Data, nil
+}
+
+// UpdateContainerConfig changes the configuration of a container. Unlike virtual machines this is not a task,
+// the change has been made when it returns. Changes that cannot be applied to a running container are stored
+// as pending until it is restarted.
+func UpdateContainerConfig(client *proxmox.Client, node string, vmid int64, containerRequest *ContainerRequest) error {
+	err := doRequest(client, "PUT", containerPath(node, vmid)+"/config", containerRequest, nil)
+	if err != nil {
+		return fmt.Errorf("UpdateContainerConfig-%s-%d: %w", node, vmid, err)
+	}
+
+	return nil
+}
+
+// DeleteContainer destroys a stopped container, including its volumes, and returns the UPID of the task
+func DeleteContainer(client *proxmox.Client, node string, vmid int64) (string, error) {
+	task := TaskResponse{}
+	err := doRequest(client, "DELETE", containerPath(node, vmid)+"?purge=1&destroy-unreferenced-disks=1", nil, &task)
+	if err != nil {
+		return "", fmt.Errorf("DeleteContainer-%s-%d: %w", node, vmid, err)
+	}
+
+	return task.Data, nil
+}
+
+func GetContainerStatus(client *proxmox.Client, node string, vmid int64) (ContainerStatus, error) {
+	statusModel := ContainerStatusResponse{}
+	err := doRequest(client, "GET", containerPath(node, vmid)+"/status/current", nil, &statusModel)
+	if err != nil {
+		return ContainerStatus{}, fmt.Errorf("GetContainerStatus-%s-%d: %w", node, vmid, err)
+	}
+
+	return statusModel.Data, nil
+}
+
+// StartContainer starts a container and returns the UPID of the task
+func StartContainer(client *proxmox.Client, node string, vmid int64) (string, error) {
+	task := TaskResponse{}
+	err := doRequest(client, "POST", containerPath(node, vmid)+"/status/start", nil, &task)
+	if err != nil {
+		return "", fmt.Errorf("StartContainer-%s-%d: %w", node, vmid, err)
+	}
+
+	return task.Data, nil
+}
+
+// StopContainer immediately kills the processes of a container and returns the UPID of the task
+func StopContainer(client *proxmox.Client, node string, vmid int64) (string, error) {
+	task := TaskResponse{}
+	err := doRequest(client, "POST", containerPath(node, vmid)+"/status/stop", nil, &task)
+	if err != nil {
+		return "", fmt.Errorf("StopContainer-%s-%d: %w", node, vmid, err)
+	}
+
+	return task.Data, nil
+}
+
+// ShutdownContainer asks the init process of the container to shut down and returns the UPID of the task. The task
+// fails when the container has not stopped after timeout seconds, unless forceStop is set in which case it is stopped.
+func ShutdownContainer(client *proxmox.Client, node string, vmid int64, timeout int64, forceStop bool) (string, error) {
+	task := TaskResponse{}
+	payload := map[string]any{"timeout": timeout, "forceStop": Bool(forceStop)}
+	err := doRequest(client, "POST", containerPath(node, vmid)+"/status/shutdown", payload, &task)
+	if err != nil {
+		return "", fmt.Errorf("ShutdownContainer-%s-%d: %w", node, vmid, err)
+	}
+
+	return task.Data, nil
+}
+
+// GetContainerPending returns the configuration of a container with the changes that are waiting for a restart
+func GetContainerPending(client *proxmox.Client, node string, vmid int64) ([]VirtualMachinePendingChange, error) {
+	pendingModel := VirtualMachinePendingResponse{}
+	err := doRequest(client, "GET", containerPath(node, vmid)+"/pending", nil, &pendingModel)
+	if err != nil {
+		return nil, fmt.Errorf("GetContainerPending-%s-%d: %w", node, vmid, err)
+	}
+
+	return pendingModel.Data, nil
+}
+
+// ResizeContainerDisk grows the root filesystem or a mount point to the given size, for example 16G, and returns
+// the UPID of the task. Proxmox does not support shrinking them.
+func ResizeContainerDisk(client *proxmox.Client, node string, vmid int64, disk string, size string) (string, error) {
+	task := TaskResponse{}
+	payload := map[string]string{"disk": disk, "size": size}
+	err := doRequest(client, "PUT", containerPath(node, vmid)+"/resize", payload, &task)
+	if err != nil {
+		return "", fmt.Errorf("ResizeContainerDisk-%s-%d-%s: %w", node, vmid, disk, err)
+	}
+
+	return task.Data, nil
+}
+
+// MigrateContainer moves a container to another node and returns the UPID of the task.
+// Containers can not be migrated live, a running container has to be migrated with a restart.
+func MigrateContainer(client *proxmox.Client, node string, vmid int64, migrateRequest *ContainerMigrateRequest) (string, error) {
+	task := TaskResponse{}
+	err := doRequest(client, "POST", containerPath(node, vmid)+"/migrate", migrateRequest, &task)
+	if err != nil {
+		return "", fmt.Errorf("MigrateContainer-%s-%d-%s: %w", node, vmid, migrateRequest.Target, err)
+	}
+
+	return task.Data, nil
+}
+
+// CloneContainer copies a container or template to a new VMID and returns the UPID of the task
+func CloneContainer(client *proxmox.Client, node string, vmid int64, cloneRequest *ContainerCloneRequest) (string, error) {
+	task := TaskResponse{}
+	err := doRequest(client, "POST", containerPath(node, vmid)+"/clone", cloneRequest, &task)
+	if err != nil {
+		return "", fmt.Errorf("CloneContainer-%s-%d: %w", node, vmid, err)
+	}
+
+	return task.Data, nil
+}
+
+// ConvertContainerToTemplate turns a stopped container into a template. Unlike virtual machines this is not a task,
+// the container is a template when it returns. This can not be undone, the template has to be destroyed instead.
+func ConvertContainerToTemplate(client *proxmox.Client, node string, vmid int64) error {
+	err := doRequest(client, "POST", containerPath(node, vmid)+"/template", nil, nil)
+	if err != nil {
+		return fmt.Errorf("ConvertContainerToTemplate-%s-%d: %w", node, vmid, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,150 @@
+package api
+
+import (
+	"encoding/json"
+	"regexp"
+)
+
+// ContainersResponse The response from Proxmox when a list of containers on a node is returned
+type ContainersResponse struct {
+	Data []ContainerStatus `json:"data"`
+}
+
+// ContainerConfigResponse The response from Proxmox when the configuration of a container is returned
+type ContainerConfigResponse struct {
+	Data ContainerConfig `json:"data"`
+}
+
+// ContainerStatusResponse The response from Proxmox when the status of a container is returned
+type ContainerStatusResponse struct {
+	Data ContainerStatus `json:"data"`
+}
+
+// containerDeviceKey matches the configuration keys of a container that hold a property string, for example rootfs or net0
+var containerDeviceKey = regexp.MustCompile(`^(rootfs|(mp|net|dev|unused)\d+)$`)
+
+// ContainerRequest The request that Proxmox expects when creating and modifying containers.
+// VMID, OSTemplate, Unprivileged, Password and SSHPublicKeys are only accepted on create.
+// Devices holds entries such as rootfs, mp0 or net0 with their property string.
+type ContainerRequest struct {
+	VMID          *int64            `json:"vmid,omitempty"`
+	OSTemplate    *string           `json:"ostemplate,omitempty"`
+	Hostname      *string           `json:"hostname,omitempty"`
+	Unprivileged  *Bool             `json:"unprivileged,omitempty"`
+	Features      *string           `json:"features,omitempty"`
+	Cores         *int64            `json:"cores,omitempty"`
+	Memory        *int64            `json:"memory,omitempty"`
+	Swap          *int64            `json:"swap,omitempty"`
+	Password      *string           `json:"password,omitempty"`
+	SSHPublicKeys *string           `json:"ssh-public-keys,omitempty"`
+	Startup       *string           `json:"startup,omitempty"`
+	OnBoot        *Bool             `json:"onboot,omitempty"`
+	Tags          *string           `json:"tags,omitempty"`
+	Description   *string           `json:"description,omitempty"`
+	Devices       map[string]string `json:"-"`
+	Delete        *string           `json:"delete,omitempty"`
+}
+
+// MarshalJSON flattens the devices into the request next to the other attributes
+func (r ContainerRequest) MarshalJSON() ([]byte, error) {
+	type alias ContainerRequest
+	data, err := json.Marshal(alias(r))
+	if err != nil {
+		return nil, err
+	}
+	if len(r.Devices) == 0 {
+		return data, nil
+	}
+
+	fields := map[string]any{}
+	err = json.Unmarshal(data, &fields)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range r.Devices {
+		fields[key] = value
+	}
+	return json.Marshal(fields)
+}
+
+// ContainerMigrateRequest The request that Proxmox expects when migrating a container to the Target node.
+// Restart stops a running container, moves it and starts it again on the target, waiting Timeout seconds
+// for it to shut down. Volumes on local storage are moved to TargetStorage when it is set.
+type ContainerMigrateRequest struct {
+	Target        string  `json:"target"`
+	Restart       *Bool   `json:"restart,omitempty"`
+	Timeout       *int64  `json:"timeout,omitempty"`
+	TargetStorage *string `json:"target-storage,omitempty"`
+}
+
+// ContainerCloneRequest The request that Proxmox expects when cloning a container.
+// Target is the node of the new container and Storage can only be set for a full clone.
+type ContainerCloneRequest struct {
+	NewID    int64   `json:"newid"`
+	Hostname *string `json:"hostname,omitempty"`
+	Target   *string `json:"target,omitempty"`
+	Full     *Bool   `json:"full,omitempty"`
+	Storage  *string `json:"storage,omitempty"`
+	Pool     *string `json:"pool,omitempty"`
+}
+
+// ContainerConfig The structure that represents the configuration of a Proxmox container.
+// Attributes left at their default are not returned by Proxmox and will be nil. The OS template, the password
+// and the SSH keys are only used to create the container and are never returned.
+type ContainerConfig struct {
+	Hostname     *string `json:"hostname,omitempty"`
+	OSType       *string `json:"ostype,omitempty"`
+	Arch         *string `json:"arch,omitempty"`
+	Unprivileged *Bool   `json:"unprivileged,omitempty"`
+	Features     *string `json:"features,omitempty"`
+	Cores        *Int    `json:"cores,omitempty"`
+	Memory       *Int    `json:"memory,omitempty"`
+	Swap         *Int    `json:"swap,omitempty"`
+	Startup      *string `json:"startup,omitempty"`
+	OnBoot       *Bool   `json:"onboot,omitempty"`
+	Template     *Bool   `json:"template,omitempty"`
+	Tags         *string `json:"tags,omitempty"`
+	Description  *string `json:"description,omitempty"`
+	Lock         *string `json:"lock,omitempty"`
+	Digest       string  `json:"digest,omitempty"`
+	// Devices holds the root filesystem, mount points and network interfaces, keyed by name
+	Devices map[string]string `json:"-"`
+}
+
+func (c *ContainerConfig) UnmarshalJSON(data []byte) error {
+	type alias ContainerConfig
+	err := json.Unmarshal(data, (*alias)(c))
+	if err != nil {
+		return err
+	}
+
+	fields := map[string]any{}
+	err = json.Unmarshal(data, &fields)
+	if err != nil {
+		return err
+	}
+
+	c.Devices = map[string]string{}
+	for key, value := range fields {
+		if text, ok := value.(string); ok && containerDeviceKey.MatchString(key) {
+			c.Devices[key] = text
+		}
+	}
+	return nil
+}
+
+// ContainerStatus The structure that represents the runtime status of a Proxmox container.
+// Status is either running or stopped.
+type ContainerStatus struct {
+	VMID     Int     `json:"vmid"`
+	Name     string  `json:"name,omitempty"`
+	Status   string  `json:"status"`
+	Lock     string  `json:"lock,omitempty"`
+	Uptime   Int     `json:"uptime,omitempty"`
+	CPU      float64 `json:"cpu,omitempty"`
+	MaxMem   Int     `json:"maxmem,omitempty"`
+	MaxSwap  Int     `json:"maxswap,omitempty"`
+	MaxDisk  Int     `json:"maxdisk,omitempty"`
+	Template *Bool   `json:"template,omitempty"`
+	Tags     string  `json:"tags,omitempty"`
+}
@@ -0,0 +1,370 @@
+package api
+
+import (
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeLxc is a stand-in for the containers on a single node. It keeps the configuration of each
+// container as the flat map that Proxmox returns. Create only keeps the attributes that Proxmox
+// stores, the OS template, password and SSH keys are recorded in created instead.
+type fakeLxc struct {
+	mu       sync.Mutex
+	node     string
+	configs  map[int64]map[string]any
+	statuses map[int64]string
+	// created records the create request of each container
+	created map[int64]map[string]any
+	// pending holds the changes that are waiting for a restart, tests set it directly
+	pending map[int64]map[string]any
+	// migrations records the request of each container that was migrated away from the node
+	migrations map[int64]map[string]any
+}
+
+func newFakeLxc(fake *fakeProxmox, node string) *fakeLxc {
+	lxc := &fakeLxc{node: node, configs: map[int64]map[string]any{}, statuses: map[int64]string{}, created: map[int64]map[string]any{}, pending: map[int64]map[string]any{}, migrations: map[int64]map[string]any{}}
+	fake.handlePrefix("nodes/"+node+"/lxc", lxc.ServeHTTP)
+	return lxc
+}
+
+func (l *fakeLxc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	parts := strings.Split(strings.Trim(strings.SplitN(r.URL.Path, "/lxc", 2)[1], "/"), "/")
+	if parts[0] == "" {
+		switch r.Method {
+		case "GET":
+			list := []map[string]any{}
+			for vmid, config := range l.configs {
+				list = append(list, map[string]any{"vmid": vmid, "name": config["hostname"], "status": l.statuses[vmid], "tags": config["tags"]})
+			}
+			writeData(w, list)
+		case "POST":
+			body := readBody(r)
+			vmid := int64(body["vmid"].(float64))
+			if _, exists := l.configs[vmid]; exists {
+				http.Error(w, "CT "+strconv.FormatInt(vmid, 10)+" already exists", http.StatusInternalServerError)
+				return
+			}
+			l.created[vmid] = body
+			config := map[string]any{}
+			for key, value := range body {
+				switch key {
+				case "vmid", "ostemplate", "password", "ssh-public-keys":
+				case "rootfs":
+					// Proxmox allocates a volume for storage:size and records the size
+					storage, size, _ := strings.Cut(value.(string), ":")
+					config[key] = storage + ":vm-" + strconv.FormatInt(vmid, 10) + "-disk-0,size=" + size + "G"
+				default:
+					config[key] = value
+				}
+			}
+			l.configs[vmid] = config
+			l.statuses[vmid] = "stopped"
+			writeData(w, testUPID)
+		}
+		return
+	}
+
+	vmid, _ := strconv.ParseInt(parts[0], 10, 64)
+	config, exists := l.configs[vmid]
+	if !exists {
+		http.Error(w, "Configuration file 'nodes/"+l.node+"/lxc/"+parts[0]+".conf' does not exist", http.StatusInternalServerError)
+		return
+	}
+
+	route := r.Method + " " + strings.Join(parts[1:], "/")
+	switch route {
+	case "DELETE ":
+		if l.statuses[vmid] != "stopped" {
+			http.Error(w, "CT is running", http.StatusInternalServerError)
+			return
+		}
+		delete(l.configs, vmid)
+		writeData(w, testUPID)
+	case "GET config":
+		writeData(w, config)
+	case "PUT config":
+		body := readBody(r)
+		if deletes, ok := body["delete"].(string); ok {
+			for _, key := range strings.Split(deletes, ",") {
+				delete(config, key)
+			}
+			delete(body, "delete")
+		}
+		for key, value := range body {
+			config[key] = value
+		}
+		writeData(w, nil)
+	case "GET pending":
+		list := []map[string]any{}
+		for key, value := range config {
+			entry := map[string]any{"key": key, "value": value}
+			if pending, ok := l.pending[vmid][key]; ok {
+				entry["pending"] = pending
+			}
+			list = append(list, entry)
+		}
+		writeData(w, list)
+	case "PUT resize":
+		body := readBody(r)
+		disk := body["disk"].(string)
+		properties := ParsePropertyString(config[disk].(string))
+		properties["size"] = body["size"].(string)
+		config[disk] = properties.String()
+		writeData(w, testUPID)
+	case "POST migrate":
+		body := readBody(r)
+		if l.statuses[vmid] == "running" && body["restart"] != float64(1) {
+			http.Error(w, "lxc live migration is currently not implemented", http.StatusInternalServerError)
+			return
+		}
+		delete(l.configs, vmid)
+		delete(l.statuses, vmid)
+		l.migrations[vmid] = body
+		writeData(w, testUPID)
+	case "POST clone":
+		body := readBody(r)
+		newID := int64(body["newid"].(float64))
+		if _, exists := l.configs[newID]; exists {
+			http.Error(w, "CT "+strconv.FormatInt(newID, 10)+" already exists", http.StatusInternalServerError)
+			return
+		}
+		if config["template"] == nil && body["full"] == float64(0) {
+			http.Error(w, "linked clone feature is not supported for a container that is not a template", http.StatusInternalServerError)
+			return
+		}
+		clone := map[string]any{}
+		for key, value := range config {
+			if key != "template" {
+				clone[key] = value
+			}
+		}
+		if hostname, ok := body["hostname"]; ok {
+			clone["hostname"] = hostname
+		}
+		l.configs[newID] = clone
+		l.statuses[newID] = "stopped"
+		writeData(w, testUPID)
+	case "POST template":
+		if l.statuses[vmid] != "stopped" {
+			http.Error(w, "you can't convert a CT to template if the CT is running", http.StatusInternalServerError)
+			return
+		}
+		config["template"] = 1
+		writeData(w, nil)
+	case "GET status/current":
+		writeData(w, map[string]any{"vmid": vmid, "status": l.statuses[vmid], "name": config["hostname"]})
+	case "POST status/start":
+		l.statuses[vmid] = "running"
+		writeData(w, testUPID)
+	case "POST status/stop", "POST status/shutdown":
+		l.statuses[vmid] = "stopped"
+		writeData(w, testUPID)
+	default:
+		http.Error(w, "no handler for "+route, http.StatusNotImplemented)
+	}
+}
+
+func TestContainerLifecycle(t *testing.T) {
+	fake := newFakeProxmox()
+	lxc := newFakeLxc(fake, "pve")
+	client := newTestClient(t, fake)
+
+	vmid := int64(200)
+	hostname := "web"
+	template := "local:vztmpl/debian-12-standard_12.7-1_amd64.tar.zst"
+	memory := int64(1024)
+	password := "secret"
+	unprivileged := Bool(true)
+	upid, err := CreateContainer(client, "pve", &ContainerRequest{
+		VMID:         &vmid,
+		OSTemplate:   &template,
+		Hostname:     &hostname,
+		Unprivileged: &unprivileged,
+		Memory:       &memory,
+		Password:     &password,
+		Devices:      map[string]string{"rootfs": "local-lvm:8", "net0": "name=eth0,bridge=vmbr0,ip=dhcp"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = WaitForTask(client, upid, TaskPollInterval)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lxc.created[vmid]["ostemplate"] != template || lxc.created[vmid]["password"] != password {
+		t.Errorf("Incorrect create request: %v", lxc.created[vmid])
+	}
+
+	// Proxmox returns the memory as a string, make sure that is handled
+	lxc.configs[vmid]["memory"] = "1024"
+
+	config, err := GetContainerConfig(client, "pve", vmid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *config.Hostname != "web" || *config.Memory.Pointer() != 1024 || !bool(*config.Unprivileged) {
+		t.Errorf("Incorrect config returned: %+v", config)
+	}
+	expectedDevices := map[string]string{"rootfs": "local-lvm:vm-200-disk-0,size=8G", "net0": "name=eth0,bridge=vmbr0,ip=dhcp"}
+	if !reflect.DeepEqual(config.Devices, expectedDevices) {
+		t.Errorf("Incorrect devices returned. Expected %v, got %v", expectedDevices, config.Devices)
+	}
+
+	cores := int64(2)
+	remove := "net0"
+	err = UpdateContainerConfig(client, "pve", vmid, &ContainerRequest{Cores: &cores, Delete: &remove})
+	if err != nil {
+		t.Fatal(err)
+	}
+	config, err = GetContainerConfig(client, "pve", vmid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *config.Cores.Pointer() != 2 || config.Devices["net0"] != "" {
+		t.Errorf("Expected cores to be 2 and net0 to be removed, got %+v", config)
+	}
+
+	lxc.pending[vmid] = map[string]any{"hostname": "web2"}
+	changes, err := GetContainerPending(client, "pve", vmid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, change := range changes {
+		if change.IsPending() != (change.Key == "hostname") {
+			t.Errorf("Incorrect pending change returned: %+v", change)
+		}
+	}
+
+	_, err = ResizeContainerDisk(client, "pve", vmid, "rootfs", "16G")
+	if err != nil {
+		t.Fatal(err)
+	}
+	config, err = GetContainerConfig(client, "pve", vmid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Devices["rootfs"] != "local-lvm:vm-200-disk-0,size=16G" {
+		t.Errorf("Incorrect rootfs after resize. Expected size=16G, got %v", config.Devices["rootfs"])
+	}
+
+	_, err = StartContainer(client, "pve", vmid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = DeleteContainer(client, "pve", vmid)
+	if err == nil {
+		t.Errorf("Expected an error when a running container is deleted")
+	}
+	_, err = ShutdownContainer(client, "pve", vmid, 60, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	status, err := GetContainerStatus(client, "pve", vmid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Status != "stopped" {
+		t.Errorf("Incorrect status returned. Expected stopped, got %v", status.Status)
+	}
+
+	_, err = DeleteContainer(client, "pve", vmid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = GetContainerConfig(client, "pve", vmid)
+	if err == nil {
+		t.Errorf("Expected an error when reading a deleted container")
+	}
+}
+
+func TestContainerRequestMarshal(t *testing.T) {
+	keys := "ssh-ed25519 AAAA user@host"
+	data, err := ContainerRequest{SSHPublicKeys: &keys, Devices: map[string]string{"mp0": "local-lvm:4,mp=/data"}}.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"mp0":"local-lvm:4,mp=/data","ssh-public-keys":"ssh-ed25519 AAAA user@host"}`
+	if string(data) != expected {
+		t.Errorf("Incorrect request. Expected %s, got %s", expected, data)
+	}
+}
+
+func TestMigrateContainer(t *testing.T) {
+	fake := newFakeProxmox()
+	lxc := newFakeLxc(fake, "pve")
+	client := newTestClient(t, fake)
+
+	lxc.configs[200] = map[string]any{"hostname": "web"}
+	lxc.statuses[200] = "running"
+
+	_, err := MigrateContainer(client, "pve", 200, &ContainerMigrateRequest{Target: "pve2"})
+	if err == nil {
+		t.Errorf("Expected an error when a running container is migrated without a restart")
+	}
+
+	restart := Bool(true)
+	timeout := int64(120)
+	targetStorage := "local-zfs"
+	_, err = MigrateContainer(client, "pve", 200, &ContainerMigrateRequest{Target: "pve2", Restart: &restart, Timeout: &timeout, TargetStorage: &targetStorage})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]any{"target": "pve2", "restart": float64(1), "timeout": float64(120), "target-storage": "local-zfs"}
+	if !reflect.DeepEqual(lxc.migrations[200], expected) {
+		t.Errorf("Incorrect migration request. Expected %v, got %v", expected, lxc.migrations[200])
+	}
+	if _, exists := lxc.configs[200]; exists {
+		t.Errorf("Expected the container to have left the node")
+	}
+}
+
+func TestCloneContainer(t *testing.T) {
+	fake := newFakeProxmox()
+	lxc := newFakeLxc(fake, "pve")
+	client := newTestClient(t, fake)
+
+	lxc.configs[200] = map[string]any{"hostname": "debian", "rootfs": "local-lvm:vm-200-disk-0,size=8G"}
+	lxc.statuses[200] = "stopped"
+
+	full := false
+	_, err := CloneContainer(client, "pve", 200, &ContainerCloneRequest{NewID: 201, Full: NewBool(&full)})
+	if err == nil {
+		t.Errorf("Expected an error for a linked clone of a container that is not a template")
+	}
+
+	err = ConvertContainerToTemplate(client, "pve", 200)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hostname := "web"
+	upid, err := CloneContainer(client, "pve", 200, &ContainerCloneRequest{NewID: 201, Hostname: &hostname, Full: NewBool(&full)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = WaitForTask(client, upid, TaskPollInterval)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template, err := GetContainerConfig(client, "pve", 200)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if template.Template == nil || !bool(*template.Template) {
+		t.Errorf("Expected the container to be a template: %+v", template)
+	}
+	config, err := GetContainerConfig(client, "pve", 201)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *config.Hostname != "web" || config.Template != nil || config.Devices["rootfs"] == "" {
+		t.Errorf("Incorrect clone returned: %+v", config)
+	}
+}
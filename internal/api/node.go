@@ -0,0 +1,20 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/clincha-org/proxmox-api/pkg/proxmox"
+)
+
+const StatusPath string = "/status"
+
+func GetNodeStatus(client *proxmox.Client, node string) (NodeStatus, error) {
+	statusModel := NodeStatusResponse{}
+	err := doRequest(client, "GET", proxmox.NodesPath+"/"+url.PathEscape(node)+StatusPath, nil, &statusModel)
+	if err != nil {
+		return NodeStatus{}, fmt.Errorf("GetNodeStatus-%s: %w", node, err)
+	}
+
+	return statusModel.Data, nil
+}
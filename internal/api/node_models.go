@@ -0,0 +1,57 @@
+package api
+
+// NodeStatusResponse The response from Proxmox when the detailed status of a node is returned
+type NodeStatusResponse struct {
+	Data NodeStatus `json:"data"`
+}
+
+// NodeStatus The structure that represents the detailed status of a Proxmox node.
+// PVEVersion is in the form pve-manager/8.2.4/faa83925c9641325.
+type NodeStatus struct {
+	Uptime        Int               `json:"uptime"`
+	CPU           float64           `json:"cpu"`
+	Wait          float64           `json:"wait"`
+	KernelVersion string            `json:"kversion"`
+	PVEVersion    string            `json:"pveversion"`
+	CPUInfo       NodeCPUInfo       `json:"cpuinfo"`
+	LoadAverage   []string          `json:"loadavg"`
+	Memory        NodeUsage         `json:"memory"`
+	Swap          NodeUsage         `json:"swap"`
+	RootFS        NodeUsage         `json:"rootfs"`
+	BootInfo      NodeBootInfo      `json:"boot-info"`
+	KSM           NodeKSM           `json:"ksm"`
+	CurrentKernel NodeCurrentKernel `json:"current-kernel"`
+}
+
+type NodeCPUInfo struct {
+	Model   string `json:"model"`
+	Sockets Int    `json:"sockets"`
+	Cores   Int    `json:"cores"`
+	CPUs    Int    `json:"cpus"`
+	MHz     string `json:"mhz"`
+}
+
+// NodeUsage Memory, swap and rootfs usage in bytes. Only the rootfs reports Available.
+type NodeUsage struct {
+	Total     Int `json:"total"`
+	Used      Int `json:"used"`
+	Free      Int `json:"free"`
+	Available Int `json:"avail"`
+}
+
+// NodeBootInfo Mode is either efi or legacy-bios
+type NodeBootInfo struct {
+	Mode       string `json:"mode"`
+	SecureBoot Bool   `json:"secureboot"`
+}
+
+type NodeKSM struct {
+	Shared Int `json:"shared"`
+}
+
+type NodeCurrentKernel struct {
+	SystemName string `json:"sysname"`
+	Release    string `json:"release"`
+	Version    string `json:"version"`
+	Machine    string `json:"machine"`
+}
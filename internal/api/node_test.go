@@ -0,0 +1,47 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestGetNodeStatus(t *testing.T) {
+	fake := newFakeProxmox()
+	fake.handle("GET nodes/pve/status", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":{
+			"uptime":3600,"cpu":0.05,"wait":0,
+			"kversion":"Linux 6.8.12-1-pve #1 SMP PREEMPT_DYNAMIC PMX 6.8.12-1",
+			"pveversion":"pve-manager/8.2.4/faa83925c9641325",
+			"cpuinfo":{"model":"AMD EPYC 7302P 16-Core Processor","sockets":1,"cores":16,"cpus":32,"mhz":"3000.000"},
+			"loadavg":["0.10","0.20","0.30"],
+			"memory":{"total":68719476736,"used":17179869184,"free":51539607552},
+			"swap":{"total":8589934592,"used":0,"free":8589934592},
+			"rootfs":{"total":100000000000,"used":25000000000,"free":75000000000,"avail":70000000000},
+			"boot-info":{"mode":"efi","secureboot":1},
+			"ksm":{"shared":1048576},
+			"current-kernel":{"sysname":"Linux","release":"6.8.12-1-pve","version":"#1 SMP","machine":"x86_64"}
+		}}`))
+	})
+	client := newTestClient(t, fake)
+
+	status, err := GetNodeStatus(client, "pve")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if status.PVEVersion != "pve-manager/8.2.4/faa83925c9641325" {
+		t.Errorf("Incorrect PVE version returned: %v", status.PVEVersion)
+	}
+	if status.CPUInfo.Cores != 16 || status.CPUInfo.CPUs != 32 {
+		t.Errorf("Incorrect CPU info returned: %+v", status.CPUInfo)
+	}
+	if status.RootFS.Available != 70000000000 {
+		t.Errorf("Incorrect rootfs available returned: %v", status.RootFS.Available)
+	}
+	if status.BootInfo.Mode != "efi" || !bool(status.BootInfo.SecureBoot) {
+		t.Errorf("Incorrect boot info returned: %+v", status.BootInfo)
+	}
+	if len(status.LoadAverage) != 3 {
+		t.Errorf("Expected 3 load averages, got %d", len(status.LoadAverage))
+	}
+}
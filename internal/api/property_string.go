@@ -0,0 +1,68 @@
+package api
+
+import (
+	"sort"
+	"strings"
+)
+
+// Proxmox encodes devices such as disks and network interfaces as property strings, for example
+// local-lvm:vm-100-disk-0,cache=writeback,size=32G. The first value may be positional, which is
+// stored under the empty key.
+
+// PropertyString The parsed form of a Proxmox property string
+type PropertyString map[string]string
+
+func ParsePropertyString(value string) PropertyString {
+	properties := PropertyString{}
+	for i, part := range strings.Split(value, ",") {
+		if part == "" {
+			continue
+		}
+		key, propertyValue, found := strings.Cut(part, "=")
+		if !found && i == 0 {
+			properties[""] = part
+			continue
+		}
+		properties[key] = propertyValue
+	}
+	return properties
+}
+
+// String formats the properties with the positional value first and the other keys in order
+// so that the same properties always produce the same string
+func (p PropertyString) String() string {
+	var keys []string
+	for key := range p {
+		if key != "" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var parts []string
+	if positional, ok := p[""]; ok {
+		parts = append(parts, positional)
+	}
+	for _, key := range keys {
+		parts = append(parts, key+"="+p[key])
+	}
+	return strings.Join(parts, ",")
+}
+
+// Flag returns whether a boolean property is enabled. Proxmox uses 1/0 and on/off interchangeably.
+func (p PropertyString) Flag(key string, defaultValue bool) bool {
+	value, ok := p[key]
+	if !ok {
+		return defaultValue
+	}
+	return value == "1" || value == "on" || value == "true" || value == "yes"
+}
+
+// SetFlag stores a boolean property as 1 or 0
+func (p PropertyString) SetFlag(key string, value bool) {
+	if value {
+		p[key] = "1"
+	} else {
+		p[key] = "0"
+	}
+}
@@ -0,0 +1,29 @@
+package api
+
+import "testing"
+
+func TestParsePropertyString(t *testing.T) {
+	properties := ParsePropertyString("local-lvm:vm-100-disk-0,cache=writeback,discard=on,size=32G")
+
+	if properties[""] != "local-lvm:vm-100-disk-0" {
+		t.Errorf("Incorrect volume returned: %v", properties[""])
+	}
+	if properties["cache"] != "writeback" || properties["size"] != "32G" {
+		t.Errorf("Incorrect properties returned: %v", properties)
+	}
+	if !properties.Flag("discard", false) || properties.Flag("ssd", false) || !properties.Flag("backup", true) {
+		t.Errorf("Incorrect flags returned: %v", properties)
+	}
+
+	if properties.String() != "local-lvm:vm-100-disk-0,cache=writeback,discard=on,size=32G" {
+		t.Errorf("Incorrect property string returned: %v", properties.String())
+	}
+
+	network := ParsePropertyString("virtio=BC:24:11:00:00:01,bridge=vmbr0,firewall=1")
+	if network["virtio"] != "BC:24:11:00:00:01" || network["bridge"] != "vmbr0" {
+		t.Errorf("Incorrect network properties returned: %v", network)
+	}
+	if _, ok := network[""]; ok {
+		t.Errorf("Expected no positional value for a network property string")
+	}
+}
@@ -0,0 +1,264 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/clincha-org/proxmox-api/pkg/proxmox"
+)
+
+const QemuPath string = "/qemu"
+
+func virtualMachinePath(node string, vmid int64) string {
+	return proxmox.NodesPath + "/" + url.PathEscape(node) + QemuPath + "/" + strconv.FormatInt(vmid, 10)
+}
+
+func GetVirtualMachines(client *proxmox.Client, node string) ([]VirtualMachineStatus, error) {
+	vmModel := VirtualMachinesResponse{}
+	err := doRequest(client, "GET", proxmox.NodesPath+"/"+url.PathEscape(node)+QemuPath, nil, &vmModel)
+	if err != nil {
+		return nil, fmt.Errorf("GetVirtualMachines-%s: %w", node, err)
+	}
+
+	return vmModel.Data, nil
+}
+
+func GetVirtualMachineConfig(client *proxmox.Client, node string, vmid int64) (VirtualMachineConfig, error) {
+	configModel := VirtualMachineConfigResponse{}
+	err := doRequest(client, "GET", virtualMachinePath(node, vmid)+"/config", nil, &configModel)
+	if err != nil {
+		return VirtualMachineConfig{}, fmt.Errorf("GetVirtualMachineConfig-%s-%d: %w", node, vmid, err)
+	}
+
+	return configModel.Data, nil
+}
+
+// CreateVirtualMachine starts the creation of a virtual machine and returns the UPID of the task
+func CreateVirtualMachine(client *proxmox.Client, node string, vmRequest *VirtualMachineRequest) (string, error) {
+	task := TaskResponse{}
+	err := doRequest(client, "POST", proxmox.NodesPath+"/"+url.PathEscape(node)+QemuPath, vmRequest, &task)
+	if err != nil {
+		return "", fmt.Errorf("CreateVirtualMachine-%s: %w", node, err)
+	}
+
+	return task.Data, nil
+}
+
+// UpdateVirtualMachineConfig changes the configuration of a virtual machine and returns the UPID of the task.
+// Changes that cannot be hot-plugged are stored as pending until the virtual machine is restarted.
+func UpdateVirtualMachineConfig(client *proxmox.Client, node string, vmid int64, vmRequest *VirtualMachineRequest) (string, error) {
+	task := TaskResponse{}
+	err := doRequest(client, "POST", virtualMachinePath(node, vmid)+"/config", vmRequest, &task)
+	if err != nil {
+		return "", fmt.Errorf("UpdateVirtualMachineConfig-%s-%d: %w", node, vmid, err)
+	}
+
+	return task.Data, nil
+}
+
+// DeleteVirtualMachine destroys a stopped virtual machine, including its disks, and returns the UPID of the task
+func DeleteVirtualMachine(client *proxmox.Client, node string, vmid int64) (string, error) {
+	task := TaskResponse{}
+	err := doRequest(client, "DELETE", virtualMachinePath(node, vmid)+"?purge=1&destroy-unreferenced-disks=1", nil, &task)
+	if err != nil {
+		return "", fmt.Errorf("DeleteVirtualMachine-%s-%d: %w", node, vmid, err)
+	}
+
+	return task.Data, nil
+}
+
+func GetVirtualMachineStatus(client *proxmox.Client, node string, vmid int64) (VirtualMachineStatus, error) {
+	statusModel := VirtualMachineStatusResponse{}
+	err := doRequest(client, "GET", virtualMachinePath(node, vmid)+"/status/current", nil, &statusModel)
+	if err != nil {
+		return VirtualMachineStatus{}, fmt.Errorf("GetVirtualMachineStatus-%s-%d: %w", node, vmid, err)
+	}
+
+	return statusModel.Data, nil
+}
+
+// StopVirtualMachine immediately stops a virtual machine, like pulling the power cable, and returns the UPID of the task
+func StopVirtualMachine(client *proxmox.Client, node string, vmid int64) (string, error) {
+	task := TaskResponse{}
+	err := doRequest(client, "POST", virtualMachinePath(node, vmid)+"/status/stop", nil, &task)
+	if err != nil {
+		return "", fmt.Errorf("StopVirtualMachine-%s-%d: %w", node, vmid, err)
+	}
+
+	return task.Data, nil
+}
+
+// StartVirtualMachine starts a virtual machine and returns the UPID of the task
+func StartVirtualMachine(client *proxmox.Client, node string, vmid int64) (string, error) {
+	task := TaskResponse{}
+	err := doRequest(client, "POST", virtualMachinePath(node, vmid)+"/status/start", nil, &task)
+	if err != nil {
+		return "", fmt.Errorf("StartVirtualMachine-%s-%d: %w", node, vmid, err)
+	}
+
+	return task.Data, nil
+}
+
+// ShutdownVirtualMachine asks the guest to shut down and returns the UPID of the task. The task fails when the
+// guest has not stopped after timeout seconds, unless forceStop is set in which case the virtual machine is stopped.
+func ShutdownVirtualMachine(client *proxmox.Client, node string, vmid int64, timeout int64, forceStop bool) (string, error) {
+	task := TaskResponse{}
+	payload := map[string]any{"timeout": timeout, "forceStop": Bool(forceStop)}
+	err := doRequest(client, "POST", virtualMachinePath(node, vmid)+"/status/shutdown", payload, &task)
+	if err != nil {
+		return "", fmt.Errorf("ShutdownVirtualMachine-%s-%d: %w", node, vmid, err)
+	}
+
+	return task.Data, nil
+}
+
+// ConvertToTemplate turns a stopped virtual machine into a template and returns the UPID of the task.
+// This can not be undone, the template has to be destroyed instead.
+func ConvertToTemplate(client *proxmox.Client, node string, vmid int64) (string, error) {
+	task := TaskResponse{}
+	err := doRequest(client, "POST", virtualMachinePath(node, vmid)+"/template", nil, &task)
+	if err != nil {
+		return "", fmt.Errorf("ConvertToTemplate-%s-%d: %w", node, vmid, err)
+	}
+
+	return task.Data, nil
+}
+
+// ResizeVirtualMachineDisk grows a disk to the given size, for example 32G, and returns the UPID of the task.
+// Proxmox does not support shrinking disks.
+func ResizeVirtualMachineDisk(client *proxmox.Client, node string, vmid int64, disk string, size string) (string, error) {
+	task := TaskResponse{}
+	payload := map[string]string{"disk": disk, "size": size}
+	err := doRequest(client, "PUT", virtualMachinePath(node, vmid)+"/resize", payload, &task)
+	if err != nil {
+		return "", fmt.Errorf("ResizeVirtualMachineDisk-%s-%d-%s: %w", node, vmid, disk, err)
+	}
+
+	return task.Data, nil
+}
+
+// MoveVirtualMachineDisk moves a disk to another storage and removes the source volume.
+// It returns the UPID of the task.
+func MoveVirtualMachineDisk(client *proxmox.Client, node string, vmid int64, disk string, storage string) (string, error) {
+	task := TaskResponse{}
+	payload := map[string]any{"disk": disk, "storage": storage, "delete": Bool(true)}
+	err := doRequest(client, "POST", virtualMachinePath(node, vmid)+"/move_disk", payload, &task)
+	if err != nil {
+		return "", fmt.Errorf("MoveVirtualMachineDisk-%s-%d-%s: %w", node, vmid, disk, err)
+	}
+
+	return task.Data, nil
+}
+
+// GetVirtualMachinePending returns the configuration of a virtual machine with the changes that are waiting for a restart
+func GetVirtualMachinePending(client *proxmox.Client, node string, vmid int64) ([]VirtualMachinePendingChange, error) {
+	pendingModel := VirtualMachinePendingResponse{}
+	err := doRequest(client, "GET", virtualMachinePath(node, vmid)+"/pending", nil, &pendingModel)
+	if err != nil {
+		return nil, fmt.Errorf("GetVirtualMachinePending-%s-%d: %w", node, vmid, err)
+	}
+
+	return pendingModel.Data, nil
+}
+
+// CloneVirtualMachine copies a virtual machine or template to a new VMID and returns the UPID of the task
+func CloneVirtualMachine(client *proxmox.Client, node string, vmid int64, cloneRequest *VirtualMachineCloneRequest) (string, error) {
+	task := TaskResponse{}
+	err := doRequest(client, "POST", virtualMachinePath(node, vmid)+"/clone", cloneRequest, &task)
+	if err != nil {
+		return "", fmt.Errorf("CloneVirtualMachine-%s-%d: %w", node, vmid, err)
+	}
+
+	return task.Data, nil
+}
+
+// MigrateVirtualMachine moves a virtual machine to another node and returns the UPID of the task.
+// A running virtual machine can only be migrated online.
+func MigrateVirtualMachine(client *proxmox.Client, node string, vmid int64, migrateRequest *VirtualMachineMigrateRequest) (string, error) {
+	task := TaskResponse{}
+	err := doRequest(client, "POST", virtualMachinePath(node, vmid)+"/migrate", migrateRequest, &task)
+	if err != nil {
+		return "", fmt.Errorf("MigrateVirtualMachine-%s-%d-%s: %w", node, vmid, migrateRequest.Target, err)
+	}
+
+	return task.Data, nil
+}
+
+// RegenerateCloudInit rebuilds the cloud-init drive of a virtual machine from its configuration
+func RegenerateCloudInit(client *proxmox.Client, node string, vmid int64) error {
+	err := doRequest(client, "PUT", virtualMachinePath(node, vmid)+"/cloudinit", nil, nil)
+	if err != nil {
+		return fmt.Errorf("RegenerateCloudInit-%s-%d: %w", node, vmid, err)
+	}
+
+	return nil
+}
+
+// GetVirtualMachineNetworkInterfaces asks the QEMU guest agent for the network interfaces of the guest.
+// It fails when the virtual machine is not running or the agent is not responding.
+func GetVirtualMachineNetworkInterfaces(client *proxmox.Client, node string, vmid int64) ([]GuestNetworkInterface, error) {
+	interfacesModel := GuestNetworkInterfacesResponse{}
+	err := doRequest(client, "GET", virtualMachinePath(node, vmid)+"/agent/network-get-interfaces", nil, &interfacesModel)
+	if err != nil {
+		return nil, fmt.Errorf("GetVirtualMachineNetworkInterfaces-%s-%d: %w", node, vmid, err)
+	}
+
+	return interfacesModel.Data.Result, nil
+}
+
+// GetVirtualMachineSnapshots lists the snapshots of a virtual machine. The list always ends with an entry named
+// current, which is the running state that the newest snapshot is the parent of.
+func GetVirtualMachineSnapshots(client *proxmox.Client, node string, vmid int64) ([]VirtualMachineSnapshot, error) {
+	snapshotsModel := VirtualMachineSnapshotsResponse{}
+	err := doRequest(client, "GET", virtualMachinePath(node, vmid)+"/snapshot", nil, &snapshotsModel)
+	if err != nil {
+		return nil, fmt.Errorf("GetVirtualMachineSnapshots-%s-%d: %w", node, vmid, err)
+	}
+
+	return snapshotsModel.Data, nil
+}
+
+// CreateVirtualMachineSnapshot takes a snapshot of a virtual machine and returns the UPID of the task
+func CreateVirtualMachineSnapshot(client *proxmox.Client, node string, vmid int64, snapshotRequest *VirtualMachineSnapshotRequest) (string, error) {
+	task := TaskResponse{}
+	err := doRequest(client, "POST", virtualMachinePath(node, vmid)+"/snapshot", snapshotRequest, &task)
+	if err != nil {
+		return "", fmt.Errorf("CreateVirtualMachineSnapshot-%s-%d-%s: %w", node, vmid, snapshotRequest.Name, err)
+	}
+
+	return task.Data, nil
+}
+
+// UpdateVirtualMachineSnapshot changes the description of a snapshot, which is the only thing that can be changed
+func UpdateVirtualMachineSnapshot(client *proxmox.Client, node string, vmid int64, name string, description string) error {
+	payload := map[string]any{"description": description}
+	err := doRequest(client, "PUT", virtualMachinePath(node, vmid)+"/snapshot/"+url.PathEscape(name)+"/config", payload, nil)
+	if err != nil {
+		return fmt.Errorf("UpdateVirtualMachineSnapshot-%s-%d-%s: %w", node, vmid, name, err)
+	}
+
+	return nil
+}
+
+// DeleteVirtualMachineSnapshot removes a snapshot and returns the UPID of the task
+func DeleteVirtualMachineSnapshot(client *proxmox.Client, node string, vmid int64, name string) (string, error) {
+	task := TaskResponse{}
+	err := doRequest(client, "DELETE", virtualMachinePath(node, vmid)+"/snapshot/"+url.PathEscape(name), nil, &task)
+	if err != nil {
+		return "", fmt.Errorf("DeleteVirtualMachineSnapshot-%s-%d-%s: %w", node, vmid, name, err)
+	}
+
+	return task.Data, nil
+}
+
+// RollbackVirtualMachineSnapshot returns a virtual machine to the state of a snapshot and returns the UPID of the task
+func RollbackVirtualMachineSnapshot(client *proxmox.Client, node string, vmid int64, name string) (string, error) {
+	task := TaskResponse{}
+	err := doRequest(client, "POST", virtualMachinePath(node, vmid)+"/snapshot/"+url.PathEscape(name)+"/rollback", nil, &task)
+	if err != nil {
+		return "", fmt.Errorf("RollbackVirtualMachineSnapshot-%s-%d-%s: %w", node, vmid, name, err)
+	}
+
+	return task.Data, nil
+}
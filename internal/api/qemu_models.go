@@ -0,0 +1,225 @@
+package api
+
+import (
+	"encoding/json"
+	"regexp"
+)
+
+// VirtualMachinesResponse The response from Proxmox when a list of virtual machines on a node is returned
+type VirtualMachinesResponse struct {
+	Data []VirtualMachineStatus `json:"data"`
+}
+
+// VirtualMachineConfigResponse The response from Proxmox when the configuration of a virtual machine is returned
+type VirtualMachineConfigResponse struct {
+	Data VirtualMachineConfig `json:"data"`
+}
+
+// VirtualMachineStatusResponse The response from Proxmox when the status of a virtual machine is returned
+type VirtualMachineStatusResponse struct {
+	Data VirtualMachineStatus `json:"data"`
+}
+
+// VirtualMachinePendingResponse The response from Proxmox when the pending configuration of a virtual machine is returned
+type VirtualMachinePendingResponse struct {
+	Data []VirtualMachinePendingChange `json:"data"`
+}
+
+// GuestNetworkInterfacesResponse The response from Proxmox when the guest agent returns the network interfaces
+type GuestNetworkInterfacesResponse struct {
+	Data struct {
+		Result []GuestNetworkInterface `json:"result"`
+	} `json:"data"`
+}
+
+// VirtualMachineSnapshotsResponse The response from Proxmox when the snapshots of a virtual machine are returned
+type VirtualMachineSnapshotsResponse struct {
+	Data []VirtualMachineSnapshot `json:"data"`
+}
+
+// deviceKey matches the configuration keys that hold a device property string, for example scsi0 or net1
+var deviceKey = regexp.MustCompile(`^((scsi|virtio|sata|ide|net|hostpci|usb|serial|ipconfig|unused)\d+|efidisk0|tpmstate0)$`)
+
+// VirtualMachineRequest The request that Proxmox expects when creating and modifying virtual machines.
+// VMID is only sent on create. Devices holds entries such as scsi0 or net0 with their property string.
+// SSHKeys must be URL encoded.
+type VirtualMachineRequest struct {
+	VMID         *int64            `json:"vmid,omitempty"`
+	Name         *string           `json:"name,omitempty"`
+	Cores        *int64            `json:"cores,omitempty"`
+	Sockets      *int64            `json:"sockets,omitempty"`
+	Memory       *int64            `json:"memory,omitempty"`
+	Balloon      *int64            `json:"balloon,omitempty"`
+	CPU          *string           `json:"cpu,omitempty"`
+	Machine      *string           `json:"machine,omitempty"`
+	BIOS         *string           `json:"bios,omitempty"`
+	Boot         *string           `json:"boot,omitempty"`
+	OSType       *string           `json:"ostype,omitempty"`
+	Tags         *string           `json:"tags,omitempty"`
+	Description  *string           `json:"description,omitempty"`
+	OnBoot       *Bool             `json:"onboot,omitempty"`
+	Agent        *string           `json:"agent,omitempty"`
+	CIUser       *string           `json:"ciuser,omitempty"`
+	CIPassword   *string           `json:"cipassword,omitempty"`
+	SSHKeys      *string           `json:"sshkeys,omitempty"`
+	Nameserver   *string           `json:"nameserver,omitempty"`
+	Searchdomain *string           `json:"searchdomain,omitempty"`
+	CICustom     *string           `json:"cicustom,omitempty"`
+	Devices      map[string]string `json:"-"`
+	Delete       *string           `json:"delete,omitempty"`
+}
+
+// MarshalJSON flattens the devices into the request next to the other attributes
+func (r VirtualMachineRequest) MarshalJSON() ([]byte, error) {
+	type alias VirtualMachineRequest
+	data, err := json.Marshal(alias(r))
+	if err != nil {
+		return nil, err
+	}
+	if len(r.Devices) == 0 {
+		return data, nil
+	}
+
+	fields := map[string]any{}
+	err = json.Unmarshal(data, &fields)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range r.Devices {
+		fields[key] = value
+	}
+	return json.Marshal(fields)
+}
+
+// VirtualMachineCloneRequest The request that Proxmox expects when cloning a virtual machine.
+// Target is the node of the new virtual machine and Storage can only be set for a full clone.
+type VirtualMachineCloneRequest struct {
+	NewID   int64   `json:"newid"`
+	Name    *string `json:"name,omitempty"`
+	Target  *string `json:"target,omitempty"`
+	Full    *Bool   `json:"full,omitempty"`
+	Storage *string `json:"storage,omitempty"`
+	Pool    *string `json:"pool,omitempty"`
+}
+
+// VirtualMachineMigrateRequest The request that Proxmox expects when migrating a virtual machine to the Target node.
+// WithLocalDisks copies disks on local storage to the target, to TargetStorage when it is set.
+type VirtualMachineMigrateRequest struct {
+	Target         string  `json:"target"`
+	Online         *Bool   `json:"online,omitempty"`
+	WithLocalDisks *Bool   `json:"with-local-disks,omitempty"`
+	TargetStorage  *string `json:"targetstorage,omitempty"`
+}
+
+// VirtualMachineConfig The structure that represents the configuration of a Proxmox virtual machine.
+// Attributes left at their default are not returned by Proxmox and will be nil.
+type VirtualMachineConfig struct {
+	Name         *string `json:"name,omitempty"`
+	Cores        *Int    `json:"cores,omitempty"`
+	Sockets      *Int    `json:"sockets,omitempty"`
+	Memory       *Int    `json:"memory,omitempty"`
+	Balloon      *Int    `json:"balloon,omitempty"`
+	CPU          *string `json:"cpu,omitempty"`
+	Machine      *string `json:"machine,omitempty"`
+	BIOS         *string `json:"bios,omitempty"`
+	Boot         *string `json:"boot,omitempty"`
+	OSType       *string `json:"ostype,omitempty"`
+	Tags         *string `json:"tags,omitempty"`
+	Description  *string `json:"description,omitempty"`
+	OnBoot       *Bool   `json:"onboot,omitempty"`
+	Template     *Bool   `json:"template,omitempty"`
+	Agent        *string `json:"agent,omitempty"`
+	CIUser       *string `json:"ciuser,omitempty"`
+	CIPassword   *string `json:"cipassword,omitempty"`
+	SSHKeys      *string `json:"sshkeys,omitempty"`
+	Nameserver   *string `json:"nameserver,omitempty"`
+	Searchdomain *string `json:"searchdomain,omitempty"`
+	CICustom     *string `json:"cicustom,omitempty"`
+	Digest       string  `json:"digest,omitempty"`
+	// Devices holds the numbered device entries such as scsi0 and net0, keyed by name
+	Devices map[string]string `json:"-"`
+}
+
+func (c *VirtualMachineConfig) UnmarshalJSON(data []byte) error {
+	type alias VirtualMachineConfig
+	err := json.Unmarshal(data, (*alias)(c))
+	if err != nil {
+		return err
+	}
+
+	fields := map[string]any{}
+	err = json.Unmarshal(data, &fields)
+	if err != nil {
+		return err
+	}
+
+	c.Devices = map[string]string{}
+	for key, value := range fields {
+		if text, ok := value.(string); ok && deviceKey.MatchString(key) {
+			c.Devices[key] = text
+		}
+	}
+	return nil
+}
+
+// VirtualMachineStatus The structure that represents the runtime status of a Proxmox virtual machine.
+// Status is either running or stopped.
+type VirtualMachineStatus struct {
+	VMID      Int     `json:"vmid"`
+	Name      string  `json:"name,omitempty"`
+	Status    string  `json:"status"`
+	QMPStatus string  `json:"qmpstatus,omitempty"`
+	Lock      string  `json:"lock,omitempty"`
+	Uptime    Int     `json:"uptime,omitempty"`
+	CPU       float64 `json:"cpu,omitempty"`
+	MaxMem    Int     `json:"maxmem,omitempty"`
+	MaxDisk   Int     `json:"maxdisk,omitempty"`
+	Agent     *Bool   `json:"agent,omitempty"`
+	Template  *Bool   `json:"template,omitempty"`
+	Tags      string  `json:"tags,omitempty"`
+}
+
+// VirtualMachinePendingChange One configuration key of a virtual machine. Pending holds the new value and Delete is set
+// when the key will be removed, both only take effect when the virtual machine is restarted.
+type VirtualMachinePendingChange struct {
+	Key     string `json:"key"`
+	Value   any    `json:"value,omitempty"`
+	Pending any    `json:"pending,omitempty"`
+	Delete  *Int   `json:"delete,omitempty"`
+}
+
+// IsPending reports whether the key has a change that is waiting for a restart
+func (c VirtualMachinePendingChange) IsPending() bool {
+	return c.Pending != nil || (c.Delete != nil && *c.Delete > 0)
+}
+
+// GuestNetworkInterface A network interface inside the guest as reported by the QEMU guest agent
+type GuestNetworkInterface struct {
+	Name            string           `json:"name"`
+	HardwareAddress string           `json:"hardware-address,omitempty"`
+	IPAddresses     []GuestIPAddress `json:"ip-addresses,omitempty"`
+}
+
+// GuestIPAddress An address of a guest network interface. Type is either ipv4 or ipv6.
+type GuestIPAddress struct {
+	Address string `json:"ip-address"`
+	Type    string `json:"ip-address-type"`
+	Prefix  Int    `json:"prefix"`
+}
+
+// VirtualMachineSnapshotRequest The request that Proxmox expects when taking a snapshot. VMState also saves the
+// memory of a running virtual machine, so that a rollback resumes it where it was.
+type VirtualMachineSnapshotRequest struct {
+	Name        string  `json:"snapname"`
+	Description *string `json:"description,omitempty"`
+	VMState     *Bool   `json:"vmstate,omitempty"`
+}
+
+// VirtualMachineSnapshot A snapshot of a virtual machine. SnapTime is not set on the current entry.
+type VirtualMachineSnapshot struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Parent      string `json:"parent,omitempty"`
+	SnapTime    Int    `json:"snaptime,omitempty"`
+	VMState     *Bool  `json:"vmstate,omitempty"`
+}
@@ -0,0 +1,591 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeQemu is a stand-in for the virtual machines on a single node. It keeps the configuration of
+// each virtual machine as the flat map that Proxmox returns. Pending holds the changes that are
+// waiting for a restart, tests set it directly.
+type fakeQemu struct {
+	mu       sync.Mutex
+	node     string
+	configs  map[int64]map[string]any
+	statuses map[int64]string
+	pending  map[int64]map[string]any
+	// agents holds what the guest agent returns for network-get-interfaces
+	agents map[int64]any
+	// snapshots holds the snapshots of each virtual machine, oldest first
+	snapshots map[int64][]map[string]any
+	// rollbacks records the snapshot that each virtual machine was last rolled back to
+	rollbacks map[int64]string
+	// migrations records the request of each virtual machine that was migrated away from the node
+	migrations map[int64]map[string]any
+}
+
+func newFakeQemu(fake *fakeProxmox, node string) *fakeQemu {
+	qemu := &fakeQemu{node: node, configs: map[int64]map[string]any{}, statuses: map[int64]string{}, pending: map[int64]map[string]any{}, agents: map[int64]any{}, snapshots: map[int64][]map[string]any{}, rollbacks: map[int64]string{}, migrations: map[int64]map[string]any{}}
+	fake.handlePrefix("nodes/"+node+"/qemu", qemu.ServeHTTP)
+	return qemu
+}
+
+func (q *fakeQemu) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	parts := strings.Split(strings.Trim(strings.SplitN(r.URL.Path, "/qemu", 2)[1], "/"), "/")
+	if parts[0] == "" {
+		switch r.Method {
+		case "GET":
+			list := []map[string]any{}
+			for vmid, config := range q.configs {
+				list = append(list, map[string]any{"vmid": vmid, "name": config["name"], "status": q.statuses[vmid], "template": config["template"], "tags": config["tags"]})
+			}
+			writeData(w, list)
+		case "POST":
+			body := readBody(r)
+			vmid := int64(body["vmid"].(float64))
+			if _, exists := q.configs[vmid]; exists {
+				http.Error(w, "VM "+strconv.FormatInt(vmid, 10)+" already exists", http.StatusInternalServerError)
+				return
+			}
+			delete(body, "vmid")
+			q.configs[vmid] = body
+			q.statuses[vmid] = "stopped"
+			writeData(w, testUPID)
+		}
+		return
+	}
+
+	vmid, _ := strconv.ParseInt(parts[0], 10, 64)
+	config, exists := q.configs[vmid]
+	if !exists {
+		http.Error(w, "Configuration file 'nodes/"+q.node+"/qemu-server/"+parts[0]+".conf' does not exist", http.StatusInternalServerError)
+		return
+	}
+
+	if len(parts) > 1 && parts[1] == "snapshot" {
+		q.serveSnapshot(w, r, vmid, parts[2:])
+		return
+	}
+
+	route := r.Method + " " + strings.Join(parts[1:], "/")
+	switch route {
+	case "DELETE ":
+		if q.statuses[vmid] != "stopped" {
+			http.Error(w, "VM is running", http.StatusInternalServerError)
+			return
+		}
+		delete(q.configs, vmid)
+		writeData(w, testUPID)
+	case "GET config":
+		writeData(w, config)
+	case "POST config":
+		body := readBody(r)
+		if deletes, ok := body["delete"].(string); ok {
+			for _, key := range strings.Split(deletes, ",") {
+				delete(config, key)
+			}
+			delete(body, "delete")
+		}
+		for key, value := range body {
+			config[key] = value
+		}
+		writeData(w, testUPID)
+	case "POST clone":
+		body := readBody(r)
+		newID := int64(body["newid"].(float64))
+		if _, exists := q.configs[newID]; exists {
+			http.Error(w, "VM "+strconv.FormatInt(newID, 10)+" already exists", http.StatusInternalServerError)
+			return
+		}
+		clone := map[string]any{}
+		for key, value := range config {
+			if key != "template" {
+				clone[key] = value
+			}
+		}
+		if name, ok := body["name"]; ok {
+			clone["name"] = name
+		}
+		q.configs[newID] = clone
+		q.statuses[newID] = "stopped"
+		writeData(w, testUPID)
+	case "GET agent/network-get-interfaces":
+		interfaces, ok := q.agents[vmid]
+		if !ok || q.statuses[vmid] != "running" {
+			http.Error(w, "QEMU guest agent is not running", http.StatusInternalServerError)
+			return
+		}
+		writeData(w, map[string]any{"result": interfaces})
+	case "POST template":
+		if q.statuses[vmid] != "stopped" {
+			http.Error(w, "you can't convert a running VM to a template", http.StatusInternalServerError)
+			return
+		}
+		config["template"] = 1
+		writeData(w, testUPID)
+	case "POST migrate":
+		body := readBody(r)
+		if q.statuses[vmid] == "running" && body["online"] != float64(1) {
+			http.Error(w, "can't migrate running VM without --online", http.StatusInternalServerError)
+			return
+		}
+		delete(q.configs, vmid)
+		delete(q.statuses, vmid)
+		q.migrations[vmid] = body
+		writeData(w, testUPID)
+	case "PUT cloudinit":
+		writeData(w, nil)
+	case "GET pending":
+		list := []map[string]any{}
+		for key, value := range config {
+			entry := map[string]any{"key": key, "value": value}
+			if pending, ok := q.pending[vmid][key]; ok {
+				entry["pending"] = pending
+			}
+			list = append(list, entry)
+		}
+		writeData(w, list)
+	case "PUT resize":
+		body := readBody(r)
+		disk := body["disk"].(string)
+		properties := ParsePropertyString(config[disk].(string))
+		properties["size"] = body["size"].(string)
+		config[disk] = properties.String()
+		writeData(w, testUPID)
+	case "POST move_disk":
+		body := readBody(r)
+		disk := body["disk"].(string)
+		properties := ParsePropertyString(config[disk].(string))
+		_, volume, _ := strings.Cut(properties[""], ":")
+		properties[""] = body["storage"].(string) + ":" + volume
+		config[disk] = properties.String()
+		writeData(w, testUPID)
+	case "GET status/current":
+		writeData(w, map[string]any{"vmid": vmid, "status": q.statuses[vmid]})
+	case "POST status/start":
+		q.statuses[vmid] = "running"
+		writeData(w, testUPID)
+	case "POST status/stop", "POST status/shutdown":
+		q.statuses[vmid] = "stopped"
+		writeData(w, testUPID)
+	default:
+		http.Error(w, "no handler for "+route, http.StatusNotImplemented)
+	}
+}
+
+// serveSnapshot handles the snapshot endpoints of a virtual machine, parts is the path after /snapshot
+func (q *fakeQemu) serveSnapshot(w http.ResponseWriter, r *http.Request, vmid int64, parts []string) {
+	snapshots := q.snapshots[vmid]
+	index := -1
+	if len(parts) > 0 {
+		for i, snapshot := range snapshots {
+			if snapshot["name"] == parts[0] {
+				index = i
+			}
+		}
+		if index < 0 {
+			http.Error(w, "snapshot '"+parts[0]+"' does not exist", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	route := r.Method + " " + strings.Join(append([]string{"snapshot"}, parts[min(len(parts), 1):]...), "/")
+	switch route {
+	case "GET snapshot":
+		list := []map[string]any{}
+		parent := ""
+		for _, snapshot := range snapshots {
+			list = append(list, snapshot)
+			parent = snapshot["name"].(string)
+		}
+		writeData(w, append(list, map[string]any{"name": "current", "description": "You are here!", "parent": parent}))
+	case "POST snapshot":
+		body := readBody(r)
+		for _, snapshot := range snapshots {
+			if snapshot["name"] == body["snapname"] {
+				http.Error(w, "snapshot name '"+body["snapname"].(string)+"' already used", http.StatusInternalServerError)
+				return
+			}
+		}
+		snapshot := map[string]any{"name": body["snapname"], "snaptime": 1700000000 + len(snapshots)}
+		if description, ok := body["description"]; ok {
+			snapshot["description"] = description
+		}
+		if vmstate, ok := body["vmstate"]; ok {
+			snapshot["vmstate"] = vmstate
+		}
+		q.snapshots[vmid] = append(snapshots, snapshot)
+		writeData(w, testUPID)
+	case "PUT snapshot/config":
+		snapshots[index]["description"] = readBody(r)["description"]
+		writeData(w, nil)
+	case "DELETE snapshot":
+		q.snapshots[vmid] = append(snapshots[:index], snapshots[index+1:]...)
+		writeData(w, testUPID)
+	case "POST snapshot/rollback":
+		q.rollbacks[vmid] = parts[0]
+		writeData(w, testUPID)
+	default:
+		http.Error(w, "no handler for "+route, http.StatusNotImplemented)
+	}
+}
+
+func TestVirtualMachineLifecycle(t *testing.T) {
+	fake := newFakeProxmox()
+	qemu := newFakeQemu(fake, "pve")
+	client := newTestClient(t, fake)
+
+	vmid := int64(100)
+	name := "web"
+	memory := int64(2048)
+	onBoot := true
+	upid, err := CreateVirtualMachine(client, "pve", &VirtualMachineRequest{
+		VMID:    &vmid,
+		Name:    &name,
+		Memory:  &memory,
+		OnBoot:  NewBool(&onBoot),
+		Devices: map[string]string{"net0": "virtio,bridge=vmbr0"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = WaitForTask(client, upid, TaskPollInterval)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Proxmox returns the memory as a string, make sure that is handled
+	qemu.configs[vmid]["memory"] = "2048"
+
+	config, err := GetVirtualMachineConfig(client, "pve", vmid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *config.Name != "web" || *config.Memory.Pointer() != 2048 || !bool(*config.OnBoot) {
+		t.Errorf("Incorrect config returned: %+v", config)
+	}
+	if config.Devices["net0"] != "virtio,bridge=vmbr0" {
+		t.Errorf("Incorrect net0 returned. Expected virtio,bridge=vmbr0, got %v", config.Devices["net0"])
+	}
+
+	remove := "onboot"
+	_, err = UpdateVirtualMachineConfig(client, "pve", vmid, &VirtualMachineRequest{Delete: &remove})
+	if err != nil {
+		t.Fatal(err)
+	}
+	config, err = GetVirtualMachineConfig(client, "pve", vmid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.OnBoot != nil {
+		t.Errorf("Expected onboot to be removed")
+	}
+
+	status, err := GetVirtualMachineStatus(client, "pve", vmid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Status != "stopped" {
+		t.Errorf("Incorrect status returned. Expected stopped, got %v", status.Status)
+	}
+
+	_, err = DeleteVirtualMachine(client, "pve", vmid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = GetVirtualMachineConfig(client, "pve", vmid)
+	if err == nil {
+		t.Errorf("Expected an error reading a deleted virtual machine")
+	}
+}
+
+func TestVirtualMachineRequestMarshal(t *testing.T) {
+	cores := int64(2)
+	data, err := json.Marshal(&VirtualMachineRequest{Cores: &cores, Devices: map[string]string{"scsi0": "local-lvm:32"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fields := map[string]any{}
+	err = json.Unmarshal(data, &fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fields["cores"] != float64(2) || fields["scsi0"] != "local-lvm:32" || len(fields) != 2 {
+		t.Errorf("Incorrect request body: %s", data)
+	}
+}
+
+func TestVirtualMachineDiskResizeAndMove(t *testing.T) {
+	fake := newFakeProxmox()
+	qemu := newFakeQemu(fake, "pve")
+	client := newTestClient(t, fake)
+
+	vmid := int64(100)
+	qemu.configs[vmid] = map[string]any{"scsi0": "local-lvm:vm-100-disk-0,size=32G"}
+	qemu.statuses[vmid] = "stopped"
+
+	_, err := ResizeVirtualMachineDisk(client, "pve", vmid, "scsi0", "64G")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = MoveVirtualMachineDisk(client, "pve", vmid, "scsi0", "ceph")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := GetVirtualMachineConfig(client, "pve", vmid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Devices["scsi0"] != "ceph:vm-100-disk-0,size=64G" {
+		t.Errorf("Incorrect scsi0 returned. Expected ceph:vm-100-disk-0,size=64G, got %v", config.Devices["scsi0"])
+	}
+}
+
+func TestGetVirtualMachinePending(t *testing.T) {
+	fake := newFakeProxmox()
+	qemu := newFakeQemu(fake, "pve")
+	client := newTestClient(t, fake)
+
+	vmid := int64(100)
+	qemu.configs[vmid] = map[string]any{"memory": 2048, "net0": "virtio=BC:24:11:00:00:01,bridge=vmbr0"}
+	qemu.statuses[vmid] = "running"
+	qemu.pending[vmid] = map[string]any{"net0": "e1000=BC:24:11:00:00:01,bridge=vmbr0"}
+
+	changes, err := GetVirtualMachinePending(client, "pve", vmid)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var pending []string
+	for _, change := range changes {
+		if change.IsPending() {
+			pending = append(pending, change.Key)
+		}
+	}
+	if len(changes) != 2 || len(pending) != 1 || pending[0] != "net0" {
+		t.Errorf("Incorrect pending changes returned: %+v", changes)
+	}
+}
+
+func TestCloneVirtualMachine(t *testing.T) {
+	fake := newFakeProxmox()
+	qemu := newFakeQemu(fake, "pve")
+	client := newTestClient(t, fake)
+
+	qemu.configs[9000] = map[string]any{"name": "debian", "template": 1, "scsi0": "local-lvm:base-9000-disk-0,size=8G"}
+	qemu.statuses[9000] = "stopped"
+
+	name := "web"
+	full := true
+	upid, err := CloneVirtualMachine(client, "pve", 9000, &VirtualMachineCloneRequest{NewID: 100, Name: &name, Full: NewBool(&full)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = WaitForTask(client, upid, TaskPollInterval)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := GetVirtualMachineConfig(client, "pve", 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *config.Name != "web" || config.Template != nil || config.Devices["scsi0"] == "" {
+		t.Errorf("Incorrect clone returned: %+v", config)
+	}
+}
+
+func TestRegenerateCloudInit(t *testing.T) {
+	fake := newFakeProxmox()
+	qemu := newFakeQemu(fake, "pve")
+	client := newTestClient(t, fake)
+
+	qemu.configs[100] = map[string]any{"ide2": "local-lvm:vm-100-cloudinit,media=cdrom", "ciuser": "debian"}
+	qemu.statuses[100] = "stopped"
+
+	err := RegenerateCloudInit(client, "pve", 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !fake.seen("PUT nodes/pve/qemu/100/cloudinit") {
+		t.Error("Expected the cloud-init drive to be regenerated")
+	}
+
+	config, err := GetVirtualMachineConfig(client, "pve", 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *config.CIUser != "debian" || config.Devices["ide2"] == "" {
+		t.Errorf("Incorrect config returned: %+v", config)
+	}
+}
+
+func TestVirtualMachinePower(t *testing.T) {
+	fake := newFakeProxmox()
+	qemu := newFakeQemu(fake, "pve")
+	client := newTestClient(t, fake)
+
+	qemu.configs[100] = map[string]any{"name": "web"}
+	qemu.statuses[100] = "stopped"
+
+	_, err := StartVirtualMachine(client, "pve", 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	status, err := GetVirtualMachineStatus(client, "pve", 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Status != "running" {
+		t.Errorf("Incorrect status returned. Expected running, got %v", status.Status)
+	}
+
+	_, err = ShutdownVirtualMachine(client, "pve", 100, 60, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	status, err = GetVirtualMachineStatus(client, "pve", 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Status != "stopped" {
+		t.Errorf("Incorrect status returned. Expected stopped, got %v", status.Status)
+	}
+}
+
+func TestGetVirtualMachineNetworkInterfaces(t *testing.T) {
+	fake := newFakeProxmox()
+	qemu := newFakeQemu(fake, "pve")
+	client := newTestClient(t, fake)
+
+	qemu.configs[100] = map[string]any{"agent": "1"}
+	qemu.statuses[100] = "running"
+
+	_, err := GetVirtualMachineNetworkInterfaces(client, "pve", 100)
+	if err == nil {
+		t.Error("Expected an error when the guest agent is not running")
+	}
+
+	qemu.agents[100] = []map[string]any{
+		{"name": "lo", "hardware-address": "00:00:00:00:00:00", "ip-addresses": []map[string]any{{"ip-address": "127.0.0.1", "ip-address-type": "ipv4", "prefix": 8}}},
+		{"name": "eth0", "hardware-address": "bc:24:11:00:00:01", "ip-addresses": []map[string]any{{"ip-address": "10.0.0.10", "ip-address-type": "ipv4", "prefix": 24}}},
+	}
+	interfaces, err := GetVirtualMachineNetworkInterfaces(client, "pve", 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(interfaces) != 2 || interfaces[1].HardwareAddress != "bc:24:11:00:00:01" || interfaces[1].IPAddresses[0].Address != "10.0.0.10" {
+		t.Errorf("Incorrect interfaces returned: %+v", interfaces)
+	}
+}
+
+func TestConvertToTemplate(t *testing.T) {
+	fake := newFakeProxmox()
+	qemu := newFakeQemu(fake, "pve")
+	client := newTestClient(t, fake)
+
+	qemu.configs[9000] = map[string]any{"name": "debian-template", "tags": "debian;golden"}
+	qemu.statuses[9000] = "stopped"
+
+	_, err := ConvertToTemplate(client, "pve", 9000)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	virtualMachines, err := GetVirtualMachines(client, "pve")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(virtualMachines) != 1 || virtualMachines[0].Template == nil || !bool(*virtualMachines[0].Template) || virtualMachines[0].Tags != "debian;golden" {
+		t.Errorf("Incorrect virtual machines returned: %+v", virtualMachines)
+	}
+}
+
+func TestVirtualMachineSnapshots(t *testing.T) {
+	fake := newFakeProxmox()
+	qemu := newFakeQemu(fake, "pve")
+	client := newTestClient(t, fake)
+
+	qemu.configs[100] = map[string]any{"name": "web"}
+	qemu.statuses[100] = "running"
+
+	description := "Before the upgrade"
+	vmstate := Bool(true)
+	_, err := CreateVirtualMachineSnapshot(client, "pve", 100, &VirtualMachineSnapshotRequest{Name: "pre-upgrade", Description: &description, VMState: &vmstate})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = CreateVirtualMachineSnapshot(client, "pve", 100, &VirtualMachineSnapshotRequest{Name: "pre-upgrade"})
+	if err == nil {
+		t.Errorf("Expected an error when the snapshot name is already used")
+	}
+
+	err = UpdateVirtualMachineSnapshot(client, "pve", 100, "pre-upgrade", "Before upgrading to bookworm")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	snapshots, err := GetVirtualMachineSnapshots(client, "pve", 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshots) != 2 || snapshots[0].Name != "pre-upgrade" || snapshots[0].Description != "Before upgrading to bookworm" ||
+		snapshots[0].VMState == nil || !bool(*snapshots[0].VMState) || snapshots[0].SnapTime == 0 || snapshots[1].Parent != "pre-upgrade" {
+		t.Errorf("Incorrect snapshots returned: %+v", snapshots)
+	}
+
+	_, err = RollbackVirtualMachineSnapshot(client, "pve", 100, "pre-upgrade")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if qemu.rollbacks[100] != "pre-upgrade" {
+		t.Errorf("Expected a rollback to pre-upgrade, got %q", qemu.rollbacks[100])
+	}
+
+	_, err = DeleteVirtualMachineSnapshot(client, "pve", 100, "pre-upgrade")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = DeleteVirtualMachineSnapshot(client, "pve", 100, "pre-upgrade")
+	if err == nil {
+		t.Errorf("Expected an error when the snapshot does not exist")
+	}
+}
+
+func TestMigrateVirtualMachine(t *testing.T) {
+	fake := newFakeProxmox()
+	qemu := newFakeQemu(fake, "pve")
+	client := newTestClient(t, fake)
+
+	qemu.configs[100] = map[string]any{"name": "web"}
+	qemu.statuses[100] = "running"
+
+	_, err := MigrateVirtualMachine(client, "pve", 100, &VirtualMachineMigrateRequest{Target: "pve2"})
+	if err == nil {
+		t.Errorf("Expected an error when a running virtual machine is migrated offline")
+	}
+
+	online := Bool(true)
+	targetStorage := "local-zfs"
+	_, err = MigrateVirtualMachine(client, "pve", 100, &VirtualMachineMigrateRequest{Target: "pve2", Online: &online, WithLocalDisks: &online, TargetStorage: &targetStorage})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]any{"target": "pve2", "online": float64(1), "with-local-disks": float64(1), "targetstorage": "local-zfs"}
+	if !reflect.DeepEqual(qemu.migrations[100], expected) {
+		t.Errorf("Incorrect migration request. Expected %v, got %v", expected, qemu.migrations[100])
+	}
+	if _, exists := qemu.configs[100]; exists {
+		t.Errorf("Expected the virtual machine to have left the node")
+	}
+}
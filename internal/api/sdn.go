@@ -0,0 +1,207 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/clincha-org/proxmox-api/pkg/proxmox"
+)
+
+const SDNPath string = ClusterPath + "/sdn"
+const SDNControllersPath string = SDNPath + "/controllers"
+const SDNIPAMsPath string = SDNPath + "/ipams"
+const SDNDNSPath string = SDNPath + "/dns"
+
+// SDNApplyTimeout is how long ApplySDN waits for the reload task to finish
+var SDNApplyTimeout = 2 * time.Minute
+
+// ApplySDN applies the pending SDN configuration to every node in the cluster.
+// Controllers, zones and vnets are only staged by their endpoints until this is called.
+func ApplySDN(client *proxmox.Client) error {
+	task := TaskResponse{}
+	err := doRequest(client, "PUT", SDNPath, nil, &task)
+	if err != nil {
+		return fmt.Errorf("ApplySDN: %w", err)
+	}
+
+	err = WaitForTask(client, task.Data, SDNApplyTimeout)
+	if err != nil {
+		return fmt.Errorf("ApplySDN: %w", err)
+	}
+
+	return nil
+}
+
+func GetSDNControllers(client *proxmox.Client) ([]SDNController, error) {
+	controllerModel := SDNControllersResponse{}
+	err := doRequest(client, "GET", SDNControllersPath, nil, &controllerModel)
+	if err != nil {
+		return nil, fmt.Errorf("GetSDNControllers: %w", err)
+	}
+
+	return controllerModel.Data, nil
+}
+
+func GetSDNController(client *proxmox.Client, controller string) (SDNController, error) {
+	controllerModel := SDNControllerResponse{}
+	err := doRequest(client, "GET", SDNControllersPath+"/"+url.PathEscape(controller), nil, &controllerModel)
+	if err != nil {
+		return SDNController{}, fmt.Errorf("GetSDNController-%s: %w", controller, err)
+	}
+
+	controllerModel.Data.Controller = controller
+
+	return controllerModel.Data, nil
+}
+
+func CreateSDNController(client *proxmox.Client, controllerRequest *SDNControllerRequest) (SDNController, error) {
+	err := doRequest(client, "POST", SDNControllersPath, controllerRequest, nil)
+	if err != nil {
+		return SDNController{}, fmt.Errorf("CreateSDNController-%s: %w", controllerRequest.Controller, err)
+	}
+
+	err = ApplySDN(client)
+	if err != nil {
+		return SDNController{}, fmt.Errorf("CreateSDNController-%s: %w", controllerRequest.Controller, err)
+	}
+
+	return GetSDNController(client, controllerRequest.Controller)
+}
+
+func UpdateSDNController(client *proxmox.Client, controller string, controllerRequest *SDNControllerRequest) (SDNController, error) {
+	err := doRequest(client, "PUT", SDNControllersPath+"/"+url.PathEscape(controller), controllerRequest, nil)
+	if err != nil {
+		return SDNController{}, fmt.Errorf("UpdateSDNController-%s: %w", controller, err)
+	}
+
+	err = ApplySDN(client)
+	if err != nil {
+		return SDNController{}, fmt.Errorf("UpdateSDNController-%s: %w", controller, err)
+	}
+
+	return GetSDNController(client, controller)
+}
+
+func DeleteSDNController(client *proxmox.Client, controller string) error {
+	err := doRequest(client, "DELETE", SDNControllersPath+"/"+url.PathEscape(controller), nil, nil)
+	if err != nil {
+		return fmt.Errorf("DeleteSDNController-%s: %w", controller, err)
+	}
+
+	err = ApplySDN(client)
+	if err != nil {
+		return fmt.Errorf("DeleteSDNController-%s: %w", controller, err)
+	}
+
+	return nil
+}
+
+func GetSDNIPAMs(client *proxmox.Client) ([]SDNIPAM, error) {
+	ipamModel := SDNIPAMsResponse{}
+	err := doRequest(client, "GET", SDNIPAMsPath, nil, &ipamModel)
+	if err != nil {
+		return nil, fmt.Errorf("GetSDNIPAMs: %w", err)
+	}
+
+	return ipamModel.Data, nil
+}
+
+func GetSDNIPAM(client *proxmox.Client, ipam string) (SDNIPAM, error) {
+	ipamModel := SDNIPAMResponse{}
+	err := doRequest(client, "GET", SDNIPAMsPath+"/"+url.PathEscape(ipam), nil, &ipamModel)
+	if err != nil {
+		return SDNIPAM{}, fmt.Errorf("GetSDNIPAM-%s: %w", ipam, err)
+	}
+
+	ipamModel.Data.IPAM = ipam
+
+	return ipamModel.Data, nil
+}
+
+func CreateSDNIPAM(client *proxmox.Client, ipamRequest *SDNIPAMRequest) (SDNIPAM, error) {
+	err := doRequest(client, "POST", SDNIPAMsPath, ipamRequest, nil)
+	if err != nil {
+		return SDNIPAM{}, fmt.Errorf("CreateSDNIPAM-%s: %w", ipamRequest.IPAM, err)
+	}
+
+	return GetSDNIPAM(client, ipamRequest.IPAM)
+}
+
+func UpdateSDNIPAM(client *proxmox.Client, ipam string, ipamRequest *SDNIPAMRequest) (SDNIPAM, error) {
+	err := doRequest(client, "PUT", SDNIPAMsPath+"/"+url.PathEscape(ipam), ipamRequest, nil)
+	if err != nil {
+		return SDNIPAM{}, fmt.Errorf("UpdateSDNIPAM-%s: %w", ipam, err)
+	}
+
+	return GetSDNIPAM(client, ipam)
+}
+
+func DeleteSDNIPAM(client *proxmox.Client, ipam string) error {
+	err := doRequest(client, "DELETE", SDNIPAMsPath+"/"+url.PathEscape(ipam), nil, nil)
+	if err != nil {
+		return fmt.Errorf("DeleteSDNIPAM-%s: %w", ipam, err)
+	}
+
+	return nil
+}
+
+func GetSDNDNSList(client *proxmox.Client) ([]SDNDNS, error) {
+	dnsModel := SDNDNSListResponse{}
+	err := doRequest(client, "GET", SDNDNSPath, nil, &dnsModel)
+	if err != nil {
+		return nil, fmt.Errorf("GetSDNDNSList: %w", err)
+	}
+
+	return dnsModel.Data, nil
+}
+
+func GetSDNDNS(client *proxmox.Client, dns string) (SDNDNS, error) {
+	dnsModel := SDNDNSResponse{}
+	err := doRequest(client, "GET", SDNDNSPath+"/"+url.PathEscape(dns), nil, &dnsModel)
+	if err != nil {
+		return SDNDNS{}, fmt.Errorf("GetSDNDNS-%s: %w", dns, err)
+	}
+
+	dnsModel.Data.DNS = dns
+
+	return dnsModel.Data, nil
+}
+
+func CreateSDNDNS(client *proxmox.Client, dnsRequest *SDNDNSRequest) (SDNDNS, error) {
+	err := doRequest(client, "POST", SDNDNSPath, dnsRequest, nil)
+	if err != nil {
+		return SDNDNS{}, fmt.Errorf("CreateSDNDNS-%s: %w", dnsRequest.DNS, err)
+	}
+
+	return GetSDNDNS(client, dnsRequest.DNS)
+}
+
+func UpdateSDNDNS(client *proxmox.Client, dns string, dnsRequest *SDNDNSRequest) (SDNDNS, error) {
+	err := doRequest(client, "PUT", SDNDNSPath+"/"+url.PathEscape(dns), dnsRequest, nil)
+	if err != nil {
+		return SDNDNS{}, fmt.Errorf("UpdateSDNDNS-%s: %w", dns, err)
+	}
+
+	return GetSDNDNS(client, dns)
+}
+
+func DeleteSDNDNS(client *proxmox.Client, dns string) error {
+	err := doRequest(client, "DELETE", SDNDNSPath+"/"+url.PathEscape(dns), nil, nil)
+	if err != nil {
+		return fmt.Errorf("DeleteSDNDNS-%s: %w", dns, err)
+	}
+
+	return nil
+}
+
+// GetSDNIPAMStatus returns every allocation that the IPAM has made across all vnets and subnets
+func GetSDNIPAMStatus(client *proxmox.Client, ipam string) ([]SDNIPAMAllocation, error) {
+	statusModel := SDNIPAMStatusResponse{}
+	err := doRequest(client, "GET", SDNIPAMsPath+"/"+url.PathEscape(ipam)+"/status", nil, &statusModel)
+	if err != nil {
+		return nil, fmt.Errorf("GetSDNIPAMStatus-%s: %w", ipam, err)
+	}
+
+	return statusModel.Data, nil
+}
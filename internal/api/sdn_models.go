@@ -0,0 +1,127 @@
+package api
+
+// SDNControllersResponse The response from Proxmox when a list of SDN controllers is returned
+type SDNControllersResponse struct {
+	Data []SDNController `json:"data"`
+}
+
+// SDNControllerResponse The response from Proxmox when a single SDN controller is returned
+type SDNControllerResponse struct {
+	Data SDNController `json:"data"`
+}
+
+// SDNControllerRequest The request that Proxmox expects when creating and modifying SDN controllers.
+// Controller and Type can only be sent on create, leave them empty when updating.
+type SDNControllerRequest struct {
+	Controller              string  `json:"controller,omitempty"`
+	Type                    string  `json:"type,omitempty"`
+	ASN                     *int64  `json:"asn,omitempty"`
+	Peers                   *string `json:"peers,omitempty"`
+	EBGP                    *Bool   `json:"ebgp,omitempty"`
+	EBGPMultihop            *int64  `json:"ebgp-multihop,omitempty"`
+	BGPMultipathAsPathRelax *Bool   `json:"bgp-multipath-as-path-relax,omitempty"`
+	Loopback                *string `json:"loopback,omitempty"`
+	Node                    *string `json:"node,omitempty"`
+	ISISDomain              *string `json:"isis-domain,omitempty"`
+	ISISInterfaces          *string `json:"isis-ifaces,omitempty"`
+	ISISNet                 *string `json:"isis-net,omitempty"`
+	Delete                  *string `json:"delete,omitempty"`
+}
+
+// SDNController The structure that represents a Proxmox SDN controller
+type SDNController struct {
+	Controller              string  `json:"controller"`
+	Type                    string  `json:"type"`
+	ASN                     *Int    `json:"asn,omitempty"`
+	Peers                   *string `json:"peers,omitempty"`
+	EBGP                    *Bool   `json:"ebgp,omitempty"`
+	EBGPMultihop            *Int    `json:"ebgp-multihop,omitempty"`
+	BGPMultipathAsPathRelax *Bool   `json:"bgp-multipath-as-path-relax,omitempty"`
+	Loopback                *string `json:"loopback,omitempty"`
+	Node                    *string `json:"node,omitempty"`
+	ISISDomain              *string `json:"isis-domain,omitempty"`
+	ISISInterfaces          *string `json:"isis-ifaces,omitempty"`
+	ISISNet                 *string `json:"isis-net,omitempty"`
+}
+
+// SDNIPAMsResponse The response from Proxmox when a list of SDN IPAM plugins is returned
+type SDNIPAMsResponse struct {
+	Data []SDNIPAM `json:"data"`
+}
+
+// SDNIPAMResponse The response from Proxmox when a single SDN IPAM plugin is returned
+type SDNIPAMResponse struct {
+	Data SDNIPAM `json:"data"`
+}
+
+// SDNIPAMRequest The request that Proxmox expects when creating and modifying SDN IPAM plugins.
+// IPAM and Type can only be sent on create, leave them empty when updating.
+type SDNIPAMRequest struct {
+	IPAM    string  `json:"ipam,omitempty"`
+	Type    string  `json:"type,omitempty"`
+	URL     *string `json:"url,omitempty"`
+	Token   *string `json:"token,omitempty"`
+	Section *int64  `json:"section,omitempty"`
+	Delete  *string `json:"delete,omitempty"`
+}
+
+// SDNIPAM The structure that represents a Proxmox SDN IPAM plugin.
+// Proxmox does not always return the token, callers should keep the configured value when it is missing.
+type SDNIPAM struct {
+	IPAM    string  `json:"ipam"`
+	Type    string  `json:"type"`
+	URL     *string `json:"url,omitempty"`
+	Token   *string `json:"token,omitempty"`
+	Section *Int    `json:"section,omitempty"`
+}
+
+// SDNDNSListResponse The response from Proxmox when a list of SDN DNS plugins is returned
+type SDNDNSListResponse struct {
+	Data []SDNDNS `json:"data"`
+}
+
+// SDNDNSResponse The response from Proxmox when a single SDN DNS plugin is returned
+type SDNDNSResponse struct {
+	Data SDNDNS `json:"data"`
+}
+
+// SDNDNSRequest The request that Proxmox expects when creating and modifying SDN DNS plugins.
+// DNS and Type can only be sent on create, leave them empty when updating.
+type SDNDNSRequest struct {
+	DNS           string  `json:"dns,omitempty"`
+	Type          string  `json:"type,omitempty"`
+	URL           *string `json:"url,omitempty"`
+	Key           *string `json:"key,omitempty"`
+	ReverseMaskV6 *int64  `json:"reversemaskv6,omitempty"`
+	TTL           *int64  `json:"ttl,omitempty"`
+	Delete        *string `json:"delete,omitempty"`
+}
+
+// SDNDNS The structure that represents a Proxmox SDN DNS plugin.
+// Proxmox does not always return the key, callers should keep the configured value when it is missing.
+type SDNDNS struct {
+	DNS           string  `json:"dns"`
+	Type          string  `json:"type"`
+	URL           *string `json:"url,omitempty"`
+	Key           *string `json:"key,omitempty"`
+	ReverseMaskV6 *Int    `json:"reversemaskv6,omitempty"`
+	TTL           *Int    `json:"ttl,omitempty"`
+}
+
+// SDNIPAMStatusResponse The response from Proxmox when the allocations of an IPAM are returned
+type SDNIPAMStatusResponse struct {
+	Data []SDNIPAMAllocation `json:"data"`
+}
+
+// SDNIPAMAllocation The structure that represents an IP address that an SDN IPAM has handed out.
+// Gateway entries belong to the subnet rather than to a guest, so they have no VMID.
+type SDNIPAMAllocation struct {
+	VNet     string  `json:"vnet"`
+	Zone     string  `json:"zone,omitempty"`
+	Subnet   string  `json:"subnet"`
+	IP       string  `json:"ip"`
+	MAC      *string `json:"mac,omitempty"`
+	VMID     *Int    `json:"vmid,omitempty"`
+	Hostname *string `json:"hostname,omitempty"`
+	Gateway  *Bool   `json:"gateway,omitempty"`
+}
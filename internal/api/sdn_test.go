@@ -0,0 +1,167 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+)
+
+func newSDNStandIn() *fakeProxmox {
+	fake := newFakeProxmox()
+	fake.collection(SDNControllersPath, "controller")
+	fake.collection(SDNIPAMsPath, "ipam")
+	fake.collection(SDNDNSPath, "dns")
+	fake.handle("PUT "+SDNPath, func(w http.ResponseWriter, r *http.Request) {
+		writeData(w, testUPID)
+	})
+	return fake
+}
+
+func TestSDNControllerLifecycle(t *testing.T) {
+	fake := newSDNStandIn()
+	client := newTestClient(t, fake)
+
+	asn := int64(65000)
+	peers := "10.0.0.1,10.0.0.2"
+	node := "pve"
+	ebgp := true
+	controller, err := CreateSDNController(client, &SDNControllerRequest{
+		Controller: "bgp1",
+		Type:       "bgp",
+		ASN:        &asn,
+		Peers:      &peers,
+		Node:       &node,
+		EBGP:       NewBool(&ebgp),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !fake.seen("PUT " + SDNPath) {
+		t.Errorf("Expected the SDN configuration to be applied after creating a controller")
+	}
+	if controller.Controller != "bgp1" || controller.Type != "bgp" {
+		t.Errorf("Incorrect controller returned. Expected bgp1/bgp, got %v/%v", controller.Controller, controller.Type)
+	}
+	if controller.ASN == nil || *controller.ASN.Pointer() != 65000 {
+		t.Errorf("Incorrect ASN returned. Expected 65000, got %v", controller.ASN)
+	}
+	if controller.EBGP == nil || !*controller.EBGP.Pointer() {
+		t.Errorf("Expected ebgp to be enabled")
+	}
+
+	remove := "peers"
+	newASN := int64(65001)
+	controller, err = UpdateSDNController(client, "bgp1", &SDNControllerRequest{ASN: &newASN, Delete: &remove})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if controller.Peers != nil {
+		t.Errorf("Expected peers to be removed, got %v", *controller.Peers)
+	}
+	if *controller.ASN.Pointer() != 65001 {
+		t.Errorf("Incorrect ASN returned. Expected 65001, got %v", *controller.ASN)
+	}
+
+	controllers, err := GetSDNControllers(client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(controllers) != 1 {
+		t.Errorf("Expected 1 controller, got %d", len(controllers))
+	}
+
+	err = DeleteSDNController(client, "bgp1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = GetSDNController(client, "bgp1")
+	if err == nil {
+		t.Errorf("Expected an error reading a deleted controller")
+	}
+}
+
+func TestSDNIPAMLifecycle(t *testing.T) {
+	fake := newSDNStandIn()
+	client := newTestClient(t, fake)
+
+	url := "https://netbox.example.com/api"
+	token := "secret"
+	ipam, err := CreateSDNIPAM(client, &SDNIPAMRequest{IPAM: "netbox", Type: "netbox", URL: &url, Token: &token})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ipam.IPAM != "netbox" || ipam.Type != "netbox" || *ipam.URL != url {
+		t.Errorf("Incorrect IPAM returned: %+v", ipam)
+	}
+
+	section := int64(3)
+	ipam, err = UpdateSDNIPAM(client, "netbox", &SDNIPAMRequest{Section: &section})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ipam.Section == nil || *ipam.Section.Pointer() != 3 {
+		t.Errorf("Incorrect section returned. Expected 3, got %v", ipam.Section)
+	}
+
+	err = DeleteSDNIPAM(client, "netbox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fake.seen("PUT " + SDNPath) {
+		t.Errorf("IPAM changes should not apply the SDN configuration")
+	}
+}
+
+func TestSDNDNSLifecycle(t *testing.T) {
+	fake := newSDNStandIn()
+	client := newTestClient(t, fake)
+
+	url := "http://powerdns.example.com:8081/api/v1/servers/localhost"
+	key := "secret"
+	ttl := int64(3600)
+	dns, err := CreateSDNDNS(client, &SDNDNSRequest{DNS: "powerdns", Type: "powerdns", URL: &url, Key: &key, TTL: &ttl})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dns.DNS != "powerdns" || *dns.TTL.Pointer() != 3600 {
+		t.Errorf("Incorrect DNS returned: %+v", dns)
+	}
+
+	list, err := GetSDNDNSList(client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 {
+		t.Errorf("Expected 1 DNS plugin, got %d", len(list))
+	}
+
+	err = DeleteSDNDNS(client, "powerdns")
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSDNIPAMStatus(t *testing.T) {
+	fake := newSDNStandIn()
+	fake.handle("GET "+SDNIPAMsPath+"/pve/status", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":[
+			{"vnet":"vnet1","zone":"zone1","subnet":"10.0.0.0-24","ip":"10.0.0.1","gateway":1},
+			{"vnet":"vnet1","zone":"zone1","subnet":"10.0.0.0-24","ip":"10.0.0.10","mac":"BC:24:11:00:00:01","vmid":"100","hostname":"web"}
+		]}`))
+	})
+	client := newTestClient(t, fake)
+
+	allocations, err := GetSDNIPAMStatus(client, "pve")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(allocations) != 2 {
+		t.Fatalf("Expected 2 allocations, got %d", len(allocations))
+	}
+	if allocations[0].Gateway == nil || !bool(*allocations[0].Gateway) || allocations[0].VMID != nil {
+		t.Errorf("Expected the first allocation to be the gateway: %+v", allocations[0])
+	}
+	if *allocations[1].VMID.Pointer() != 100 || *allocations[1].Hostname != "web" {
+		t.Errorf("Incorrect guest allocation returned: %+v", allocations[1])
+	}
+}
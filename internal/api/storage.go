@@ -0,0 +1,89 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/clincha-org/proxmox-api/pkg/proxmox"
+)
+
+const StoragePath string = "storage"
+
+// GetStorages returns the storage definitions of the cluster
+func GetStorages(client *proxmox.Client) ([]Storage, error) {
+	storageModel := StoragesResponse{}
+	err := doRequest(client, "GET", StoragePath, nil, &storageModel)
+	if err != nil {
+		return nil, fmt.Errorf("GetStorages: %w", err)
+	}
+
+	return storageModel.Data, nil
+}
+
+func GetStorage(client *proxmox.Client, storage string) (Storage, error) {
+	storageModel := StorageResponse{}
+	err := doRequest(client, "GET", StoragePath+"/"+url.PathEscape(storage), nil, &storageModel)
+	if err != nil {
+		return Storage{}, fmt.Errorf("GetStorage-%s: %w", storage, err)
+	}
+
+	storageModel.Data.Storage = storage
+
+	return storageModel.Data, nil
+}
+
+// CreateStorage adds a storage definition to the cluster. Proxmox checks that the storage can be reached,
+// for example by mounting an NFS export, before the definition is saved.
+func CreateStorage(client *proxmox.Client, storageRequest *StorageRequest) (Storage, error) {
+	err := doRequest(client, "POST", StoragePath, storageRequest, nil)
+	if err != nil {
+		return Storage{}, fmt.Errorf("CreateStorage-%s: %w", storageRequest.Storage, err)
+	}
+
+	return GetStorage(client, storageRequest.Storage)
+}
+
+func UpdateStorage(client *proxmox.Client, storage string, storageRequest *StorageRequest) (Storage, error) {
+	err := doRequest(client, "PUT", StoragePath+"/"+url.PathEscape(storage), storageRequest, nil)
+	if err != nil {
+		return Storage{}, fmt.Errorf("UpdateStorage-%s: %w", storage, err)
+	}
+
+	return GetStorage(client, storage)
+}
+
+// DeleteStorage removes the storage definition. The data on the storage is not touched.
+func DeleteStorage(client *proxmox.Client, storage string) error {
+	err := doRequest(client, "DELETE", StoragePath+"/"+url.PathEscape(storage), nil, nil)
+	if err != nil {
+		return fmt.Errorf("DeleteStorage-%s: %w", storage, err)
+	}
+
+	return nil
+}
+
+// GetNodeStorages returns the status of the storage that the node can use. An empty content returns storage of every
+// content type. Proxmox can only filter for enabled storage, with enabledOnly false both enabled and disabled
+// storage is returned.
+func GetNodeStorages(client *proxmox.Client, node string, content string, enabledOnly bool) ([]NodeStorage, error) {
+	query := url.Values{}
+	if content != "" {
+		query.Set("content", content)
+	}
+	if enabledOnly {
+		query.Set("enabled", "1")
+	}
+
+	path := proxmox.NodesPath + "/" + url.PathEscape(node) + "/" + StoragePath
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	storageModel := NodeStoragesResponse{}
+	err := doRequest(client, "GET", path, nil, &storageModel)
+	if err != nil {
+		return nil, fmt.Errorf("GetNodeStorages-%s: %w", node, err)
+	}
+
+	return storageModel.Data, nil
+}
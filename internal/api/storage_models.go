@@ -0,0 +1,101 @@
+package api
+
+// StoragesResponse The response from Proxmox when a list of storage definitions is returned
+type StoragesResponse struct {
+	Data []Storage `json:"data"`
+}
+
+// StorageResponse The response from Proxmox when a single storage definition is returned
+type StorageResponse struct {
+	Data Storage `json:"data"`
+}
+
+// StorageRequest The request that Proxmox expects when creating and modifying storage definitions.
+// Storage, Type and the attributes that locate the storage, such as Path, Export and VGName, can only be
+// sent on create, leave them empty when updating.
+type StorageRequest struct {
+	Storage       string  `json:"storage,omitempty"`
+	Type          string  `json:"type,omitempty"`
+	Content       *string `json:"content,omitempty"`
+	Nodes         *string `json:"nodes,omitempty"`
+	Disable       *Bool   `json:"disable,omitempty"`
+	Shared        *Bool   `json:"shared,omitempty"`
+	PruneBackups  *string `json:"prune-backups,omitempty"`
+	Path          *string `json:"path,omitempty"`
+	Server        *string `json:"server,omitempty"`
+	Export        *string `json:"export,omitempty"`
+	Options       *string `json:"options,omitempty"`
+	Share         *string `json:"share,omitempty"`
+	Domain        *string `json:"domain,omitempty"`
+	SMBVersion    *string `json:"smbversion,omitempty"`
+	Subdir        *string `json:"subdir,omitempty"`
+	Username      *string `json:"username,omitempty"`
+	Password      *string `json:"password,omitempty"`
+	VGName        *string `json:"vgname,omitempty"`
+	ThinPool      *string `json:"thinpool,omitempty"`
+	Pool          *string `json:"pool,omitempty"`
+	BlockSize     *string `json:"blocksize,omitempty"`
+	Sparse        *Bool   `json:"sparse,omitempty"`
+	MonHost       *string `json:"monhost,omitempty"`
+	Keyring       *string `json:"keyring,omitempty"`
+	KRBD          *Bool   `json:"krbd,omitempty"`
+	FSName        *string `json:"fs-name,omitempty"`
+	Datastore     *string `json:"datastore,omitempty"`
+	Namespace     *string `json:"namespace,omitempty"`
+	Fingerprint   *string `json:"fingerprint,omitempty"`
+	EncryptionKey *string `json:"encryption-key,omitempty"`
+	Delete        *string `json:"delete,omitempty"`
+}
+
+// Storage The structure that represents a Proxmox storage definition. Proxmox never returns
+// the password, keyring or encryption key.
+type Storage struct {
+	Storage      string  `json:"storage"`
+	Type         string  `json:"type"`
+	Content      *string `json:"content,omitempty"`
+	Nodes        *string `json:"nodes,omitempty"`
+	Disable      *Bool   `json:"disable,omitempty"`
+	Shared       *Bool   `json:"shared,omitempty"`
+	PruneBackups *string `json:"prune-backups,omitempty"`
+	Path         *string `json:"path,omitempty"`
+	Server       *string `json:"server,omitempty"`
+	Export       *string `json:"export,omitempty"`
+	Options      *string `json:"options,omitempty"`
+	Share        *string `json:"share,omitempty"`
+	Domain       *string `json:"domain,omitempty"`
+	SMBVersion   *string `json:"smbversion,omitempty"`
+	Subdir       *string `json:"subdir,omitempty"`
+	Username     *string `json:"username,omitempty"`
+	VGName       *string `json:"vgname,omitempty"`
+	ThinPool     *string `json:"thinpool,omitempty"`
+	Pool         *string `json:"pool,omitempty"`
+	BlockSize    *string `json:"blocksize,omitempty"`
+	Sparse       *Bool   `json:"sparse,omitempty"`
+	MonHost      *string `json:"monhost,omitempty"`
+	KRBD         *Bool   `json:"krbd,omitempty"`
+	FSName       *string `json:"fs-name,omitempty"`
+	Datastore    *string `json:"datastore,omitempty"`
+	Namespace    *string `json:"namespace,omitempty"`
+	Fingerprint  *string `json:"fingerprint,omitempty"`
+	Digest       string  `json:"digest,omitempty"`
+}
+
+// NodeStoragesResponse The response from Proxmox when the storage status of a node is returned
+type NodeStoragesResponse struct {
+	Data []NodeStorage `json:"data"`
+}
+
+// NodeStorage The status of a storage as seen by one node. The sizes are in bytes and are zero when the storage is
+// not active.
+type NodeStorage struct {
+	Storage      string  `json:"storage"`
+	Type         string  `json:"type"`
+	Content      string  `json:"content"`
+	Active       Bool    `json:"active"`
+	Enabled      Bool    `json:"enabled"`
+	Shared       Bool    `json:"shared"`
+	Total        Int     `json:"total"`
+	Used         Int     `json:"used"`
+	Available    Int     `json:"avail"`
+	UsedFraction float64 `json:"used_fraction"`
+}
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestStorageLifecycle(t *testing.T) {
+	fake := newFakeProxmox()
+	fake.collection(StoragePath, "storage")
+	client := newTestClient(t, fake)
+
+	content := "backup,iso"
+	server := "10.0.0.10"
+	export := "/srv/proxmox"
+	password := "secret"
+	prune := "keep-daily=7,keep-last=3"
+	storage, err := CreateStorage(client, &StorageRequest{
+		Storage:      "nas",
+		Type:         "nfs",
+		Content:      &content,
+		Server:       &server,
+		Export:       &export,
+		Password:     &password,
+		PruneBackups: &prune,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if storage.Storage != "nas" || storage.Type != "nfs" || *storage.Export != "/srv/proxmox" {
+		t.Errorf("Incorrect storage returned: %+v", storage)
+	}
+	if *storage.PruneBackups != prune {
+		t.Errorf("Incorrect retention returned. Expected %s, got %s", prune, *storage.PruneBackups)
+	}
+
+	remove := "prune-backups"
+	disable := true
+	nodes := "pve,pve2"
+	storage, err = UpdateStorage(client, "nas", &StorageRequest{Nodes: &nodes, Disable: NewBool(&disable), Delete: &remove})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if storage.PruneBackups != nil {
+		t.Errorf("Expected the retention to be removed, got %s", *storage.PruneBackups)
+	}
+	if storage.Disable == nil || !bool(*storage.Disable) || *storage.Nodes != nodes {
+		t.Errorf("Incorrect storage returned after the update: %+v", storage)
+	}
+
+	storages, err := GetStorages(client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(storages) != 1 {
+		t.Errorf("Expected 1 storage, got %d", len(storages))
+	}
+
+	err = DeleteStorage(client, "nas")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = GetStorage(client, "nas")
+	if err == nil {
+		t.Errorf("Expected an error reading a deleted storage")
+	}
+}
+
+func TestGetNodeStorages(t *testing.T) {
+	fake := newFakeProxmox()
+	fake.handle("GET nodes/pve/storage", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("content") != "images" || r.URL.Query().Get("enabled") != "1" {
+			http.Error(w, "unexpected filter "+r.URL.RawQuery, http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{"data":[
+			{"storage":"local-lvm","type":"lvmthin","content":"rootdir,images","active":1,"enabled":1,"shared":0,
+			 "total":107374182400,"used":32212254720,"avail":75161927680,"used_fraction":0.3},
+			{"storage":"nas","type":"nfs","content":"images,backup","active":0,"enabled":1,"shared":1}
+		]}`))
+	})
+	client := newTestClient(t, fake)
+
+	storages, err := GetNodeStorages(client, "pve", "images", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(storages) != 2 {
+		t.Fatalf("Expected 2 storages, got %d", len(storages))
+	}
+	if storages[0].Storage != "local-lvm" || !bool(storages[0].Active) || storages[0].Available != 75161927680 {
+		t.Errorf("Incorrect storage returned: %+v", storages[0])
+	}
+	if bool(storages[1].Active) || !bool(storages[1].Shared) || storages[1].Total != 0 {
+		t.Errorf("Incorrect inactive storage returned: %+v", storages[1])
+	}
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/clincha-org/proxmox-api/pkg/proxmox"
+)
+
+const TasksPath string = "/tasks"
+
+// TaskPollInterval is how long WaitForTask waits between two task status requests
+var TaskPollInterval = 2 * time.Second
+
+// ErrTaskTimeout is returned when a task is still running after the timeout given to WaitForTask
+var ErrTaskTimeout = errors.New("timed out waiting for task")
+
+// TaskNode returns the node that a task is running on. Proxmox encodes it as the second field of
+// the UPID, for example UPID:pve:000A1B2C:0123ABCD:66A0B1C2:qmcreate:100:root@pam:
+func TaskNode(upid string) (string, error) {
+	parts := strings.Split(upid, ":")
+	if len(parts) < 3 || parts[0] != "UPID" || parts[1] == "" {
+		return "", fmt.Errorf("TaskNode-parse-upid: %q is not a valid UPID", upid)
+	}
+	return parts[1], nil
+}
+
+func GetTaskStatus(client *proxmox.Client, upid string) (TaskStatus, error) {
+	node, err := TaskNode(upid)
+	if err != nil {
+		return TaskStatus{}, fmt.Errorf("GetTaskStatus: %w", err)
+	}
+
+	taskModel := TaskStatusResponse{}
+	err = doRequest(client, "GET", proxmox.NodesPath+"/"+node+TasksPath+"/"+url.PathEscape(upid)+"/status", nil, &taskModel)
+	if err != nil {
+		return TaskStatus{}, fmt.Errorf("GetTaskStatus-%s: %w", upid, err)
+	}
+
+	return taskModel.Data, nil
+}
+
+// WaitForTask polls the task until it stops and returns an error if it did not finish with an OK exit status
+func WaitForTask(client *proxmox.Client, upid string, timeout time.Duration) error {
+	// Some endpoints finish synchronously and return no task at all
+	if upid == "" {
+		return nil
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		status, err := GetTaskStatus(client, upid)
+		if err != nil {
+			return fmt.Errorf("WaitForTask: %w", err)
+		}
+
+		if status.Status == "stopped" {
+			if status.ExitStatus != "OK" && !strings.HasPrefix(status.ExitStatus, "WARNINGS") {
+				return fmt.Errorf("WaitForTask-%s: task failed: %s", upid, status.ExitStatus)
+			}
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("WaitForTask-%s: %w after %s", upid, ErrTaskTimeout, timeout)
+		}
+
+		time.Sleep(TaskPollInterval)
+	}
+}
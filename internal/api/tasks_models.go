@@ -0,0 +1,24 @@
+package api
+
+// TaskResponse The response from Proxmox when an endpoint starts a background task.
+// The data is the UPID of the task, which can be passed to WaitForTask.
+type TaskResponse struct {
+	Data string `json:"data"`
+}
+
+// TaskStatusResponse The response from Proxmox when the status of a task is returned
+type TaskStatusResponse struct {
+	Data TaskStatus `json:"data"`
+}
+
+// TaskStatus The structure that represents the status of a Proxmox task.
+// Status is "running" or "stopped" and ExitStatus is only set once the task has stopped.
+type TaskStatus struct {
+	UPID       string `json:"upid"`
+	Node       string `json:"node"`
+	Type       string `json:"type"`
+	ID         string `json:"id"`
+	User       string `json:"user"`
+	Status     string `json:"status"`
+	ExitStatus string `json:"exitstatus,omitempty"`
+}
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// Proxmox is not consistent about how it encodes scalars. The same attribute can be returned as
+// a number by one endpoint and as a string by another, and booleans are usually 0 or 1.
+// These types accept every encoding we have seen so the models can stay strongly typed.
+
+// Int An integer that may be encoded as a JSON number or a JSON string
+type Int int64
+
+func (i *Int) UnmarshalJSON(data []byte) error {
+	data = bytes.Trim(data, `"`)
+	if len(data) == 0 || string(data) == "null" {
+		return nil
+	}
+	value, err := strconv.ParseFloat(string(data), 64)
+	if err != nil {
+		return fmt.Errorf("Int-unmarshal %s: %w", data, err)
+	}
+	*i = Int(value)
+	return nil
+}
+
+// Pointer returns the value as an *int64 so that it can be handed to the Terraform types
+func (i *Int) Pointer() *int64 {
+	if i == nil {
+		return nil
+	}
+	value := int64(*i)
+	return &value
+}
+
+// Bool A boolean that may be encoded as true/false, 0/1 or "0"/"1"
+type Bool bool
+
+func (b *Bool) UnmarshalJSON(data []byte) error {
+	data = bytes.Trim(data, `"`)
+	switch string(data) {
+	case "", "null":
+		return nil
+	case "1", "true", "on", "yes":
+		*b = true
+	case "0", "false", "off", "no":
+		*b = false
+	default:
+		return fmt.Errorf("Bool-unmarshal: unexpected value %s", data)
+	}
+	return nil
+}
+
+// MarshalJSON encodes the boolean as 0 or 1, which every Proxmox endpoint accepts
+func (b Bool) MarshalJSON() ([]byte, error) {
+	if b {
+		return []byte("1"), nil
+	}
+	return []byte("0"), nil
+}
+
+// Pointer returns the value as a *bool so that it can be handed to the Terraform types
+func (b *Bool) Pointer() *bool {
+	if b == nil {
+		return nil
+	}
+	value := bool(*b)
+	return &value
+}
+
+// NewBool converts an optional Terraform boolean into an optional API boolean
+func NewBool(value *bool) *Bool {
+	if value == nil {
+		return nil
+	}
+	b := Bool(*value)
+	return &b
+}
+
+var _ json.Unmarshaler = (*Int)(nil)
+var _ json.Unmarshaler = (*Bool)(nil)
+var _ json.Marshaler = Bool(false)
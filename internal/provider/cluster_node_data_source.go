@@ -0,0 +1,86 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+
+	"github.com/clincha-org/proxmox-api/pkg/proxmox"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+)
+
+var (
+	_ datasource.DataSource              = &clusterNodeDataSource{}
+	_ datasource.DataSourceWithConfigure = &clusterNodeDataSource{}
+)
+
+// clusterNodeDataSource returns a single node with its attributes at the top level, so that
+// configurations do not need to search the list returned by the proxmox_node data source.
+type clusterNodeDataSource struct {
+	client *proxmox.Client
+}
+
+func NewClusterNodeDataSource() datasource.DataSource {
+	return &clusterNodeDataSource{}
+}
+
+func (d *clusterNodeDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_cluster_node"
+}
+
+func (d *clusterNodeDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: nodeAttributes(),
+	}
+	resp.Schema.Attributes["node"] = schema.StringAttribute{
+		Required: true,
+	}
+}
+
+func (d *clusterNodeDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config Node
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	nodes, err := d.client.GetNodes()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to read Proxmox nodes",
+			err.Error(),
+		)
+		return
+	}
+
+	node, err := findNode(nodes, config.Node.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("node"), "Proxmox node not found", err.Error())
+		return
+	}
+
+	diags = resp.State.Set(ctx, nodeState(node))
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (d *clusterNodeDataSource) Configure(_ context.Context, request datasource.ConfigureRequest, response *datasource.ConfigureResponse) {
+	if request.ProviderData == nil {
+		return
+	}
+
+	client, ok := request.ProviderData.(*proxmox.Client)
+	if !ok {
+		response.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *proxmox.Client, got %T. Please report this error to the developer", request.ProviderData),
+		)
+		return
+	}
+
+	d.client = client
+}
@@ -0,0 +1,27 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"regexp"
+	"testing"
+)
+
+func TestClusterNodeDataSource_Read(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `data "proxmox_cluster_node" "test" { node = "pve" }`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.proxmox_cluster_node.test", "id", "node/pve"),
+					resource.TestCheckResourceAttr("data.proxmox_cluster_node.test", "node", "pve"),
+					resource.TestCheckResourceAttr("data.proxmox_cluster_node.test", "status", "online"),
+				),
+			},
+			{
+				Config:      providerConfig + `data "proxmox_cluster_node" "test" { node = "missing" }`,
+				ExpectError: regexp.MustCompile("Proxmox node not found"),
+			},
+		},
+	})
+}
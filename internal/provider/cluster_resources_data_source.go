@@ -0,0 +1,276 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/clincha-org/proxmox-api/pkg/proxmox"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"slices"
+	"strings"
+	"terraform-provider-proxmox/internal/api"
+)
+
+var (
+	_ datasource.DataSource                   = &clusterResourcesDataSource{}
+	_ datasource.DataSourceWithConfigure      = &clusterResourcesDataSource{}
+	_ datasource.DataSourceWithValidateConfig = &clusterResourcesDataSource{}
+)
+
+// clusterResourceTypes are the types that /cluster/resources can be filtered by
+var clusterResourceTypes = []string{"vm", "storage", "node", "sdn", "pool"}
+
+type ClusterResourcesDataSourceModel struct {
+	Type      types.String           `tfsdk:"type"`
+	Resources []ClusterResourceModel `tfsdk:"resources"`
+}
+
+// ClusterResourceModel A resource in the cluster. Attributes that do not apply to its type are null or zero.
+type ClusterResourceModel struct {
+	ID         types.String  `tfsdk:"id"`
+	Type       types.String  `tfsdk:"type"`
+	Node       types.String  `tfsdk:"node"`
+	Status     types.String  `tfsdk:"status"`
+	Name       types.String  `tfsdk:"name"`
+	VMID       types.Int64   `tfsdk:"vmid"`
+	Pool       types.String  `tfsdk:"pool"`
+	Tags       types.List    `tfsdk:"tags"`
+	Template   types.Bool    `tfsdk:"template"`
+	HAState    types.String  `tfsdk:"ha_state"`
+	Storage    types.String  `tfsdk:"storage"`
+	PluginType types.String  `tfsdk:"plugin_type"`
+	Content    types.List    `tfsdk:"content"`
+	Shared     types.Bool    `tfsdk:"shared"`
+	SDN        types.String  `tfsdk:"sdn"`
+	Level      types.String  `tfsdk:"level"`
+	CPU        types.Float64 `tfsdk:"cpu"`
+	MaxCPU     types.Int64   `tfsdk:"max_cpu"`
+	Memory     types.Int64   `tfsdk:"memory"`
+	MaxMemory  types.Int64   `tfsdk:"max_memory"`
+	Disk       types.Int64   `tfsdk:"disk"`
+	MaxDisk    types.Int64   `tfsdk:"max_disk"`
+	Uptime     types.Int64   `tfsdk:"uptime"`
+}
+
+// clusterResourcesDataSource returns the whole inventory of the cluster with a single request, instead of a
+// request per node
+type clusterResourcesDataSource struct {
+	client *proxmox.Client
+}
+
+func NewClusterResourcesDataSource() datasource.DataSource {
+	return &clusterResourcesDataSource{}
+}
+
+func (d *clusterResourcesDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_cluster_resources"
+}
+
+func (d *clusterResourcesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"type": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only return resources of this type, one of vm, storage, node, sdn or pool. The vm type includes containers",
+			},
+			"resources": schema.ListNestedAttribute{
+				Computed: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Computed:    true,
+							Description: "The ID of the resource, for example qemu/100 or storage/pve/local",
+						},
+						"type": schema.StringAttribute{
+							Computed:    true,
+							Description: "One of qemu, lxc, storage, node, sdn or pool",
+						},
+						"node": schema.StringAttribute{
+							Computed: true,
+						},
+						"status": schema.StringAttribute{
+							Computed: true,
+						},
+						"name": schema.StringAttribute{
+							Computed: true,
+						},
+						"vmid": schema.Int64Attribute{
+							Computed: true,
+						},
+						"pool": schema.StringAttribute{
+							Computed: true,
+						},
+						"tags": schema.ListAttribute{
+							Computed:    true,
+							ElementType: types.StringType,
+						},
+						"template": schema.BoolAttribute{
+							Computed: true,
+						},
+						"ha_state": schema.StringAttribute{
+							Computed:    true,
+							Description: "The state of the guest in the HA manager, for example started",
+						},
+						"storage": schema.StringAttribute{
+							Computed: true,
+						},
+						"plugin_type": schema.StringAttribute{
+							Computed:    true,
+							Description: "The type of the storage, for example lvmthin or nfs",
+						},
+						"content": schema.ListAttribute{
+							Computed:    true,
+							ElementType: types.StringType,
+							Description: "The content types of the storage, for example images and rootdir",
+						},
+						"shared": schema.BoolAttribute{
+							Computed: true,
+						},
+						"sdn": schema.StringAttribute{
+							Computed:    true,
+							Description: "The name of the SDN zone",
+						},
+						"level": schema.StringAttribute{
+							Computed:    true,
+							Description: "The support level of the node",
+						},
+						"cpu": schema.Float64Attribute{
+							Computed: true,
+						},
+						"max_cpu": schema.Int64Attribute{
+							Computed: true,
+						},
+						"memory": schema.Int64Attribute{
+							Computed: true,
+						},
+						"max_memory": schema.Int64Attribute{
+							Computed: true,
+						},
+						"disk": schema.Int64Attribute{
+							Computed: true,
+						},
+						"max_disk": schema.Int64Attribute{
+							Computed: true,
+						},
+						"uptime": schema.Int64Attribute{
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *clusterResourcesDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	var config ClusterResourcesDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() || config.Type.IsNull() || config.Type.IsUnknown() {
+		return
+	}
+
+	if !slices.Contains(clusterResourceTypes, config.Type.ValueString()) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("type"),
+			"Unsupported cluster resource type",
+			fmt.Sprintf("The type must be one of %s. Got: %q", strings.Join(clusterResourceTypes, ", "), config.Type.ValueString()),
+		)
+	}
+}
+
+func (d *clusterResourcesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state ClusterResourcesDataSourceModel
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resources, err := api.GetClusterResources(d.client, state.Type.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to read Proxmox cluster resources",
+			err.Error(),
+		)
+		return
+	}
+
+	state.Resources = []ClusterResourceModel{}
+	for _, resource := range resources {
+		state.Resources = append(state.Resources, clusterResourceState(resource))
+	}
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func clusterResourceState(resource api.ClusterResource) ClusterResourceModel {
+	state := ClusterResourceModel{
+		ID:         types.StringValue(resource.ID),
+		Type:       types.StringValue(resource.Type),
+		Node:       optionalString(resource.Node),
+		Status:     optionalString(resource.Status),
+		Name:       optionalString(resource.Name),
+		VMID:       types.Int64Null(),
+		Pool:       optionalString(resource.Pool),
+		Tags:       stringList(splitTags(resource.Tags)),
+		Content:    stringList(strings.Split(resource.Content, ",")),
+		Template:   types.BoolValue(bool(resource.Template)),
+		HAState:    optionalString(resource.HAState),
+		Storage:    optionalString(resource.Storage),
+		PluginType: optionalString(resource.PluginType),
+		Shared:     types.BoolValue(bool(resource.Shared)),
+		SDN:        optionalString(resource.SDN),
+		Level:      optionalString(resource.Level),
+		CPU:        types.Float64Value(resource.CPU),
+		MaxCPU:     types.Int64Value(int64(resource.MaxCPU)),
+		Memory:     types.Int64Value(int64(resource.Mem)),
+		MaxMemory:  types.Int64Value(int64(resource.MaxMem)),
+		Disk:       types.Int64Value(int64(resource.Disk)),
+		MaxDisk:    types.Int64Value(int64(resource.MaxDisk)),
+		Uptime:     types.Int64Value(int64(resource.Uptime)),
+	}
+	if resource.VMID != 0 {
+		state.VMID = types.Int64Value(int64(resource.VMID))
+	}
+	return state
+}
+
+// optionalString converts an empty string, which Proxmox leaves out, into null
+func optionalString(value string) types.String {
+	if value == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(value)
+}
+
+// stringList converts the strings into a list, leaving out empty strings
+func stringList(values []string) types.List {
+	elements := []attr.Value{}
+	for _, value := range values {
+		if value != "" {
+			elements = append(elements, types.StringValue(value))
+		}
+	}
+	return types.ListValueMust(types.StringType, elements)
+}
+
+func (d *clusterResourcesDataSource) Configure(_ context.Context, request datasource.ConfigureRequest, response *datasource.ConfigureResponse) {
+	if request.ProviderData == nil {
+		return
+	}
+
+	client, ok := request.ProviderData.(*proxmox.Client)
+	if !ok {
+		response.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *proxmox.Client, got %T. Please report this error to the developer", request.ProviderData),
+		)
+		return
+	}
+
+	d.client = client
+}
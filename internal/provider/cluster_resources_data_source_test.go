@@ -0,0 +1,52 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"regexp"
+	"terraform-provider-proxmox/internal/api"
+	"testing"
+)
+
+func TestClusterResourcesDataSource_Read(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `data "proxmox_cluster_resources" "test" { type = "node" }`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.proxmox_cluster_resources.test", "resources.0.type", "node"),
+					resource.TestCheckResourceAttrSet("data.proxmox_cluster_resources.test", "resources.0.node"),
+					resource.TestCheckNoResourceAttr("data.proxmox_cluster_resources.test", "resources.0.vmid"),
+				),
+			},
+			{
+				Config:      providerConfig + `data "proxmox_cluster_resources" "test" { type = "qemu" }`,
+				ExpectError: regexp.MustCompile("Unsupported cluster resource type"),
+			},
+		},
+	})
+}
+
+func TestClusterResourceState(t *testing.T) {
+	storage := clusterResourceState(api.ClusterResource{
+		ID:         "storage/pve/local-lvm",
+		Type:       "storage",
+		Node:       "pve",
+		Status:     "available",
+		Storage:    "local-lvm",
+		PluginType: "lvmthin",
+		Content:    "images,rootdir",
+		MaxDisk:    107374182400,
+	})
+	if !storage.VMID.IsNull() || !storage.Name.IsNull() || storage.Storage.ValueString() != "local-lvm" || storage.MaxDisk.ValueInt64() != 107374182400 {
+		t.Errorf("Incorrect storage: %+v", storage)
+	}
+	if len(storage.Content.Elements()) != 2 || len(storage.Tags.Elements()) != 0 {
+		t.Errorf("Incorrect storage content or tags: %v %v", storage.Content, storage.Tags)
+	}
+
+	guest := clusterResourceState(api.ClusterResource{ID: "qemu/100", Type: "qemu", Node: "pve", VMID: 100, Name: "web", Tags: "debian;web", Template: true})
+	if guest.VMID.ValueInt64() != 100 || guest.Name.ValueString() != "web" || !guest.Template.ValueBool() || len(guest.Tags.Elements()) != 2 || len(guest.Content.Elements()) != 0 {
+		t.Errorf("Incorrect guest: %+v", guest)
+	}
+}
@@ -0,0 +1,230 @@
+package provider
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"terraform-provider-proxmox/internal/api"
+	"time"
+)
+
+// ContainerCloneModel The container or template that a container is cloned from.
+// The new container is created on the node of the resource.
+type ContainerCloneModel struct {
+	SourceVMID types.Int64  `tfsdk:"source_vmid"`
+	SourceName types.String `tfsdk:"source_name"`
+	SourceNode types.String `tfsdk:"source_node"`
+	Full       types.Bool   `tfsdk:"full"`
+	Storage    types.String `tfsdk:"storage"`
+	Pool       types.String `tfsdk:"pool"`
+	Timeout    types.Int64  `tfsdk:"timeout"`
+}
+
+func containerCloneBlock() schema.SingleNestedBlock {
+	return schema.SingleNestedBlock{
+		Description: "Create the container by cloning another container or template instead of an OS template, the rest of the configuration is applied to the clone",
+		Attributes: map[string]schema.Attribute{
+			"source_vmid": schema.Int64Attribute{
+				Optional:    true,
+				Description: "The VMID to clone. Exactly one of source_vmid and source_name must be set",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"source_name": schema.StringAttribute{
+				Optional:    true,
+				Description: "The hostname of the container to clone, it must be unique on the source node",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"source_node": schema.StringAttribute{
+				Optional:    true,
+				Description: "The node of the container to clone. Defaults to the node of the new container",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"full": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(true),
+				Description: "Copy the volumes instead of creating a linked clone. Linked clones can only be made from templates",
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"storage": schema.StringAttribute{
+				Optional:    true,
+				Description: "The storage for the volumes of a full clone. Defaults to the storage of the source volumes",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"pool": schema.StringAttribute{
+				Optional:    true,
+				Description: "The resource pool to add the new container to",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"timeout": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(1800),
+				Description: "The number of seconds to wait for the clone to finish",
+			},
+		},
+	}
+}
+
+// validateContainerClone checks the clone block together with the attributes that are only used to create a
+// container from an OS template
+func validateContainerClone(config ContainerResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if config.Clone == nil {
+		if config.OSTemplate.IsNull() {
+			diags.AddAttributeError(
+				path.Root("ostemplate"),
+				"Missing OS template",
+				"Set ostemplate, or add a clone block to create the container from another container",
+			)
+		}
+		return diags
+	}
+
+	diags.Append(validateCloneSource(config.Clone.SourceVMID, config.Clone.SourceName, config.Clone.Full, config.Clone.Storage)...)
+	for name, value := range map[string]interface{ IsNull() bool }{
+		"ostemplate":      config.OSTemplate,
+		"password":        config.Password,
+		"ssh_public_keys": config.SSHPublicKeys,
+	} {
+		if !value.IsNull() {
+			diags.AddAttributeError(
+				path.Root(name),
+				"Attribute not supported when cloning",
+				fmt.Sprintf("The %s is only used to create a container from an OS template, a clone keeps the one of its source", name),
+			)
+		}
+	}
+	return diags
+}
+
+// findContainerByName returns the VMID of the only container with the hostname
+func findContainerByName(containers []api.ContainerStatus, name string) (int64, error) {
+	var matches []int64
+	for _, container := range containers {
+		if container.Name == name {
+			matches = append(matches, int64(container.VMID))
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return 0, fmt.Errorf("no container is called %s", name)
+	case 1:
+		return matches[0], nil
+	default:
+		return 0, fmt.Errorf("%d containers are called %s: %v", len(matches), name, matches)
+	}
+}
+
+// cloneContainer clones the source of the clone block to the VMID of the plan and waits for it to finish
+func (r *containerResource) cloneContainer(plan ContainerResourceModel) error {
+	clone := plan.Clone
+	sourceNode := plan.Node.ValueString()
+	if !clone.SourceNode.IsNull() {
+		sourceNode = clone.SourceNode.ValueString()
+	}
+
+	sourceVMID := clone.SourceVMID.ValueInt64()
+	if clone.SourceVMID.IsNull() {
+		containers, err := api.GetContainers(r.client, sourceNode)
+		if err != nil {
+			return err
+		}
+		sourceVMID, err = findContainerByName(containers, clone.SourceName.ValueString())
+		if err != nil {
+			return fmt.Errorf("could not find the source on %s: %w", sourceNode, err)
+		}
+	}
+
+	cloneRequest := api.ContainerCloneRequest{
+		NewID:   plan.VMID.ValueInt64(),
+		Full:    api.NewBool(clone.Full.ValueBoolPointer()),
+		Storage: clone.Storage.ValueStringPointer(),
+		Pool:    clone.Pool.ValueStringPointer(),
+	}
+	if !plan.Hostname.IsUnknown() {
+		cloneRequest.Hostname = plan.Hostname.ValueStringPointer()
+	}
+	if sourceNode != plan.Node.ValueString() {
+		cloneRequest.Target = plan.Node.ValueStringPointer()
+	}
+
+	upid, err := api.CloneContainer(r.client, sourceNode, sourceVMID, &cloneRequest)
+	if err != nil {
+		return err
+	}
+	return api.WaitForTask(r.client, upid, time.Duration(clone.Timeout.ValueInt64())*time.Second)
+}
+
+// planContainerCloneDefaults keeps the settings of the source of a clone that the configuration does not set
+func planContainerCloneDefaults(plan *ContainerResourceModel, config ContainerResourceModel, state ContainerResourceModel, exists bool) {
+	sourceValue(&plan.Unprivileged, config.Unprivileged, state.Unprivileged, types.BoolUnknown(), exists)
+	sourceValue(&plan.Memory, config.Memory, state.Memory, types.Int64Unknown(), exists)
+	sourceValue(&plan.Swap, config.Swap, state.Swap, types.Int64Unknown(), exists)
+	sourceValue(&plan.OnBoot, config.OnBoot, state.OnBoot, types.BoolUnknown(), exists)
+}
+
+// setContainerCloneDefaults sets the settings that planContainerCloneDefaults left unknown to those of the clone
+func setContainerCloneDefaults(plan *ContainerResourceModel, clone ContainerResourceModel) {
+	knownValue(&plan.Unprivileged, clone.Unprivileged)
+	knownValue(&plan.Memory, clone.Memory)
+	knownValue(&plan.Swap, clone.Swap)
+	knownValue(&plan.OnBoot, clone.OnBoot)
+}
+
+// keepContainerDeclared leaves the settings and devices of a cloned container that the prior model does not declare
+// out of the model, like keepDeclared does for virtual machines. Mount points are declared by their slot and
+// network interfaces by their position.
+func keepContainerDeclared(model *ContainerResourceModel, prior ContainerResourceModel) {
+	if prior.Cores.IsNull() {
+		model.Cores = types.Int64Null()
+	}
+	if prior.Tags.IsNull() {
+		model.Tags = types.SetNull(types.StringType)
+	}
+	if prior.Description.IsNull() {
+		model.Description = types.StringNull()
+	}
+	if prior.Features == nil {
+		model.Features = nil
+	}
+	if prior.Startup == nil {
+		model.Startup = nil
+	}
+
+	declared := map[int64]bool{}
+	for _, mountPoint := range prior.MountPoints {
+		declared[mountPoint.Slot.ValueInt64()] = true
+	}
+	mountPoints := []ContainerMountPointModel{}
+	for _, mountPoint := range model.MountPoints {
+		if declared[mountPoint.Slot.ValueInt64()] {
+			mountPoints = append(mountPoints, mountPoint)
+		}
+	}
+	model.MountPoints = mountPoints
+
+	model.NetworkInterfaces = model.NetworkInterfaces[:min(len(model.NetworkInterfaces), len(prior.NetworkInterfaces))]
+}
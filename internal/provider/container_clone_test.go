@@ -0,0 +1,186 @@
+package provider
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"reflect"
+	"terraform-provider-proxmox/internal/api"
+	"testing"
+)
+
+func TestFindContainerByName(t *testing.T) {
+	containers := []api.ContainerStatus{
+		{VMID: 9100, Name: "debian-template"},
+		{VMID: 200, Name: "dns"},
+		{VMID: 201, Name: "dns"},
+	}
+
+	vmid, err := findContainerByName(containers, "debian-template")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vmid != 9100 {
+		t.Errorf("Incorrect VMID returned. Expected 9100, got %d", vmid)
+	}
+
+	_, err = findContainerByName(containers, "dns")
+	if err == nil {
+		t.Error("Expected an error when more than one container has the name")
+	}
+
+	_, err = findContainerByName(containers, "web")
+	if err == nil {
+		t.Error("Expected an error when no container has the name")
+	}
+}
+
+func TestValidateContainerClone(t *testing.T) {
+	config := ContainerResourceModel{
+		OSTemplate:    types.StringNull(),
+		Password:      types.StringNull(),
+		SSHPublicKeys: types.ListNull(types.StringType),
+	}
+	if diags := validateContainerClone(config); diags.ErrorsCount() != 1 {
+		t.Errorf("Expected an error without an OS template or clone block, got %v", diags)
+	}
+
+	config.Clone = &ContainerCloneModel{
+		SourceVMID: types.Int64Value(9100),
+		SourceName: types.StringNull(),
+		Full:       types.BoolNull(),
+		Storage:    types.StringNull(),
+	}
+	if diags := validateContainerClone(config); diags.HasError() {
+		t.Errorf("Expected the clone to be valid, got %v", diags)
+	}
+
+	config.OSTemplate = types.StringValue("local:vztmpl/debian-12-standard_12.7-1_amd64.tar.zst")
+	config.Password = types.StringValue("secret")
+	if diags := validateContainerClone(config); diags.ErrorsCount() != 2 {
+		t.Errorf("Expected errors for the OS template and password of a clone, got %v", diags)
+	}
+
+	config.OSTemplate = types.StringNull()
+	config.Password = types.StringNull()
+	config.Clone.Full = types.BoolValue(false)
+	config.Clone.Storage = types.StringValue("local-lvm")
+	if diags := validateContainerClone(config); diags.ErrorsCount() != 1 {
+		t.Errorf("Expected an error for storage on a linked clone, got %v", diags)
+	}
+}
+
+func TestContainerCloneChanges(t *testing.T) {
+	swap := int64(1024)
+	tests := []struct {
+		name      string
+		configure func(config *ContainerResourceModel)
+		expected  api.ContainerRequest
+	}{
+		{
+			name:      "minimal configuration",
+			configure: func(config *ContainerResourceModel) {},
+			expected:  api.ContainerRequest{Devices: map[string]string{}},
+		},
+		{
+			name: "configured swap and network interface",
+			configure: func(config *ContainerResourceModel) {
+				config.Swap = types.Int64Value(swap)
+				config.NetworkInterfaces = []ContainerNetworkInterfaceModel{{
+					Name:     types.StringValue("eth0"),
+					Bridge:   types.StringValue("vmbr2"),
+					IP:       types.StringValue("dhcp"),
+					Firewall: types.BoolValue(false),
+					MAC:      types.StringUnknown(),
+				}}
+			},
+			expected: api.ContainerRequest{
+				Swap:    &swap,
+				Devices: map[string]string{"net0": "bridge=vmbr2,hwaddr=BC:24:11:00:00:01,ip=dhcp,name=eth0"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := ContainerResourceModel{
+				Node:              types.StringValue("pve"),
+				VMID:              types.Int64Value(200),
+				Clone:             &ContainerCloneModel{SourceVMID: types.Int64Value(9100)},
+				RootFS:            &ContainerRootFSModel{Storage: types.StringValue("local-lvm"), Size: types.Int64Value(8), Volume: types.StringUnknown()},
+				MountPoints:       []ContainerMountPointModel{},
+				NetworkInterfaces: []ContainerNetworkInterfaceModel{},
+			}
+			test.configure(&config)
+
+			// The framework plans the defaults of the schema for the attributes that are not configured
+			plan := config
+			plan.Hostname = types.StringUnknown()
+			plan.Unprivileged = types.BoolValue(true)
+			plan.Memory = types.Int64Value(512)
+			if plan.Swap.IsNull() {
+				plan.Swap = types.Int64Value(512)
+			}
+			plan.OnBoot = types.BoolValue(false)
+			planContainerCloneDefaults(&plan, config, ContainerResourceModel{}, false)
+
+			clone := readContainerTemplateClone(t, plan)
+			setContainerCloneDefaults(&plan, clone)
+			if plan.Unprivileged.ValueBool() || plan.Memory.ValueInt64() != 2048 || !plan.OnBoot.ValueBool() {
+				t.Errorf("Expected the settings of the template, got %+v", plan)
+			}
+
+			changes, diags := planContainerChanges(context.Background(), plan, clone)
+			if diags.HasError() {
+				t.Fatal(diags)
+			}
+			if !reflect.DeepEqual(changes.request, test.expected) {
+				t.Errorf("Incorrect request. Expected %+v, got %+v", test.expected, changes.request)
+			}
+			if len(changes.disks.removed) > 0 || len(changes.disks.removedVolumes) > 0 {
+				t.Errorf("Expected the mount points of the template to be kept, got %+v", changes.disks)
+			}
+		})
+	}
+}
+
+// readContainerTemplateClone reads a clone of a privileged template with features, a mount point and two network
+// interfaces like readContainer does
+func readContainerTemplateClone(t *testing.T, plan ContainerResourceModel) ContainerResourceModel {
+	features, startup := "nesting=1,keyctl=1", "order=2"
+	devices := map[string]string{
+		"rootfs": "local-lvm:vm-200-disk-0,size=8G",
+		"mp0":    "local-lvm:vm-200-disk-1,mp=/srv,size=4G",
+		"net0":   "name=eth0,bridge=vmbr0,hwaddr=BC:24:11:00:00:01,ip=dhcp,type=veth",
+		"net1":   "name=eth1,bridge=vmbr1,hwaddr=BC:24:11:00:00:02,ip6=auto,type=veth",
+	}
+
+	clone := plan
+	clone.Hostname = types.StringValue("debian")
+	clone.Unprivileged = types.BoolValue(false)
+	clone.Cores = types.Int64Value(2)
+	clone.Memory = types.Int64Value(2048)
+	clone.Swap = types.Int64Value(0)
+	clone.OnBoot = types.BoolValue(true)
+	clone.Features = readFeatures(&features, nil)
+	clone.Description = types.StringValue("Debian 12")
+	clone.Tags = types.SetValueMust(types.StringType, []attr.Value{types.StringValue("debian")})
+
+	var err error
+	clone.Startup, err = readStartup(&startup, nil)
+	if err == nil {
+		clone.RootFS, err = readRootFS(devices)
+	}
+	if err == nil {
+		clone.MountPoints, err = readMountPoints(devices)
+	}
+	if err == nil {
+		clone.NetworkInterfaces, err = readContainerNetworkInterfaces(devices)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keepContainerDeclared(&clone, plan)
+	return clone
+}
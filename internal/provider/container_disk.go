@@ -0,0 +1,471 @@
+package provider
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"terraform-provider-proxmox/internal/api"
+)
+
+// mountPointKey matches the configuration keys that hold a mount point
+var mountPointKey = regexp.MustCompile(`^mp(\d+)$`)
+
+// ContainerRootFSModel The volume that holds the root filesystem of the container
+type ContainerRootFSModel struct {
+	Storage types.String `tfsdk:"storage"`
+	Size    types.Int64  `tfsdk:"size"`
+	Volume  types.String `tfsdk:"volume"`
+}
+
+// ContainerMountPointModel A mount point of the container, the block with slot N is configured as mpN. A volume
+// is allocated on a storage and belongs to the container, a bind mount or device mount is a directory or block
+// device of the node that is mounted into the container.
+type ContainerMountPointModel struct {
+	Slot     types.Int64  `tfsdk:"slot"`
+	Type     types.String `tfsdk:"type"`
+	Storage  types.String `tfsdk:"storage"`
+	Size     types.Int64  `tfsdk:"size"`
+	Source   types.String `tfsdk:"source"`
+	Path     types.String `tfsdk:"path"`
+	Backup   types.Bool   `tfsdk:"backup"`
+	ReadOnly types.Bool   `tfsdk:"read_only"`
+	Shared   types.Bool   `tfsdk:"shared"`
+	Volume   types.String `tfsdk:"volume"`
+}
+
+func containerRootFSBlock() schema.SingleNestedBlock {
+	return schema.SingleNestedBlock{
+		Description: "The root filesystem of the container. Required",
+		Attributes: map[string]schema.Attribute{
+			"storage": schema.StringAttribute{
+				Optional:    true,
+				Description: "The storage of the root filesystem, for example local-lvm. Required when the block is set",
+			},
+			"size": schema.Int64Attribute{
+				Optional:    true,
+				Description: "The size in GiB. The root filesystem can grow in place but can not shrink. Required when the block is set",
+			},
+			"volume": schema.StringAttribute{
+				Computed:    true,
+				Description: "The volume that backs the root filesystem, for example local-lvm:vm-100-disk-0",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func containerMountPointBlock() schema.ListNestedBlock {
+	return schema.ListNestedBlock{
+		NestedObject: schema.NestedBlockObject{
+			Attributes: map[string]schema.Attribute{
+				"slot": schema.Int64Attribute{
+					Required:    true,
+					Description: "The number of the mount point, slot 0 is configured as mp0",
+				},
+				"type": schema.StringAttribute{
+					Optional:    true,
+					Computed:    true,
+					Default:     stringdefault.StaticString("volume"),
+					Description: "Either volume for a volume on a storage, bind for a directory of the node or device for a block device of the node",
+				},
+				"storage": schema.StringAttribute{
+					Optional:    true,
+					Description: "The storage of the volume, for example local-lvm. Required for volumes",
+				},
+				"size": schema.Int64Attribute{
+					Optional:    true,
+					Description: "The size of the volume in GiB. Volumes can grow in place but can not shrink. Required for volumes",
+				},
+				"source": schema.StringAttribute{
+					Optional:    true,
+					Description: "The directory or block device on the node, for example /mnt/media or /dev/sdb1. Required for bind and device mounts",
+				},
+				"path": schema.StringAttribute{
+					Required:    true,
+					Description: "Where the mount point is mounted in the container, for example /var/lib/data",
+				},
+				"backup": schema.BoolAttribute{
+					Optional:    true,
+					Computed:    true,
+					Default:     booldefault.StaticBool(false),
+					Description: "Include the volume in backups of the container. Bind and device mounts are never backed up",
+				},
+				"read_only": schema.BoolAttribute{
+					Optional: true,
+					Computed: true,
+					Default:  booldefault.StaticBool(false),
+				},
+				"shared": schema.BoolAttribute{
+					Optional:    true,
+					Computed:    true,
+					Default:     booldefault.StaticBool(false),
+					Description: "The source of a bind or device mount is available on every node, so it is not copied when the container is migrated",
+				},
+				"volume": schema.StringAttribute{
+					Computed:    true,
+					Description: "The volume that backs the mount point, for example local-lvm:vm-100-disk-1. The source for bind and device mounts",
+				},
+			},
+		},
+	}
+}
+
+// newRootFSValue is the property string that allocates the root filesystem, for example local-lvm:8
+func newRootFSValue(rootFS *ContainerRootFSModel) string {
+	return fmt.Sprintf("%s:%d", rootFS.Storage.ValueString(), rootFS.Size.ValueInt64())
+}
+
+// mountPointOptions returns the properties of the mount point other than its volume and size
+func mountPointOptions(mountPoint ContainerMountPointModel) api.PropertyString {
+	properties := api.PropertyString{"mp": mountPoint.Path.ValueString()}
+	if mountPoint.Backup.ValueBool() {
+		properties.SetFlag("backup", true)
+	}
+	if mountPoint.ReadOnly.ValueBool() {
+		properties.SetFlag("ro", true)
+	}
+	if mountPoint.Shared.ValueBool() {
+		properties.SetFlag("shared", true)
+	}
+	return properties
+}
+
+// newMountPointValue is the property string of a new mount point. For a volume it allocates a new volume, for
+// example local-lvm:8,mp=/data, and for bind and device mounts it is the source, for example /mnt/media,mp=/media.
+func newMountPointValue(mountPoint ContainerMountPointModel) string {
+	properties := mountPointOptions(mountPoint)
+	if mountPoint.Type.ValueString() == "volume" {
+		properties[""] = fmt.Sprintf("%s:%d", mountPoint.Storage.ValueString(), mountPoint.Size.ValueInt64())
+	} else {
+		properties[""] = mountPoint.Source.ValueString()
+	}
+	return properties.String()
+}
+
+// existingMountPointValue is the property string that changes the options of an existing volume
+func existingMountPointValue(mountPoint ContainerMountPointModel, prior ContainerMountPointModel) string {
+	properties := mountPointOptions(mountPoint)
+	properties[""] = prior.Volume.ValueString()
+	properties["size"] = fmt.Sprintf("%dG", prior.Size.ValueInt64())
+	return properties.String()
+}
+
+// sameMountPointSource reports whether the planned mount point still uses the volume or source of the existing one.
+// A volume that changes type or storage is a new volume, the old one is removed.
+func sameMountPointSource(mountPoint ContainerMountPointModel, existing ContainerMountPointModel) bool {
+	if mountPoint.Type.ValueString() != existing.Type.ValueString() {
+		return false
+	}
+	if mountPoint.Type.ValueString() == "volume" {
+		return mountPoint.Storage.ValueString() == existing.Storage.ValueString()
+	}
+	return mountPoint.Source.ValueString() == existing.Source.ValueString()
+}
+
+func mountPointName(slot int64) string {
+	return "mp" + strconv.FormatInt(slot, 10)
+}
+
+// readRootFS converts the rootfs entry of the configuration into the model
+func readRootFS(devices map[string]string) (*ContainerRootFSModel, error) {
+	value, ok := devices["rootfs"]
+	if !ok {
+		return nil, nil
+	}
+
+	properties := api.ParsePropertyString(value)
+	storage, _, _ := strings.Cut(properties[""], ":")
+	size, err := diskSizeGiB(properties["size"])
+	if err != nil {
+		return nil, fmt.Errorf("rootfs: %w", err)
+	}
+	return &ContainerRootFSModel{
+		Storage: types.StringValue(storage),
+		Size:    types.Int64Value(size),
+		Volume:  types.StringValue(properties[""]),
+	}, nil
+}
+
+// readMountPoints converts the mount points in the configuration into the model, ordered by their slot.
+// The type is derived from the source, volumes are named storage:volume while bind and device mounts are paths.
+func readMountPoints(devices map[string]string) ([]ContainerMountPointModel, error) {
+	var slots []int64
+	for key := range devices {
+		if match := mountPointKey.FindStringSubmatch(key); match != nil {
+			slot, _ := strconv.ParseInt(match[1], 10, 64)
+			slots = append(slots, slot)
+		}
+	}
+	sort.Slice(slots, func(i, j int) bool {
+		return slots[i] < slots[j]
+	})
+
+	mountPoints := []ContainerMountPointModel{}
+	for _, slot := range slots {
+		key := mountPointName(slot)
+		properties := api.ParsePropertyString(devices[key])
+		volume := properties[""]
+
+		mountPoint := ContainerMountPointModel{
+			Slot:     types.Int64Value(slot),
+			Type:     types.StringValue("volume"),
+			Storage:  types.StringNull(),
+			Size:     types.Int64Null(),
+			Source:   types.StringNull(),
+			Path:     types.StringValue(properties["mp"]),
+			Backup:   types.BoolValue(properties.Flag("backup", false)),
+			ReadOnly: types.BoolValue(properties.Flag("ro", false)),
+			Shared:   types.BoolValue(properties.Flag("shared", false)),
+			Volume:   types.StringValue(volume),
+		}
+
+		switch {
+		case strings.HasPrefix(volume, "/dev/"):
+			mountPoint.Type = types.StringValue("device")
+			mountPoint.Source = types.StringValue(volume)
+		case strings.HasPrefix(volume, "/"):
+			mountPoint.Type = types.StringValue("bind")
+			mountPoint.Source = types.StringValue(volume)
+		default:
+			storage, _, _ := strings.Cut(volume, ":")
+			size, err := diskSizeGiB(properties["size"])
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			mountPoint.Storage = types.StringValue(storage)
+			mountPoint.Size = types.Int64Value(size)
+		}
+		mountPoints = append(mountPoints, mountPoint)
+	}
+	return mountPoints, nil
+}
+
+// containerDiskChanges describes how the root filesystem and mount points of an existing container need to change
+type containerDiskChanges struct {
+	// devices are the configuration entries for new mount points and mount points with changed options
+	devices map[string]string
+	// removed are the mount points that should be detached
+	removed []string
+	// removedVolumes are the volumes that are detached or replaced, Proxmox keeps them as unused disks until they are destroyed
+	removedVolumes []string
+	// resizes maps rootfs or the mount point to the new size in GiB
+	resizes map[string]int64
+}
+
+func planContainerDiskChanges(plan ContainerResourceModel, state ContainerResourceModel) containerDiskChanges {
+	changes := containerDiskChanges{devices: map[string]string{}, resizes: map[string]int64{}}
+
+	if plan.RootFS != nil && state.RootFS != nil && plan.RootFS.Size.ValueInt64() > state.RootFS.Size.ValueInt64() {
+		changes.resizes["rootfs"] = plan.RootFS.Size.ValueInt64()
+	}
+
+	current := map[int64]ContainerMountPointModel{}
+	for _, mountPoint := range state.MountPoints {
+		current[mountPoint.Slot.ValueInt64()] = mountPoint
+	}
+
+	planned := map[int64]bool{}
+	for _, mountPoint := range plan.MountPoints {
+		slot := mountPoint.Slot.ValueInt64()
+		key := mountPointName(slot)
+		planned[slot] = true
+
+		existing, ok := current[slot]
+		if !ok {
+			changes.devices[key] = newMountPointValue(mountPoint)
+			continue
+		}
+
+		if !sameMountPointSource(mountPoint, existing) {
+			changes.devices[key] = newMountPointValue(mountPoint)
+			if existing.Type.ValueString() == "volume" {
+				changes.removedVolumes = append(changes.removedVolumes, existing.Volume.ValueString())
+			}
+			continue
+		}
+		if mountPointOptions(mountPoint).String() != mountPointOptions(existing).String() {
+			if mountPoint.Type.ValueString() == "volume" {
+				changes.devices[key] = existingMountPointValue(mountPoint, existing)
+			} else {
+				changes.devices[key] = newMountPointValue(mountPoint)
+			}
+		}
+		if mountPoint.Type.ValueString() == "volume" && mountPoint.Size.ValueInt64() > existing.Size.ValueInt64() {
+			changes.resizes[key] = mountPoint.Size.ValueInt64()
+		}
+	}
+
+	for _, mountPoint := range state.MountPoints {
+		if planned[mountPoint.Slot.ValueInt64()] {
+			continue
+		}
+		changes.removed = append(changes.removed, mountPointName(mountPoint.Slot.ValueInt64()))
+		if mountPoint.Type.ValueString() == "volume" {
+			changes.removedVolumes = append(changes.removedVolumes, mountPoint.Volume.ValueString())
+		}
+	}
+	sort.Strings(changes.removed)
+
+	return changes
+}
+
+// validateMountPoints checks that every mount point has the attributes of its type
+func validateMountPoints(mountPoints []ContainerMountPointModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	for i, mountPoint := range mountPoints {
+		mountPointPath := path.Root("mount_point").AtListIndex(i)
+		if mountPoint.Type.IsUnknown() || mountPoint.Source.IsUnknown() {
+			continue
+		}
+
+		mountType := mountPoint.Type.ValueString()
+		if mountPoint.Type.IsNull() {
+			mountType = "volume"
+		}
+		switch mountType {
+		case "volume":
+			if mountPoint.Storage.IsNull() || mountPoint.Size.IsNull() {
+				diags.AddAttributeError(mountPointPath, "Missing volume storage or size",
+					"A volume mount point needs a storage and a size")
+			}
+			if !mountPoint.Source.IsNull() || mountPoint.Shared.ValueBool() {
+				diags.AddAttributeError(mountPointPath, "Invalid volume mount point",
+					"The source and shared attributes are only used by bind and device mounts")
+			}
+		case "bind", "device":
+			if !mountPoint.Storage.IsNull() || !mountPoint.Size.IsNull() || mountPoint.Backup.ValueBool() {
+				diags.AddAttributeError(mountPointPath, "Invalid "+mountType+" mount point",
+					"The storage, size and backup attributes are only used by volumes")
+			}
+			source := mountPoint.Source.ValueString()
+			if mountPoint.Source.IsNull() || !strings.HasPrefix(source, "/") {
+				diags.AddAttributeError(mountPointPath.AtName("source"), "Invalid mount point source",
+					fmt.Sprintf("A %s mount needs the absolute path of its source on the node. Got: %q", mountType, source))
+			} else if (mountType == "device") != strings.HasPrefix(source, "/dev/") {
+				diags.AddAttributeError(mountPointPath.AtName("source"), "Invalid mount point source",
+					fmt.Sprintf("Device mounts are block devices under /dev and bind mounts are directories outside of it. Got: %q", source))
+			}
+		default:
+			diags.AddAttributeError(mountPointPath.AtName("type"), "Invalid mount point type",
+				fmt.Sprintf("The type must be volume, bind or device. Got: %q", mountType))
+		}
+	}
+	return diags
+}
+
+// validateContainerDiskPlan rejects changes that Proxmox can not make in place. Volumes can not shrink. Removing a
+// volume or changing its type or storage destroys the volume, so it is only allowed when allow_data_loss is set.
+func validateContainerDiskPlan(plan ContainerResourceModel, state ContainerResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if plan.RootFS != nil && state.RootFS != nil {
+		rootFSPath := path.Root("rootfs")
+		if !plan.RootFS.Size.IsUnknown() && plan.RootFS.Size.ValueInt64() < state.RootFS.Size.ValueInt64() {
+			diags.AddAttributeError(rootFSPath.AtName("size"), "The root filesystem can not shrink",
+				fmt.Sprintf("The root filesystem can not shrink from %dG to %dG. Proxmox only supports growing volumes", state.RootFS.Size.ValueInt64(), plan.RootFS.Size.ValueInt64()))
+		}
+		if !plan.RootFS.Storage.IsUnknown() && plan.RootFS.Storage.ValueString() != state.RootFS.Storage.ValueString() {
+			diags.AddAttributeError(rootFSPath.AtName("storage"), "The storage of the root filesystem can not change",
+				fmt.Sprintf("The root filesystem can not move from %s to %s", state.RootFS.Storage.ValueString(), plan.RootFS.Storage.ValueString()))
+		}
+	}
+
+	current := map[int64]ContainerMountPointModel{}
+	for _, mountPoint := range state.MountPoints {
+		current[mountPoint.Slot.ValueInt64()] = mountPoint
+	}
+
+	seen := map[int64]bool{}
+	allowDataLoss := plan.AllowDataLoss.ValueBool() || plan.AllowDataLoss.IsUnknown()
+	for i, mountPoint := range plan.MountPoints {
+		if mountPoint.Slot.IsUnknown() || mountPoint.Type.IsUnknown() || mountPoint.Size.IsUnknown() || mountPoint.Storage.IsUnknown() || mountPoint.Source.IsUnknown() {
+			continue
+		}
+		slot := mountPoint.Slot.ValueInt64()
+		mountPointPath := path.Root("mount_point").AtListIndex(i)
+
+		if slot < 0 || slot > 255 {
+			diags.AddAttributeError(mountPointPath.AtName("slot"), "Invalid mount point slot",
+				fmt.Sprintf("The slot must be between 0 and 255. Got: %d", slot))
+			continue
+		}
+		if seen[slot] {
+			diags.AddAttributeError(mountPointPath.AtName("slot"), "Duplicate mount point slot",
+				fmt.Sprintf("The slot %d is used by more than one mount point", slot))
+		}
+		seen[slot] = true
+
+		existing, ok := current[slot]
+		if !ok || existing.Type.ValueString() != "volume" {
+			continue
+		}
+		if !sameMountPointSource(mountPoint, existing) {
+			if !allowDataLoss {
+				diags.AddAttributeError(mountPointPath, "Mount point would lose data",
+					fmt.Sprintf("The volume of the mount point %s would be replaced and destroyed. Set allow_data_loss to true to allow this", mountPointName(slot)))
+			}
+			continue
+		}
+		if mountPoint.Size.ValueInt64() < existing.Size.ValueInt64() {
+			diags.AddAttributeError(mountPointPath.AtName("size"), "Mount points can not shrink",
+				fmt.Sprintf("The mount point %s can not shrink from %dG to %dG. Proxmox only supports growing volumes", mountPointName(slot), existing.Size.ValueInt64(), mountPoint.Size.ValueInt64()))
+		}
+	}
+
+	if allowDataLoss {
+		return diags
+	}
+	for _, mountPoint := range state.MountPoints {
+		if mountPoint.Type.ValueString() == "volume" && !seen[mountPoint.Slot.ValueInt64()] {
+			diags.AddAttributeError(path.Root("mount_point"), "Mount point would lose data",
+				fmt.Sprintf("Removing the mount point %s destroys the volume %s. Set allow_data_loss to true to allow this", mountPointName(mountPoint.Slot.ValueInt64()), mountPoint.Volume.ValueString()))
+		}
+	}
+	return diags
+}
+
+// planMountPointVolumes keeps the known volume of mount points that keep their volume, so that the plan only
+// shows the volume as unknown for new volumes. The volume of a bind or device mount is its source.
+func planMountPointVolumes(plan []ContainerMountPointModel, state []ContainerMountPointModel) {
+	current := map[int64]ContainerMountPointModel{}
+	for _, mountPoint := range state {
+		current[mountPoint.Slot.ValueInt64()] = mountPoint
+	}
+
+	for i, mountPoint := range plan {
+		existing, ok := current[mountPoint.Slot.ValueInt64()]
+		switch {
+		case mountPoint.Type.ValueString() != "volume" && !mountPoint.Type.IsUnknown():
+			plan[i].Volume = mountPoint.Source
+		case ok && !mountPoint.Slot.IsUnknown() && !mountPoint.Storage.IsUnknown() && sameMountPointSource(mountPoint, existing):
+			plan[i].Volume = existing.Volume
+		default:
+			plan[i].Volume = types.StringUnknown()
+		}
+	}
+}
+
+// attachedVolumes returns the volumes that are not yet an unusedN entry, the detach of their mount points is
+// still pending
+func attachedVolumes(devices map[string]string, volumes []string) []string {
+	var attached []string
+	for _, volume := range volumes {
+		if len(unusedDisks(devices, []string{volume})) == 0 {
+			attached = append(attached, volume)
+		}
+	}
+	return attached
+}
@@ -0,0 +1,291 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"reflect"
+	"testing"
+)
+
+func TestReadContainerDisks(t *testing.T) {
+	devices := map[string]string{
+		"rootfs": "local-lvm:vm-200-disk-0,size=8G",
+		"mp10":   "local-zfs:subvol-200-disk-2,mp=/srv,size=512M",
+		"mp1":    "local-lvm:vm-200-disk-1,mp=/var/lib/data,size=16G,backup=1",
+		"mp2":    "/mnt/media,mp=/media,ro=1,shared=1",
+		"mp3":    "/dev/sdb1,mp=/mnt/disk",
+		"net0":   "name=eth0,bridge=vmbr0,hwaddr=BC:24:11:00:00:01,type=veth",
+	}
+
+	rootFS, err := readRootFS(devices)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedRootFS := &ContainerRootFSModel{
+		Storage: types.StringValue("local-lvm"),
+		Size:    types.Int64Value(8),
+		Volume:  types.StringValue("local-lvm:vm-200-disk-0"),
+	}
+	if !reflect.DeepEqual(rootFS, expectedRootFS) {
+		t.Errorf("Incorrect root filesystem returned: %+v", rootFS)
+	}
+
+	mountPoints, err := readMountPoints(devices)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []ContainerMountPointModel{
+		{
+			Slot:     types.Int64Value(1),
+			Type:     types.StringValue("volume"),
+			Storage:  types.StringValue("local-lvm"),
+			Size:     types.Int64Value(16),
+			Path:     types.StringValue("/var/lib/data"),
+			Backup:   types.BoolValue(true),
+			ReadOnly: types.BoolValue(false),
+			Shared:   types.BoolValue(false),
+			Volume:   types.StringValue("local-lvm:vm-200-disk-1"),
+		},
+		{
+			Slot:     types.Int64Value(2),
+			Type:     types.StringValue("bind"),
+			Source:   types.StringValue("/mnt/media"),
+			Path:     types.StringValue("/media"),
+			Backup:   types.BoolValue(false),
+			ReadOnly: types.BoolValue(true),
+			Shared:   types.BoolValue(true),
+			Volume:   types.StringValue("/mnt/media"),
+		},
+		{
+			Slot:     types.Int64Value(3),
+			Type:     types.StringValue("device"),
+			Source:   types.StringValue("/dev/sdb1"),
+			Path:     types.StringValue("/mnt/disk"),
+			Backup:   types.BoolValue(false),
+			ReadOnly: types.BoolValue(false),
+			Shared:   types.BoolValue(false),
+			Volume:   types.StringValue("/dev/sdb1"),
+		},
+		{
+			Slot:     types.Int64Value(10),
+			Type:     types.StringValue("volume"),
+			Storage:  types.StringValue("local-zfs"),
+			Size:     types.Int64Value(1),
+			Path:     types.StringValue("/srv"),
+			Backup:   types.BoolValue(false),
+			ReadOnly: types.BoolValue(false),
+			Shared:   types.BoolValue(false),
+			Volume:   types.StringValue("local-zfs:subvol-200-disk-2"),
+		},
+	}
+	if !reflect.DeepEqual(mountPoints, expected) {
+		t.Errorf("Incorrect mount points. Expected %+v, got %+v", expected, mountPoints)
+	}
+
+	rootFS, err = readRootFS(map[string]string{})
+	if err != nil || rootFS != nil {
+		t.Errorf("Expected no root filesystem, got %+v %v", rootFS, err)
+	}
+}
+
+func TestPlanContainerDiskChanges(t *testing.T) {
+	state := ContainerResourceModel{
+		RootFS: &ContainerRootFSModel{Storage: types.StringValue("local-lvm"), Size: types.Int64Value(8), Volume: types.StringValue("local-lvm:vm-200-disk-0")},
+		MountPoints: []ContainerMountPointModel{
+			{Slot: types.Int64Value(0), Type: types.StringValue("volume"), Storage: types.StringValue("local-lvm"), Size: types.Int64Value(8), Path: types.StringValue("/data"), Volume: types.StringValue("local-lvm:vm-200-mp0")},
+			{Slot: types.Int64Value(1), Type: types.StringValue("volume"), Storage: types.StringValue("local-lvm"), Size: types.Int64Value(4), Path: types.StringValue("/logs"), Volume: types.StringValue("local-lvm:vm-200-mp1")},
+			{Slot: types.Int64Value(2), Type: types.StringValue("volume"), Storage: types.StringValue("local-lvm"), Size: types.Int64Value(4), Path: types.StringValue("/tmp"), Volume: types.StringValue("local-lvm:vm-200-mp2")},
+		},
+	}
+	plan := ContainerResourceModel{
+		RootFS: &ContainerRootFSModel{Storage: types.StringValue("local-lvm"), Size: types.Int64Value(16), Volume: types.StringValue("local-lvm:vm-200-disk-0")},
+		MountPoints: []ContainerMountPointModel{
+			{Slot: types.Int64Value(0), Type: types.StringValue("volume"), Storage: types.StringValue("local-lvm"), Size: types.Int64Value(8), Path: types.StringValue("/srv/data"), Volume: types.StringValue("local-lvm:vm-200-mp0")},
+			{Slot: types.Int64Value(1), Type: types.StringValue("volume"), Storage: types.StringValue("local-lvm"), Size: types.Int64Value(10), Path: types.StringValue("/logs"), Volume: types.StringValue("local-lvm:vm-200-mp1")},
+			{Slot: types.Int64Value(3), Type: types.StringValue("volume"), Storage: types.StringValue("local-lvm"), Size: types.Int64Value(2), Path: types.StringValue("/cache"), Volume: types.StringUnknown()},
+		},
+	}
+
+	changes := planContainerDiskChanges(plan, state)
+
+	expectedDevices := map[string]string{
+		"mp0": "local-lvm:vm-200-mp0,mp=/srv/data,size=8G",
+		"mp3": "local-lvm:2,mp=/cache",
+	}
+	if !reflect.DeepEqual(changes.devices, expectedDevices) {
+		t.Errorf("Incorrect devices. Expected %v, got %v", expectedDevices, changes.devices)
+	}
+	if !reflect.DeepEqual(changes.resizes, map[string]int64{"rootfs": 16, "mp1": 10}) {
+		t.Errorf("Incorrect resizes: %v", changes.resizes)
+	}
+	if !reflect.DeepEqual(changes.removed, []string{"mp2"}) {
+		t.Errorf("Incorrect removals: %v", changes.removed)
+	}
+	if !reflect.DeepEqual(changes.removedVolumes, []string{"local-lvm:vm-200-mp2"}) {
+		t.Errorf("Incorrect removed volumes: %v", changes.removedVolumes)
+	}
+}
+
+func TestPlanContainerBindMountChanges(t *testing.T) {
+	state := ContainerResourceModel{
+		MountPoints: []ContainerMountPointModel{
+			{Slot: types.Int64Value(0), Type: types.StringValue("volume"), Storage: types.StringValue("local-lvm"), Size: types.Int64Value(8), Path: types.StringValue("/data"), Volume: types.StringValue("local-lvm:vm-200-mp0")},
+			{Slot: types.Int64Value(1), Type: types.StringValue("bind"), Source: types.StringValue("/mnt/media"), Path: types.StringValue("/media"), Volume: types.StringValue("/mnt/media")},
+			{Slot: types.Int64Value(2), Type: types.StringValue("bind"), Source: types.StringValue("/mnt/logs"), Path: types.StringValue("/logs"), Volume: types.StringValue("/mnt/logs")},
+		},
+	}
+	plan := ContainerResourceModel{
+		MountPoints: []ContainerMountPointModel{
+			{Slot: types.Int64Value(0), Type: types.StringValue("bind"), Source: types.StringValue("/mnt/data"), Path: types.StringValue("/data"), Volume: types.StringUnknown()},
+			{Slot: types.Int64Value(1), Type: types.StringValue("bind"), Source: types.StringValue("/mnt/media"), Path: types.StringValue("/media"), ReadOnly: types.BoolValue(true), Volume: types.StringValue("/mnt/media")},
+			{Slot: types.Int64Value(3), Type: types.StringValue("device"), Source: types.StringValue("/dev/sdb1"), Path: types.StringValue("/mnt/disk"), Volume: types.StringUnknown()},
+		},
+	}
+
+	changes := planContainerDiskChanges(plan, state)
+
+	expectedDevices := map[string]string{
+		"mp0": "/mnt/data,mp=/data",
+		"mp1": "/mnt/media,mp=/media,ro=1",
+		"mp3": "/dev/sdb1,mp=/mnt/disk",
+	}
+	if !reflect.DeepEqual(changes.devices, expectedDevices) {
+		t.Errorf("Incorrect devices. Expected %v, got %v", expectedDevices, changes.devices)
+	}
+	if !reflect.DeepEqual(changes.removed, []string{"mp2"}) {
+		t.Errorf("Incorrect removals: %v", changes.removed)
+	}
+	if !reflect.DeepEqual(changes.removedVolumes, []string{"local-lvm:vm-200-mp0"}) {
+		t.Errorf("Only the replaced volume should be destroyed, got %v", changes.removedVolumes)
+	}
+	if len(changes.resizes) != 0 {
+		t.Errorf("Expected no resizes, got %v", changes.resizes)
+	}
+}
+
+func TestValidateMountPoints(t *testing.T) {
+	tests := []struct {
+		name       string
+		mountPoint ContainerMountPointModel
+		valid      bool
+	}{
+		{"volume", ContainerMountPointModel{Type: types.StringValue("volume"), Storage: types.StringValue("local-lvm"), Size: types.Int64Value(8), Path: types.StringValue("/data")}, true},
+		{"bind", ContainerMountPointModel{Type: types.StringValue("bind"), Source: types.StringValue("/mnt/media"), Path: types.StringValue("/media")}, true},
+		{"device", ContainerMountPointModel{Type: types.StringValue("device"), Source: types.StringValue("/dev/sdb1"), Path: types.StringValue("/mnt/disk")}, true},
+		{"shared volume", ContainerMountPointModel{Type: types.StringValue("volume"), Storage: types.StringValue("local-lvm"), Size: types.Int64Value(8), Path: types.StringValue("/data"), Shared: types.BoolValue(true)}, false},
+		{"volume without size", ContainerMountPointModel{Type: types.StringValue("volume"), Storage: types.StringValue("local-lvm"), Path: types.StringValue("/data")}, false},
+		{"bind with storage", ContainerMountPointModel{Type: types.StringValue("bind"), Source: types.StringValue("/mnt/media"), Storage: types.StringValue("local-lvm"), Path: types.StringValue("/media")}, false},
+		{"relative source", ContainerMountPointModel{Type: types.StringValue("bind"), Source: types.StringValue("mnt/media"), Path: types.StringValue("/media")}, false},
+		{"device as bind", ContainerMountPointModel{Type: types.StringValue("bind"), Source: types.StringValue("/dev/sdb1"), Path: types.StringValue("/mnt/disk")}, false},
+		{"unknown type", ContainerMountPointModel{Type: types.StringValue("nfs"), Source: types.StringValue("/mnt/media"), Path: types.StringValue("/media")}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			diags := validateMountPoints([]ContainerMountPointModel{test.mountPoint})
+			if diags.HasError() == test.valid {
+				t.Errorf("Incorrect validation of %+v: %v", test.mountPoint, diags)
+			}
+		})
+	}
+}
+
+func TestValidateContainerDiskPlan(t *testing.T) {
+	rootFS := &ContainerRootFSModel{Storage: types.StringValue("local-lvm"), Size: types.Int64Value(8)}
+	data := ContainerMountPointModel{Slot: types.Int64Value(0), Type: types.StringValue("volume"), Storage: types.StringValue("local-lvm"), Size: types.Int64Value(8), Path: types.StringValue("/data")}
+	media := ContainerMountPointModel{Slot: types.Int64Value(1), Type: types.StringValue("bind"), Source: types.StringValue("/mnt/media"), Path: types.StringValue("/media")}
+	state := ContainerResourceModel{RootFS: rootFS, MountPoints: []ContainerMountPointModel{data, media}}
+
+	tests := []struct {
+		name   string
+		plan   ContainerResourceModel
+		state  ContainerResourceModel
+		errors int
+	}{
+		{
+			name:   "shrink root filesystem",
+			plan:   ContainerResourceModel{RootFS: &ContainerRootFSModel{Storage: types.StringValue("local-lvm"), Size: types.Int64Value(4)}, MountPoints: state.MountPoints},
+			state:  state,
+			errors: 1,
+		},
+		{
+			name:   "move root filesystem",
+			plan:   ContainerResourceModel{RootFS: &ContainerRootFSModel{Storage: types.StringValue("local-zfs"), Size: types.Int64Value(8)}, MountPoints: state.MountPoints},
+			state:  state,
+			errors: 1,
+		},
+		{
+			name: "shrink mount point",
+			plan: ContainerResourceModel{RootFS: rootFS, AllowDataLoss: types.BoolValue(false), MountPoints: []ContainerMountPointModel{
+				{Slot: types.Int64Value(0), Type: types.StringValue("volume"), Storage: types.StringValue("local-lvm"), Size: types.Int64Value(4), Path: types.StringValue("/data")},
+			}},
+			state:  state,
+			errors: 1,
+		},
+		{
+			name: "move mount point",
+			plan: ContainerResourceModel{RootFS: rootFS, AllowDataLoss: types.BoolValue(false), MountPoints: []ContainerMountPointModel{
+				{Slot: types.Int64Value(0), Type: types.StringValue("volume"), Storage: types.StringValue("local-zfs"), Size: types.Int64Value(8), Path: types.StringValue("/data")},
+			}},
+			state:  state,
+			errors: 1,
+		},
+		{
+			name: "replace volume with bind mount",
+			plan: ContainerResourceModel{RootFS: rootFS, AllowDataLoss: types.BoolValue(false), MountPoints: []ContainerMountPointModel{
+				{Slot: types.Int64Value(0), Type: types.StringValue("bind"), Source: types.StringValue("/mnt/data"), Path: types.StringValue("/data")},
+			}},
+			state:  state,
+			errors: 1,
+		},
+		{
+			name:   "remove volume",
+			plan:   ContainerResourceModel{RootFS: rootFS, AllowDataLoss: types.BoolValue(false)},
+			state:  state,
+			errors: 1,
+		},
+		{
+			name:   "remove volume with data loss allowed",
+			plan:   ContainerResourceModel{RootFS: rootFS, AllowDataLoss: types.BoolValue(true)},
+			state:  state,
+			errors: 0,
+		},
+		{
+			name: "move mount point with data loss allowed",
+			plan: ContainerResourceModel{RootFS: rootFS, AllowDataLoss: types.BoolValue(true), MountPoints: []ContainerMountPointModel{
+				{Slot: types.Int64Value(0), Type: types.StringValue("volume"), Storage: types.StringValue("local-zfs"), Size: types.Int64Value(8), Path: types.StringValue("/data")},
+			}},
+			state:  state,
+			errors: 0,
+		},
+		{
+			name: "duplicate and invalid slot",
+			plan: ContainerResourceModel{MountPoints: []ContainerMountPointModel{
+				{Slot: types.Int64Value(1), Type: types.StringValue("volume"), Storage: types.StringValue("local-lvm"), Size: types.Int64Value(4), Path: types.StringValue("/a")},
+				{Slot: types.Int64Value(1), Type: types.StringValue("volume"), Storage: types.StringValue("local-lvm"), Size: types.Int64Value(4), Path: types.StringValue("/b")},
+				{Slot: types.Int64Value(256), Type: types.StringValue("volume"), Storage: types.StringValue("local-lvm"), Size: types.Int64Value(4), Path: types.StringValue("/c")},
+			}},
+			state:  ContainerResourceModel{},
+			errors: 2,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			diags := validateContainerDiskPlan(test.plan, test.state)
+			if diags.ErrorsCount() != test.errors {
+				t.Errorf("Expected %d errors, got %v", test.errors, diags)
+			}
+		})
+	}
+}
+
+func TestAttachedVolumes(t *testing.T) {
+	devices := map[string]string{
+		"mp0":     "local-lvm:vm-100-disk-1,mp=/srv,size=8G",
+		"unused0": "local-lvm:vm-100-disk-2",
+	}
+
+	// The detach of mp0 is pending while the container is running, mp1 has already become an unused disk
+	attached := attachedVolumes(devices, []string{"local-lvm:vm-100-disk-1", "local-lvm:vm-100-disk-2"})
+	if len(attached) != 1 || attached[0] != "local-lvm:vm-100-disk-1" {
+		t.Errorf("Incorrect attached volumes: %v", attached)
+	}
+}
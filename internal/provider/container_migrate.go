@@ -0,0 +1,37 @@
+package provider
+
+import (
+	"terraform-provider-proxmox/internal/api"
+	"time"
+)
+
+// migrateContainer moves the container from the node in the state to the node in the plan. Containers can not
+// be migrated live, so a running container is shut down, moved and started again on the new node.
+func (r *containerResource) migrateContainer(plan ContainerResourceModel, state ContainerResourceModel) error {
+	node := state.Node.ValueString()
+	vmid := state.VMID.ValueInt64()
+
+	status, err := api.GetContainerStatus(r.client, node, vmid)
+	if err != nil {
+		return err
+	}
+
+	migrateRequest := api.ContainerMigrateRequest{
+		Target:        plan.Node.ValueString(),
+		TargetStorage: plan.MigrateTargetStorage.ValueStringPointer(),
+	}
+	timeout := time.Duration(plan.MigrateTimeout.ValueInt64()) * time.Second
+	if status.Status == "running" {
+		restart := api.Bool(true)
+		shutdownTimeout := plan.ShutdownTimeout.ValueInt64()
+		migrateRequest.Restart = &restart
+		migrateRequest.Timeout = &shutdownTimeout
+		timeout += time.Duration(shutdownTimeout) * time.Second
+	}
+
+	upid, err := api.MigrateContainer(r.client, node, vmid, &migrateRequest)
+	if err != nil {
+		return err
+	}
+	return api.WaitForTask(r.client, upid, timeout)
+}
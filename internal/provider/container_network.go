@@ -0,0 +1,160 @@
+package provider
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"strconv"
+	"terraform-provider-proxmox/internal/api"
+)
+
+// ContainerNetworkInterfaceModel The network interface at position N of the list is configured as netN, so the
+// interfaces of a container must be numbered without gaps.
+// Unlike a virtual machine the addresses are part of the interface, Proxmox writes them into the container.
+type ContainerNetworkInterfaceModel struct {
+	Name     types.String `tfsdk:"name"`
+	Bridge   types.String `tfsdk:"bridge"`
+	IP       types.String `tfsdk:"ip"`
+	Gateway  types.String `tfsdk:"gw"`
+	IP6      types.String `tfsdk:"ip6"`
+	Gateway6 types.String `tfsdk:"gw6"`
+	Tag      types.Int64  `tfsdk:"tag"`
+	Firewall types.Bool   `tfsdk:"firewall"`
+	MAC      types.String `tfsdk:"mac"`
+}
+
+func containerNetworkInterfaceBlock() schema.ListNestedBlock {
+	return schema.ListNestedBlock{
+		Description: "The first block is net0, the second net1 and so on",
+		NestedObject: schema.NestedBlockObject{
+			Attributes: map[string]schema.Attribute{
+				"name": schema.StringAttribute{
+					Required:    true,
+					Description: "The name of the interface in the container, for example eth0",
+				},
+				"bridge": schema.StringAttribute{
+					Required:    true,
+					Description: "The bridge to connect the interface to, for example vmbr0",
+				},
+				"ip": schema.StringAttribute{
+					Optional:    true,
+					Description: "The IPv4 address in CIDR notation, dhcp or manual",
+				},
+				"gw": schema.StringAttribute{
+					Optional:    true,
+					Description: "The IPv4 gateway",
+				},
+				"ip6": schema.StringAttribute{
+					Optional:    true,
+					Description: "The IPv6 address in CIDR notation, auto, dhcp or manual",
+				},
+				"gw6": schema.StringAttribute{
+					Optional:    true,
+					Description: "The IPv6 gateway",
+				},
+				"tag": schema.Int64Attribute{
+					Optional:    true,
+					Description: "The VLAN tag of the traffic on the interface",
+				},
+				"firewall": schema.BoolAttribute{
+					Optional: true,
+					Computed: true,
+					Default:  booldefault.StaticBool(false),
+				},
+				"mac": schema.StringAttribute{
+					Optional:    true,
+					Computed:    true,
+					Description: "Proxmox generates a MAC address when none is set, it is kept when the interface changes",
+					PlanModifiers: []planmodifier.String{
+						stringplanmodifier.UseStateForUnknown(),
+					},
+				},
+			},
+		},
+	}
+}
+
+// containerNetworkInterfaceValue is the property string of the interface, for example name=eth0,bridge=vmbr0,ip=dhcp
+func containerNetworkInterfaceValue(nic ContainerNetworkInterfaceModel) string {
+	properties := api.PropertyString{
+		"name":   nic.Name.ValueString(),
+		"bridge": nic.Bridge.ValueString(),
+	}
+	if !nic.MAC.IsNull() && !nic.MAC.IsUnknown() {
+		properties["hwaddr"] = nic.MAC.ValueString()
+	}
+	for key, value := range map[string]types.String{"ip": nic.IP, "gw": nic.Gateway, "ip6": nic.IP6, "gw6": nic.Gateway6} {
+		if !value.IsNull() {
+			properties[key] = value.ValueString()
+		}
+	}
+	if !nic.Tag.IsNull() {
+		properties["tag"] = strconv.FormatInt(nic.Tag.ValueInt64(), 10)
+	}
+	if nic.Firewall.ValueBool() {
+		properties.SetFlag("firewall", true)
+	}
+	return properties.String()
+}
+
+// readContainerNetworkInterfaces converts the network interfaces in the configuration into the model, ordered by their number
+func readContainerNetworkInterfaces(devices map[string]string) ([]ContainerNetworkInterfaceModel, error) {
+	keys, err := networkDeviceKeys(devices)
+	if err != nil {
+		return nil, err
+	}
+
+	networkInterfaces := []ContainerNetworkInterfaceModel{}
+	for _, key := range keys {
+		properties := api.ParsePropertyString(devices[key])
+		nic := ContainerNetworkInterfaceModel{
+			Name:     types.StringValue(properties["name"]),
+			Bridge:   types.StringValue(properties["bridge"]),
+			IP:       optionalString(properties["ip"]),
+			Gateway:  optionalString(properties["gw"]),
+			IP6:      optionalString(properties["ip6"]),
+			Gateway6: optionalString(properties["gw6"]),
+			Tag:      types.Int64Null(),
+			Firewall: types.BoolValue(properties.Flag("firewall", false)),
+			MAC:      types.StringValue(properties["hwaddr"]),
+		}
+		if properties["tag"] != "" {
+			tag, err := strconv.ParseInt(properties["tag"], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%s: the tag %q is not a number", key, properties["tag"])
+			}
+			nic.Tag = types.Int64Value(tag)
+		}
+		networkInterfaces = append(networkInterfaces, nic)
+	}
+	return networkInterfaces, nil
+}
+
+// planContainerNetworkInterfaceChanges returns the network interfaces that are new or have changed and the
+// interfaces that were removed
+func planContainerNetworkInterfaceChanges(plan []ContainerNetworkInterfaceModel, state []ContainerNetworkInterfaceModel) (map[string]string, []string) {
+	devices := map[string]string{}
+
+	for i, nic := range plan {
+		key := fmt.Sprintf("net%d", i)
+		// Keep the MAC address that Proxmox generated for an interface that already exists
+		if nic.MAC.IsUnknown() && i < len(state) {
+			nic.MAC = state[i].MAC
+		}
+		value := containerNetworkInterfaceValue(nic)
+		if i < len(state) && containerNetworkInterfaceValue(state[i]) == value {
+			continue
+		}
+		devices[key] = value
+	}
+
+	var removed []string
+	for i := len(plan); i < len(state); i++ {
+		removed = append(removed, fmt.Sprintf("net%d", i))
+	}
+
+	return devices, removed
+}
@@ -0,0 +1,89 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"reflect"
+	"testing"
+)
+
+func TestReadContainerNetworkInterfaces(t *testing.T) {
+	devices := map[string]string{
+		"net1":   "name=eth1,bridge=vmbr1,hwaddr=BC:24:11:00:00:02,ip6=auto,type=veth",
+		"net0":   "name=eth0,bridge=vmbr0,firewall=1,gw=192.168.1.1,hwaddr=BC:24:11:00:00:01,ip=192.168.1.10/24,tag=10,type=veth",
+		"rootfs": "local-lvm:vm-200-disk-0,size=8G",
+	}
+
+	networkInterfaces, err := readContainerNetworkInterfaces(devices)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []ContainerNetworkInterfaceModel{
+		{
+			Name:     types.StringValue("eth0"),
+			Bridge:   types.StringValue("vmbr0"),
+			IP:       types.StringValue("192.168.1.10/24"),
+			Gateway:  types.StringValue("192.168.1.1"),
+			Tag:      types.Int64Value(10),
+			Firewall: types.BoolValue(true),
+			MAC:      types.StringValue("BC:24:11:00:00:01"),
+		},
+		{
+			Name:     types.StringValue("eth1"),
+			Bridge:   types.StringValue("vmbr1"),
+			IP6:      types.StringValue("auto"),
+			Firewall: types.BoolValue(false),
+			MAC:      types.StringValue("BC:24:11:00:00:02"),
+		},
+	}
+	if !reflect.DeepEqual(networkInterfaces, expected) {
+		t.Errorf("Incorrect network interfaces. Expected %+v, got %+v", expected, networkInterfaces)
+	}
+
+	_, err = readContainerNetworkInterfaces(map[string]string{"net0": "name=eth0,bridge=vmbr0,tag=ten"})
+	if err == nil {
+		t.Error("Expected an error for an invalid tag")
+	}
+
+	_, err = readContainerNetworkInterfaces(map[string]string{"net0": "name=eth0,bridge=vmbr0", "net2": "name=eth2,bridge=vmbr0"})
+	if err == nil {
+		t.Error("Expected an error for a gap in the interface numbers")
+	}
+}
+
+func TestContainerNetworkInterfaceValue(t *testing.T) {
+	nic := ContainerNetworkInterfaceModel{
+		Name:     types.StringValue("eth0"),
+		Bridge:   types.StringValue("vmbr0"),
+		IP:       types.StringValue("dhcp"),
+		Firewall: types.BoolValue(true),
+		MAC:      types.StringUnknown(),
+	}
+
+	expected := "bridge=vmbr0,firewall=1,ip=dhcp,name=eth0"
+	if value := containerNetworkInterfaceValue(nic); value != expected {
+		t.Errorf("Incorrect value. Expected %s, got %s", expected, value)
+	}
+}
+
+func TestPlanContainerNetworkInterfaceChanges(t *testing.T) {
+	state := []ContainerNetworkInterfaceModel{
+		{Name: types.StringValue("eth0"), Bridge: types.StringValue("vmbr0"), MAC: types.StringValue("BC:24:11:00:00:01")},
+		{Name: types.StringValue("eth1"), Bridge: types.StringValue("vmbr1"), MAC: types.StringValue("BC:24:11:00:00:02")},
+		{Name: types.StringValue("eth2"), Bridge: types.StringValue("vmbr2"), MAC: types.StringValue("BC:24:11:00:00:03")},
+	}
+	plan := []ContainerNetworkInterfaceModel{
+		{Name: types.StringValue("eth0"), Bridge: types.StringValue("vmbr0"), MAC: types.StringUnknown()},
+		{Name: types.StringValue("eth1"), Bridge: types.StringValue("vmbr1"), IP: types.StringValue("dhcp"), MAC: types.StringValue("BC:24:11:00:00:02")},
+	}
+
+	devices, removed := planContainerNetworkInterfaceChanges(plan, state)
+
+	expected := map[string]string{"net1": "bridge=vmbr1,hwaddr=BC:24:11:00:00:02,ip=dhcp,name=eth1"}
+	if !reflect.DeepEqual(devices, expected) {
+		t.Errorf("Incorrect devices. Expected %v, got %v", expected, devices)
+	}
+	if !reflect.DeepEqual(removed, []string{"net2"}) {
+		t.Errorf("Incorrect removals: %v", removed)
+	}
+}
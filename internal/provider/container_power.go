@@ -0,0 +1,97 @@
+package provider
+
+import (
+	"terraform-provider-proxmox/internal/api"
+	"time"
+)
+
+// startContainer starts the container and waits for it to be running
+func (r *containerResource) startContainer(node string, vmid int64) error {
+	upid, err := api.StartContainer(r.client, node, vmid)
+	if err != nil {
+		return err
+	}
+	return api.WaitForTask(r.client, upid, containerTaskTimeout)
+}
+
+// shutdownContainer asks the container to shut down. When it has not stopped within the timeout
+// the container is stopped if forceStop is set, otherwise an error is returned.
+func (r *containerResource) shutdownContainer(node string, vmid int64, timeout int64, forceStop bool) error {
+	upid, err := api.ShutdownContainer(r.client, node, vmid, timeout, forceStop)
+	if err != nil {
+		return err
+	}
+	// Give Proxmox time to stop the container after the shutdown has timed out
+	return api.WaitForTask(r.client, upid, time.Duration(timeout)*time.Second+time.Minute)
+}
+
+// setPowerState starts or shuts down the container so that its power state matches started.
+// When reboot is set a running container is restarted to apply pending changes. Finally the
+// container is converted into a template if the plan asks for one.
+func (r *containerResource) setPowerState(plan ContainerResourceModel, reboot bool) error {
+	node := plan.Node.ValueString()
+	vmid := plan.VMID.ValueInt64()
+
+	status, err := api.GetContainerStatus(r.client, node, vmid)
+	if err != nil {
+		return err
+	}
+	running := status.Status == "running"
+
+	started := running
+	if !plan.Started.IsNull() && !plan.Started.IsUnknown() {
+		started = plan.Started.ValueBool()
+	}
+
+	if running && (!started || reboot) {
+		err = r.shutdownContainer(node, vmid, plan.ShutdownTimeout.ValueInt64(), plan.ForceStop.ValueBool())
+		if err != nil {
+			return err
+		}
+		running = false
+	}
+
+	if !running && started {
+		return r.startContainer(node, vmid)
+	}
+	return r.convertToTemplate(plan, running)
+}
+
+// convertToTemplate turns the container into a template when the plan asks for one.
+// Only a stopped container can be converted.
+func (r *containerResource) convertToTemplate(plan ContainerResourceModel, running bool) error {
+	if !plan.Template.ValueBool() {
+		return nil
+	}
+
+	node := plan.Node.ValueString()
+	vmid := plan.VMID.ValueInt64()
+	config, err := api.GetContainerConfig(r.client, node, vmid)
+	if err != nil || (config.Template != nil && bool(*config.Template)) {
+		return err
+	}
+
+	if running {
+		err = r.shutdownContainer(node, vmid, plan.ShutdownTimeout.ValueInt64(), plan.ForceStop.ValueBool())
+		if err != nil {
+			return err
+		}
+	}
+	return api.ConvertContainerToTemplate(r.client, node, vmid)
+}
+
+// updatePowerState sets the power state after an update. The container is restarted when reboot_after_update
+// is set and some of the changes could not be applied to the running container.
+func (r *containerResource) updatePowerState(plan ContainerResourceModel) error {
+	reboot := false
+	if plan.RebootAfterUpdate.ValueBool() {
+		changes, err := api.GetContainerPending(r.client, plan.Node.ValueString(), plan.VMID.ValueInt64())
+		if err != nil {
+			return err
+		}
+		for _, change := range changes {
+			reboot = reboot || change.IsPending()
+		}
+	}
+	return r.setPowerState(plan, reboot)
+}
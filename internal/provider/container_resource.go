@@ -0,0 +1,1056 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/clincha-org/proxmox-api/pkg/proxmox"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"terraform-provider-proxmox/internal/api"
+	"time"
+)
+
+var (
+	_ resource.Resource                   = &containerResource{}
+	_ resource.ResourceWithConfigure      = &containerResource{}
+	_ resource.ResourceWithImportState    = &containerResource{}
+	_ resource.ResourceWithModifyPlan     = &containerResource{}
+	_ resource.ResourceWithValidateConfig = &containerResource{}
+)
+
+// containerTaskTimeout is how long to wait for the create, update and delete tasks of a container.
+// Creating a container unpacks the OS template, which takes longer than creating a virtual machine.
+const containerTaskTimeout = 15 * time.Minute
+
+type ContainerResourceModel struct {
+	ID                   types.String                     `tfsdk:"id"`
+	Node                 types.String                     `tfsdk:"node"`
+	VMID                 types.Int64                      `tfsdk:"vmid"`
+	Hostname             types.String                     `tfsdk:"hostname"`
+	OSTemplate           types.String                     `tfsdk:"ostemplate"`
+	Clone                *ContainerCloneModel             `tfsdk:"clone"`
+	Unprivileged         types.Bool                       `tfsdk:"unprivileged"`
+	Features             *ContainerFeaturesModel          `tfsdk:"features"`
+	Cores                types.Int64                      `tfsdk:"cores"`
+	Memory               types.Int64                      `tfsdk:"memory"`
+	Swap                 types.Int64                      `tfsdk:"swap"`
+	RootFS               *ContainerRootFSModel            `tfsdk:"rootfs"`
+	MountPoints          []ContainerMountPointModel       `tfsdk:"mount_point"`
+	NetworkInterfaces    []ContainerNetworkInterfaceModel `tfsdk:"network_interface"`
+	SSHPublicKeys        types.List                       `tfsdk:"ssh_public_keys"`
+	Password             types.String                     `tfsdk:"password"`
+	Startup              *ContainerStartupModel           `tfsdk:"startup"`
+	OnBoot               types.Bool                       `tfsdk:"onboot"`
+	Template             types.Bool                       `tfsdk:"template"`
+	Tags                 types.Set                        `tfsdk:"tags"`
+	Description          types.String                     `tfsdk:"description"`
+	Started              types.Bool                       `tfsdk:"started"`
+	RebootAfterUpdate    types.Bool                       `tfsdk:"reboot_after_update"`
+	ShutdownTimeout      types.Int64                      `tfsdk:"shutdown_timeout"`
+	ForceStop            types.Bool                       `tfsdk:"force_stop"`
+	Migrate              types.Bool                       `tfsdk:"migrate"`
+	MigrateTargetStorage types.String                     `tfsdk:"migrate_target_storage"`
+	MigrateTimeout       types.Int64                      `tfsdk:"migrate_timeout"`
+	AllowDataLoss        types.Bool                       `tfsdk:"allow_data_loss"`
+	Pending              types.Set                        `tfsdk:"pending"`
+}
+
+// ContainerFeaturesModel The advanced features of the container. Most of them need a privileged container or root@pam.
+type ContainerFeaturesModel struct {
+	Nesting types.Bool `tfsdk:"nesting"`
+	Keyctl  types.Bool `tfsdk:"keyctl"`
+	Fuse    types.Bool `tfsdk:"fuse"`
+	Mount   types.List `tfsdk:"mount"`
+}
+
+// ContainerStartupModel The order in which the containers and virtual machines with onboot are started when the node boots
+type ContainerStartupModel struct {
+	Order     types.Int64 `tfsdk:"order"`
+	UpDelay   types.Int64 `tfsdk:"up_delay"`
+	DownDelay types.Int64 `tfsdk:"down_delay"`
+}
+
+type containerResource struct {
+	client *proxmox.Client
+}
+
+func NewContainerResource() resource.Resource {
+	return &containerResource{}
+}
+
+func (r *containerResource) Metadata(_ context.Context, request resource.MetadataRequest, response *resource.MetadataResponse) {
+	response.TypeName = request.ProviderTypeName + "_container"
+}
+
+func (r *containerResource) Configure(_ context.Context, request resource.ConfigureRequest, response *resource.ConfigureResponse) {
+	if request.ProviderData == nil {
+		return
+	}
+
+	client, ok := request.ProviderData.(*proxmox.Client)
+
+	if !ok {
+		response.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *proxmox.Client, got %T. Please report this issue to the developers", request.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+func (r *containerResource) Schema(_ context.Context, _ resource.SchemaRequest, response *resource.SchemaResponse) {
+	response.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"node": schema.StringAttribute{
+				Required:    true,
+				Description: "Changing the node migrates the container, unless migrate is false",
+				PlanModifiers: []planmodifier.String{
+					requiresReplaceUnlessMigrated(),
+				},
+			},
+			"vmid": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "The VMID of the container. Defaults to the lowest free VMID in the cluster",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"hostname": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "The hostname of the container. Defaults to CT followed by the VMID",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"ostemplate": schema.StringAttribute{
+				Optional:    true,
+				Description: "The OS template to create the container from, for example local:vztmpl/debian-12-standard_12.7-1_amd64.tar.zst. Required unless the container is cloned. Changes replace the container",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"unprivileged": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(true),
+				Description: "Map the root user of the container to an unprivileged user on the node. Changes replace the container",
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"cores": schema.Int64Attribute{
+				Optional:    true,
+				Description: "The number of cores the container can use. Defaults to all the cores of the node",
+			},
+			"memory": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(512),
+				Description: "The memory in MiB",
+			},
+			"swap": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(512),
+				Description: "The swap in MiB",
+			},
+			"ssh_public_keys": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "The public SSH keys of the root user. They are only used when the container is created, changes replace the container",
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+			},
+			"password": schema.StringAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				Description: "The password of the root user. It is only used when the container is created, changes replace the container",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"onboot": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Start the container when the node boots",
+			},
+			"template": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Convert the container into a template. A template can not be turned back into a container, so it is replaced instead",
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplaceIf(
+						func(_ context.Context, request planmodifier.BoolRequest, response *boolplanmodifier.RequiresReplaceIfFuncResponse) {
+							response.RequiresReplace = request.StateValue.ValueBool() && !request.PlanValue.ValueBool()
+						},
+						"A template can not be turned back into a container",
+						"A template can not be turned back into a container",
+					),
+				},
+			},
+			"tags": schema.SetAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"description": schema.StringAttribute{
+				Optional: true,
+			},
+			"started": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Whether the container should be running. When not set the power state is left alone",
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"reboot_after_update": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Restart a running container when an update leaves changes pending",
+			},
+			"shutdown_timeout": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(180),
+				Description: "The number of seconds to wait for the container to shut down",
+			},
+			"force_stop": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Stop the container when it has not shut down within the shutdown timeout",
+			},
+			"migrate": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(true),
+				Description: "Migrate the container when the node changes. A running container is restarted on the new node. When false it is replaced instead",
+			},
+			"migrate_target_storage": schema.StringAttribute{
+				Optional:    true,
+				Description: "The storage on the new node for volumes on local storage. Defaults to a storage with the same name",
+			},
+			"migrate_timeout": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(1800),
+				Description: "The number of seconds to wait for a migration to finish",
+			},
+			"allow_data_loss": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Allow removing a volume mount point or changing its type or storage, which destroys the volume and the data on it",
+			},
+			"pending": schema.SetAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "The configuration keys, for example mp0, with changes that take effect when the container is restarted",
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"clone":             containerCloneBlock(),
+			"features":          containerFeaturesBlock(),
+			"rootfs":            containerRootFSBlock(),
+			"mount_point":       containerMountPointBlock(),
+			"network_interface": containerNetworkInterfaceBlock(),
+			"startup":           containerStartupBlock(),
+		},
+	}
+}
+
+func containerFeaturesBlock() schema.SingleNestedBlock {
+	return schema.SingleNestedBlock{
+		Attributes: map[string]schema.Attribute{
+			"nesting": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Allow containers inside the container, which systemd in recent distributions needs",
+			},
+			"keyctl": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Allow the keyctl system call in an unprivileged container, which Docker needs",
+			},
+			"fuse": schema.BoolAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+			},
+			"mount": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "The filesystem types the container may mount, for example nfs and cifs",
+			},
+		},
+	}
+}
+
+func containerStartupBlock() schema.SingleNestedBlock {
+	return schema.SingleNestedBlock{
+		Description: "When the container is started and stopped with the node. Only used when onboot is true",
+		Attributes: map[string]schema.Attribute{
+			"order": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Guests with a lower order are started first and stopped last",
+			},
+			"up_delay": schema.Int64Attribute{
+				Optional:    true,
+				Description: "The number of seconds to wait before starting the next guest",
+			},
+			"down_delay": schema.Int64Attribute{
+				Optional:    true,
+				Description: "The number of seconds to wait for the container to stop when the node shuts down",
+			},
+		},
+	}
+}
+
+func (r *containerResource) ValidateConfig(ctx context.Context, request resource.ValidateConfigRequest, response *resource.ValidateConfigResponse) {
+	var config ContainerResourceModel
+	response.Diagnostics.Append(request.Config.Get(ctx, &config)...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	if config.RootFS == nil {
+		response.Diagnostics.AddAttributeError(
+			path.Root("rootfs"),
+			"Missing root filesystem",
+			"A rootfs block with the storage and size of the root filesystem is required",
+		)
+		return
+	}
+	if config.RootFS.Storage.IsNull() {
+		response.Diagnostics.AddAttributeError(
+			path.Root("rootfs").AtName("storage"),
+			"Missing root filesystem storage",
+			"The storage of the root filesystem is required",
+		)
+	}
+	if config.RootFS.Size.IsNull() {
+		response.Diagnostics.AddAttributeError(
+			path.Root("rootfs").AtName("size"),
+			"Missing root filesystem size",
+			"The size of the root filesystem is required",
+		)
+	}
+	response.Diagnostics.Append(validateMountPoints(config.MountPoints)...)
+	response.Diagnostics.Append(validateContainerClone(config)...)
+
+	if config.Template.ValueBool() && config.Started.ValueBool() {
+		response.Diagnostics.AddAttributeError(
+			path.Root("started"),
+			"Templates can not be started",
+			"Remove started or set it to false when template is true",
+		)
+	}
+}
+
+func (r *containerResource) ModifyPlan(ctx context.Context, request resource.ModifyPlanRequest, response *resource.ModifyPlanResponse) {
+	// Nothing to check when the container is being destroyed
+	if request.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan, state ContainerResourceModel
+	response.Diagnostics.Append(request.Plan.Get(ctx, &plan)...)
+	if !request.State.Raw.IsNull() {
+		response.Diagnostics.Append(request.State.Get(ctx, &state)...)
+	}
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	response.Diagnostics.Append(validateContainerDiskPlan(plan, state)...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+	// A migration can move volumes on local storage to another storage, so their volumes are only known afterwards
+	if !request.State.Raw.IsNull() && plan.Node.ValueString() != state.Node.ValueString() {
+		planMountPointVolumes(plan.MountPoints, nil)
+		if plan.RootFS != nil {
+			plan.RootFS.Volume = types.StringUnknown()
+		}
+	} else {
+		planMountPointVolumes(plan.MountPoints, state.MountPoints)
+	}
+
+	if plan.Clone != nil {
+		var config ContainerResourceModel
+		response.Diagnostics.Append(request.Config.Get(ctx, &config)...)
+		planContainerCloneDefaults(&plan, config, state, !request.State.Raw.IsNull())
+	}
+
+	// Templates are never running
+	if plan.Template.ValueBool() {
+		plan.Started = types.BoolValue(false)
+	}
+
+	response.Diagnostics.Append(response.Plan.Set(ctx, plan)...)
+}
+
+func (r *containerResource) ImportState(ctx context.Context, request resource.ImportStateRequest, response *resource.ImportStateResponse) {
+	importGuest(ctx, request, response)
+}
+
+// containerRequest builds the API request from the plan with the attributes that can be changed after the
+// container has been created. Attributes that are null in the plan are not sent, removing them from an
+// existing container is handled by the caller.
+func containerRequest(ctx context.Context, plan ContainerResourceModel) (api.ContainerRequest, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	containerRequest := api.ContainerRequest{
+		Cores:       plan.Cores.ValueInt64Pointer(),
+		Memory:      plan.Memory.ValueInt64Pointer(),
+		Swap:        plan.Swap.ValueInt64Pointer(),
+		OnBoot:      api.NewBool(plan.OnBoot.ValueBoolPointer()),
+		Description: plan.Description.ValueStringPointer(),
+		Startup:     startupValue(plan.Startup),
+	}
+
+	if !plan.Hostname.IsUnknown() {
+		containerRequest.Hostname = plan.Hostname.ValueStringPointer()
+	}
+
+	features, featuresDiags := featuresValue(ctx, plan.Features)
+	diags.Append(featuresDiags...)
+	containerRequest.Features = features
+
+	if !plan.Tags.IsNull() && !plan.Tags.IsUnknown() {
+		var tags []string
+		diags.Append(plan.Tags.ElementsAs(ctx, &tags, false)...)
+		joined := strings.Join(tags, ";")
+		containerRequest.Tags = &joined
+	}
+
+	return containerRequest, diags
+}
+
+// featuresValue is the property string of the features, for example nesting=1,mount=nfs;cifs.
+// Nil is returned when no feature is enabled.
+func featuresValue(ctx context.Context, features *ContainerFeaturesModel) (*string, diag.Diagnostics) {
+	if features == nil {
+		return nil, nil
+	}
+
+	properties := api.PropertyString{}
+	for key, value := range map[string]types.Bool{"nesting": features.Nesting, "keyctl": features.Keyctl, "fuse": features.Fuse} {
+		if value.ValueBool() {
+			properties.SetFlag(key, true)
+		}
+	}
+
+	var diags diag.Diagnostics
+	if !features.Mount.IsNull() && !features.Mount.IsUnknown() {
+		var mount []string
+		diags = features.Mount.ElementsAs(ctx, &mount, false)
+		if len(mount) > 0 {
+			properties["mount"] = strings.Join(mount, ";")
+		}
+	}
+
+	if len(properties) == 0 {
+		return nil, diags
+	}
+	value := properties.String()
+	return &value, diags
+}
+
+// readFeatures converts the features of the configuration into the model. The block is kept when it is in the
+// prior model, even though Proxmox does not store features that are all disabled.
+func readFeatures(value *string, prior *ContainerFeaturesModel) *ContainerFeaturesModel {
+	if value == nil && prior == nil {
+		return nil
+	}
+
+	properties := api.PropertyString{}
+	if value != nil {
+		properties = api.ParsePropertyString(*value)
+	}
+	features := ContainerFeaturesModel{
+		Nesting: types.BoolValue(properties.Flag("nesting", false)),
+		Keyctl:  types.BoolValue(properties.Flag("keyctl", false)),
+		Fuse:    types.BoolValue(properties.Flag("fuse", false)),
+		Mount:   types.ListNull(types.StringType),
+	}
+	if properties["mount"] != "" {
+		var mount []attr.Value
+		for _, filesystem := range strings.Split(properties["mount"], ";") {
+			mount = append(mount, types.StringValue(filesystem))
+		}
+		features.Mount = types.ListValueMust(types.StringType, mount)
+	} else if prior != nil && !prior.Mount.IsNull() && len(prior.Mount.Elements()) == 0 {
+		features.Mount = prior.Mount
+	}
+	return &features
+}
+
+// startupValue is the property string of the startup order, for example order=1,up=30,down=60
+func startupValue(startup *ContainerStartupModel) *string {
+	if startup == nil {
+		return nil
+	}
+
+	properties := api.PropertyString{}
+	for key, value := range map[string]types.Int64{"order": startup.Order, "up": startup.UpDelay, "down": startup.DownDelay} {
+		if !value.IsNull() {
+			properties[key] = strconv.FormatInt(value.ValueInt64(), 10)
+		}
+	}
+	if len(properties) == 0 {
+		return nil
+	}
+	value := properties.String()
+	return &value
+}
+
+// readStartup converts the startup order of the configuration into the model. Like the features, an empty
+// block in the prior model is kept.
+func readStartup(value *string, prior *ContainerStartupModel) (*ContainerStartupModel, error) {
+	if value == nil && prior == nil {
+		return nil, nil
+	}
+
+	startup := ContainerStartupModel{Order: types.Int64Null(), UpDelay: types.Int64Null(), DownDelay: types.Int64Null()}
+	if value == nil {
+		return &startup, nil
+	}
+	properties := api.ParsePropertyString(*value)
+	for key, target := range map[string]*types.Int64{"order": &startup.Order, "up": &startup.UpDelay, "down": &startup.DownDelay} {
+		if properties[key] == "" {
+			continue
+		}
+		number, err := strconv.ParseInt(properties[key], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("the startup %s %q is not a number", key, properties[key])
+		}
+		*target = types.Int64Value(number)
+	}
+	return &startup, nil
+}
+
+// readContainer refreshes the model from the configuration that Proxmox has for the container. The OS template,
+// password and SSH keys are not returned by Proxmox, so they keep the values from the model. A clone only has
+// the settings and devices of its source that the model declares.
+func (r *containerResource) readContainer(model *ContainerResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+	prior := *model
+
+	node := model.Node.ValueString()
+	vmid := model.VMID.ValueInt64()
+	config, err := api.GetContainerConfig(r.client, node, vmid)
+	if err != nil {
+		diags.AddError(
+			"Error reading Proxmox container",
+			fmt.Sprintf("Could not read the Proxmox container: %s/%d: %s", node, vmid, err.Error()),
+		)
+		return diags
+	}
+
+	status, err := api.GetContainerStatus(r.client, node, vmid)
+	if err != nil {
+		diags.AddError(
+			"Error reading Proxmox container",
+			fmt.Sprintf("Could not read the status of the Proxmox container: %s/%d: %s", node, vmid, err.Error()),
+		)
+		return diags
+	}
+
+	model.ID = types.StringValue(strconv.FormatInt(vmid, 10))
+	model.Started = types.BoolValue(status.Status == "running")
+
+	// Settings of the provider are not stored by Proxmox, use the defaults after an import
+	if model.RebootAfterUpdate.IsNull() {
+		model.RebootAfterUpdate = types.BoolValue(false)
+	}
+	if model.ShutdownTimeout.IsNull() {
+		model.ShutdownTimeout = types.Int64Value(180)
+	}
+	if model.ForceStop.IsNull() {
+		model.ForceStop = types.BoolValue(false)
+	}
+	if model.Migrate.IsNull() {
+		model.Migrate = types.BoolValue(true)
+	}
+	if model.MigrateTimeout.IsNull() {
+		model.MigrateTimeout = types.Int64Value(1800)
+	}
+	if model.AllowDataLoss.IsNull() {
+		model.AllowDataLoss = types.BoolValue(false)
+	}
+	model.Hostname = types.StringPointerValue(config.Hostname)
+	model.Unprivileged = types.BoolValue(config.Unprivileged != nil && bool(*config.Unprivileged))
+	model.Cores = types.Int64PointerValue(config.Cores.Pointer())
+	model.Memory = types.Int64Value(valueOrDefault(config.Memory.Pointer(), 512))
+	model.Swap = types.Int64Value(valueOrDefault(config.Swap.Pointer(), 512))
+	model.OnBoot = types.BoolValue(config.OnBoot != nil && bool(*config.OnBoot))
+	model.Template = types.BoolValue(config.Template != nil && bool(*config.Template))
+	model.Features = readFeatures(config.Features, model.Features)
+
+	// Proxmox stores the description as a comment and adds a newline to the end
+	model.Description = types.StringNull()
+	if config.Description != nil {
+		model.Description = types.StringValue(strings.TrimRight(*config.Description, "\n"))
+	}
+
+	model.Startup, err = readStartup(config.Startup, model.Startup)
+	if err == nil {
+		model.RootFS, err = readRootFS(config.Devices)
+	}
+	if err == nil {
+		model.MountPoints, err = readMountPoints(config.Devices)
+	}
+	if err == nil {
+		model.NetworkInterfaces, err = readContainerNetworkInterfaces(config.Devices)
+	}
+	if err != nil {
+		diags.AddError(
+			"Error reading Proxmox container",
+			fmt.Sprintf("Could not read the configuration of the Proxmox container: %s/%d: %s", node, vmid, err.Error()),
+		)
+		return diags
+	}
+
+	changes, err := api.GetContainerPending(r.client, node, vmid)
+	if err != nil {
+		diags.AddError(
+			"Error reading Proxmox container",
+			fmt.Sprintf("Could not read the pending changes of the Proxmox container: %s/%d: %s", node, vmid, err.Error()),
+		)
+		return diags
+	}
+	var pending []attr.Value
+	for _, change := range changes {
+		if change.IsPending() {
+			pending = append(pending, types.StringValue(change.Key))
+		}
+	}
+	var setDiags diag.Diagnostics
+	model.Pending, setDiags = types.SetValue(types.StringType, pending)
+	diags.Append(setDiags...)
+
+	tags, listDiags := splitList(config.Tags)
+	diags.Append(listDiags...)
+	model.Tags = types.SetNull(types.StringType)
+	if !tags.IsNull() {
+		var setDiags diag.Diagnostics
+		model.Tags, setDiags = types.SetValue(types.StringType, tags.Elements())
+		diags.Append(setDiags...)
+	}
+
+	if model.Clone != nil {
+		keepContainerDeclared(model, prior)
+	}
+
+	return diags
+}
+
+func (r *containerResource) Create(ctx context.Context, request resource.CreateRequest, response *resource.CreateResponse) {
+	var plan ContainerResourceModel
+	diags := request.Plan.Get(ctx, &plan)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.VMID.IsUnknown() {
+		vmid, err := allocator.allocate(r.client, 0, 0)
+		if err != nil {
+			response.Diagnostics.AddError(
+				"Error creating Proxmox container",
+				"Could not find a free VMID for the Proxmox container: "+err.Error(),
+			)
+			return
+		}
+		plan.VMID = types.Int64Value(vmid)
+	}
+
+	if plan.Clone != nil {
+		r.createClone(ctx, plan, response)
+		return
+	}
+
+	createRequest, diags := containerRequest(ctx, plan)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+	createRequest.VMID = plan.VMID.ValueInt64Pointer()
+	createRequest.OSTemplate = plan.OSTemplate.ValueStringPointer()
+	createRequest.Unprivileged = api.NewBool(plan.Unprivileged.ValueBoolPointer())
+	createRequest.Password = plan.Password.ValueStringPointer()
+	if !plan.SSHPublicKeys.IsNull() && !plan.SSHPublicKeys.IsUnknown() {
+		var keys []string
+		response.Diagnostics.Append(plan.SSHPublicKeys.ElementsAs(ctx, &keys, false)...)
+		joined := strings.Join(keys, "\n")
+		createRequest.SSHPublicKeys = &joined
+	}
+	createRequest.Devices = map[string]string{"rootfs": newRootFSValue(plan.RootFS)}
+	for _, mountPoint := range plan.MountPoints {
+		createRequest.Devices[mountPointName(mountPoint.Slot.ValueInt64())] = newMountPointValue(mountPoint)
+	}
+	for i, nic := range plan.NetworkInterfaces {
+		createRequest.Devices[fmt.Sprintf("net%d", i)] = containerNetworkInterfaceValue(nic)
+	}
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	upid, err := api.CreateContainer(r.client, plan.Node.ValueString(), &createRequest)
+	if err == nil {
+		err = api.WaitForTask(r.client, upid, containerTaskTimeout)
+	}
+	if err == nil {
+		err = r.setPowerState(plan, false)
+	}
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error creating Proxmox container",
+			fmt.Sprintf("Could not create the Proxmox container: %d: %s", plan.VMID.ValueInt64(), err.Error()),
+		)
+		return
+	}
+
+	response.Diagnostics.Append(r.readContainer(&plan)...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	diags = response.State.Set(ctx, plan)
+	response.Diagnostics.Append(diags...)
+}
+
+// createClone clones the source of the clone block and then applies the rest of the plan to the clone.
+// The clone is saved to the state when applying the plan fails, so that Terraform can replace it.
+func (r *containerResource) createClone(ctx context.Context, plan ContainerResourceModel, response *resource.CreateResponse) {
+	err := r.cloneContainer(plan)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error creating Proxmox container",
+			fmt.Sprintf("Could not clone the Proxmox container: %d: %s", plan.VMID.ValueInt64(), err.Error()),
+		)
+		return
+	}
+
+	clone := plan
+	response.Diagnostics.Append(r.readContainer(&clone)...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+	setContainerCloneDefaults(&plan, clone)
+
+	// The volumes of the clone are only known now, they can grow but not shrink
+	diags := validateContainerDiskPlan(plan, clone)
+	if plan.Unprivileged.ValueBool() != clone.Unprivileged.ValueBool() {
+		diags.AddAttributeError(
+			path.Root("unprivileged"),
+			"Unprivileged does not match the source",
+			fmt.Sprintf("A clone keeps the unprivileged setting of its source, set unprivileged to %t or remove it", clone.Unprivileged.ValueBool()),
+		)
+	}
+	if !diags.HasError() {
+		var err error
+		diags, err = r.applyChanges(ctx, plan, clone)
+		if err == nil && !diags.HasError() {
+			err = r.setPowerState(plan, false)
+		}
+		if err != nil {
+			diags.AddError(
+				"Error creating Proxmox container",
+				fmt.Sprintf("Could not configure the clone of the Proxmox container: %d: %s", plan.VMID.ValueInt64(), err.Error()),
+			)
+		}
+	}
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		response.Diagnostics.Append(response.State.Set(ctx, clone)...)
+		return
+	}
+
+	response.Diagnostics.Append(r.readContainer(&plan)...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	response.Diagnostics.Append(response.State.Set(ctx, plan)...)
+}
+
+func (r *containerResource) Read(ctx context.Context, request resource.ReadRequest, response *resource.ReadResponse) {
+	var state ContainerResourceModel
+	diags := request.State.Get(ctx, &state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	response.Diagnostics.Append(r.readContainer(&state)...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	diags = response.State.Set(ctx, &state)
+	response.Diagnostics.Append(diags...)
+}
+
+func (r *containerResource) Update(ctx context.Context, request resource.UpdateRequest, response *resource.UpdateResponse) {
+	var plan, state ContainerResourceModel
+	response.Diagnostics.Append(request.Plan.Get(ctx, &plan)...)
+	response.Diagnostics.Append(request.State.Get(ctx, &state)...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	// The configuration is changed on the new node, so the container is migrated first.
+	// Its volumes may have moved to another storage, so the state is read again.
+	if plan.Node.ValueString() != state.Node.ValueString() {
+		err := r.migrateContainer(plan, state)
+		if err != nil {
+			response.Diagnostics.AddError(
+				"Error migrating Proxmox container",
+				fmt.Sprintf("Could not migrate the Proxmox container %d from %s to %s: %s", state.VMID.ValueInt64(), state.Node.ValueString(), plan.Node.ValueString(), err.Error()),
+			)
+			return
+		}
+		state.Node = plan.Node
+		response.Diagnostics.Append(r.readContainer(&state)...)
+		if response.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	diags, err := r.applyChanges(ctx, plan, state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+	if err == nil {
+		err = r.updatePowerState(plan)
+	}
+	var leftBehind []string
+	if err == nil {
+		leftBehind, err = r.destroyRemovedVolumes(plan, planContainerDiskChanges(plan, state).removedVolumes)
+	}
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error updating Proxmox container",
+			fmt.Sprintf("Could not update the Proxmox container: %d. Got this error: %s", plan.VMID.ValueInt64(), err.Error()),
+		)
+		return
+	}
+
+	response.Diagnostics.Append(r.readContainer(&plan)...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	var pending []string
+	response.Diagnostics.Append(plan.Pending.ElementsAs(ctx, &pending, false)...)
+	if len(pending) > 0 {
+		sort.Strings(pending)
+		response.Diagnostics.AddWarning(
+			"Proxmox container has pending changes",
+			fmt.Sprintf("Some changes to the Proxmox container %d could not be applied while it is running and take effect when it is restarted: %s", plan.VMID.ValueInt64(), strings.Join(pending, ", ")),
+		)
+	}
+
+	if len(leftBehind) > 0 {
+		response.Diagnostics.AddWarning(
+			"Proxmox container volumes were not destroyed",
+			fmt.Sprintf("The mount points of the Proxmox container %d are only detached when it is restarted, so their volumes could not be destroyed. "+
+				"They are kept as unused disks after the restart and have to be removed by hand: %s", plan.VMID.ValueInt64(), strings.Join(leftBehind, ", ")),
+		)
+	}
+
+	diags = response.State.Set(ctx, plan)
+	response.Diagnostics.Append(diags...)
+}
+
+// containerChanges describes how the configuration of an existing container needs to change
+type containerChanges struct {
+	// request has the attributes and devices that are new or have changed and the ones to delete
+	request api.ContainerRequest
+	disks   containerDiskChanges
+}
+
+// empty reports whether the request has nothing to change
+func (c containerChanges) empty() bool {
+	request := c.request
+	request.Devices = nil
+	return len(c.request.Devices) == 0 && reflect.DeepEqual(request, api.ContainerRequest{})
+}
+
+// planContainerChanges returns the changes from the state to the plan. Only the attributes that changed are sent,
+// because Proxmox records every attribute it is sent for a running container as pending.
+func planContainerChanges(ctx context.Context, plan ContainerResourceModel, state ContainerResourceModel) (containerChanges, diag.Diagnostics) {
+	updateRequest, diags := containerRequest(ctx, plan)
+	current, currentDiags := containerRequest(ctx, state)
+	diags.Append(currentDiags...)
+	if diags.HasError() {
+		return containerChanges{}, diags
+	}
+	var removed removedAttributes
+	removed.check("cores", plan.Cores, state.Cores)
+	removed.check("tags", plan.Tags, state.Tags)
+	removed.check("description", plan.Description, state.Description)
+	if updateRequest.Features == nil && current.Features != nil {
+		removed = append(removed, "features")
+	}
+	if updateRequest.Startup == nil && current.Startup != nil {
+		removed = append(removed, "startup")
+	}
+
+	keepChanged(&updateRequest.Hostname, current.Hostname)
+	keepChanged(&updateRequest.Features, current.Features)
+	keepChanged(&updateRequest.Cores, current.Cores)
+	keepChanged(&updateRequest.Memory, current.Memory)
+	keepChanged(&updateRequest.Swap, current.Swap)
+	keepChanged(&updateRequest.Startup, current.Startup)
+	keepChanged(&updateRequest.OnBoot, current.OnBoot)
+	keepChanged(&updateRequest.Tags, current.Tags)
+	keepChanged(&updateRequest.Description, current.Description)
+
+	disks := planContainerDiskChanges(plan, state)
+	updateRequest.Devices = disks.devices
+	removed = append(removed, disks.removed...)
+
+	networkInterfaces, removedNetworkInterfaces := planContainerNetworkInterfaceChanges(plan.NetworkInterfaces, state.NetworkInterfaces)
+	for key, value := range networkInterfaces {
+		updateRequest.Devices[key] = value
+	}
+	removed = append(removed, removedNetworkInterfaces...)
+	updateRequest.Delete = removed.value()
+
+	return containerChanges{request: updateRequest, disks: disks}, diags
+}
+
+// applyChanges changes the configuration of the container from the state to the plan. The diagnostics report
+// problems with the plan and the error reports problems with Proxmox.
+func (r *containerResource) applyChanges(ctx context.Context, plan ContainerResourceModel, state ContainerResourceModel) (diag.Diagnostics, error) {
+	changes, diags := planContainerChanges(ctx, plan, state)
+	if diags.HasError() {
+		return diags, nil
+	}
+	disks := changes.disks
+
+	node := plan.Node.ValueString()
+	vmid := plan.VMID.ValueInt64()
+
+	// Proxmox rejects an update without any options
+	if !changes.empty() {
+		err := api.UpdateContainerConfig(r.client, node, vmid, &changes.request)
+		if err != nil {
+			return diags, err
+		}
+	}
+
+	for _, disk := range sortedKeys(disks.resizes) {
+		upid, err := api.ResizeContainerDisk(r.client, node, vmid, disk, fmt.Sprintf("%dG", disks.resizes[disk]))
+		if err == nil {
+			err = api.WaitForTask(r.client, upid, containerTaskTimeout)
+		}
+		if err != nil {
+			return diags, err
+		}
+	}
+	return diags, nil
+}
+
+// destroyRemovedVolumes destroys the volumes of removed mount points. Proxmox keeps a detached volume as an
+// unused disk, while the container is running the mount point is only detached when it restarts. The volumes
+// that are still attached are returned, they can only be destroyed after the restart.
+func (r *containerResource) destroyRemovedVolumes(plan ContainerResourceModel, volumes []string) ([]string, error) {
+	if len(volumes) == 0 {
+		return nil, nil
+	}
+
+	node := plan.Node.ValueString()
+	vmid := plan.VMID.ValueInt64()
+	config, err := api.GetContainerConfig(r.client, node, vmid)
+	if err != nil {
+		return nil, err
+	}
+	attached := attachedVolumes(config.Devices, volumes)
+	unused := unusedDisks(config.Devices, volumes)
+	if len(unused) == 0 {
+		return attached, nil
+	}
+
+	remove := strings.Join(unused, ",")
+	return attached, api.UpdateContainerConfig(r.client, node, vmid, &api.ContainerRequest{Delete: &remove})
+}
+
+// keepChanged clears the value of the request when it is the same as the current value
+func keepChanged[T comparable](value **T, current *T) {
+	if *value != nil && current != nil && **value == *current {
+		*value = nil
+	}
+}
+
+func (r *containerResource) Delete(ctx context.Context, request resource.DeleteRequest, response *resource.DeleteResponse) {
+	var state ContainerResourceModel
+	diags := request.State.Get(ctx, &state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	node := state.Node.ValueString()
+	vmid := state.VMID.ValueInt64()
+
+	// Proxmox refuses to destroy a running container
+	status, err := api.GetContainerStatus(r.client, node, vmid)
+	if err == nil && status.Status != "stopped" {
+		var upid string
+		upid, err = api.StopContainer(r.client, node, vmid)
+		if err == nil {
+			err = api.WaitForTask(r.client, upid, containerTaskTimeout)
+		}
+	}
+	if err == nil {
+		var upid string
+		upid, err = api.DeleteContainer(r.client, node, vmid)
+		if err == nil {
+			err = api.WaitForTask(r.client, upid, containerTaskTimeout)
+		}
+	}
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error deleting Proxmox container",
+			fmt.Sprintf("Could not delete the Proxmox container: %d. Got this error: %s", vmid, err.Error()),
+		)
+		return
+	}
+}
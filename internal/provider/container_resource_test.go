@@ -0,0 +1,275 @@
+package provider
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"reflect"
+	"testing"
+)
+
+func TestContainerResource_Create(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+resource "proxmox_container" "test" {
+  node        = "pve"
+  vmid        = 9101
+  hostname    = "terraform-test"
+  ostemplate  = "local:vztmpl/debian-12-standard_12.7-1_amd64.tar.zst"
+  cores       = 1
+  memory      = 256
+  tags        = ["terraform", "test"]
+  description = "Test container"
+  password    = "terraform-test-password"
+
+  ssh_public_keys = ["ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIGJ5ZkB6cWxrYXJzZGZramFsc2RmamFsc2Rma2ps terraform@test"]
+
+  features {
+    nesting = true
+  }
+
+  rootfs {
+    storage = "local-lvm"
+    size    = 4
+  }
+
+  network_interface {
+    name     = "eth0"
+    bridge   = "vmbr0"
+    ip       = "dhcp"
+    firewall = true
+  }
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("proxmox_container.test", "id", "9101"),
+					resource.TestCheckResourceAttr("proxmox_container.test", "hostname", "terraform-test"),
+					resource.TestCheckResourceAttr("proxmox_container.test", "unprivileged", "true"),
+					resource.TestCheckResourceAttr("proxmox_container.test", "memory", "256"),
+					resource.TestCheckResourceAttr("proxmox_container.test", "swap", "512"),
+					resource.TestCheckResourceAttr("proxmox_container.test", "tags.#", "2"),
+					resource.TestCheckResourceAttr("proxmox_container.test", "features.nesting", "true"),
+					resource.TestCheckResourceAttr("proxmox_container.test", "features.keyctl", "false"),
+					resource.TestCheckResourceAttr("proxmox_container.test", "rootfs.size", "4"),
+					resource.TestCheckResourceAttrSet("proxmox_container.test", "rootfs.volume"),
+					resource.TestCheckResourceAttr("proxmox_container.test", "network_interface.#", "1"),
+					resource.TestCheckResourceAttrSet("proxmox_container.test", "network_interface.0.mac"),
+					resource.TestCheckResourceAttr("proxmox_container.test", "started", "false"),
+					resource.TestCheckResourceAttr("proxmox_container.test", "pending.#", "0"),
+				),
+			},
+			{
+				ResourceName:            "proxmox_container.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateId:           "pve/9101",
+				ImportStateVerifyIgnore: []string{"ostemplate", "password", "ssh_public_keys"},
+			},
+			{
+				Config: providerConfig + `
+resource "proxmox_container" "test" {
+  node       = "pve"
+  vmid       = 9101
+  hostname   = "terraform-test-renamed"
+  ostemplate = "local:vztmpl/debian-12-standard_12.7-1_amd64.tar.zst"
+  memory     = 512
+  swap       = 0
+  onboot     = true
+  started    = true
+  password   = "terraform-test-password"
+
+  ssh_public_keys = ["ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIGJ5ZkB6cWxrYXJzZGZramFsc2RmamFsc2Rma2ps terraform@test"]
+
+  features {
+    nesting = true
+    keyctl  = true
+  }
+
+  startup {
+    order    = 2
+    up_delay = 10
+  }
+
+  rootfs {
+    storage = "local-lvm"
+    size    = 6
+  }
+
+  mount_point {
+    slot    = 0
+    storage = "local-lvm"
+    size    = 1
+    path    = "/srv/data"
+    backup  = true
+  }
+
+  network_interface {
+    name   = "eth0"
+    bridge = "vmbr0"
+    ip     = "192.168.254.10/24"
+    gw     = "192.168.254.1"
+    tag    = 254
+  }
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("proxmox_container.test", "hostname", "terraform-test-renamed"),
+					resource.TestCheckNoResourceAttr("proxmox_container.test", "cores"),
+					resource.TestCheckNoResourceAttr("proxmox_container.test", "tags"),
+					resource.TestCheckResourceAttr("proxmox_container.test", "swap", "0"),
+					resource.TestCheckResourceAttr("proxmox_container.test", "features.keyctl", "true"),
+					resource.TestCheckResourceAttr("proxmox_container.test", "startup.order", "2"),
+					resource.TestCheckResourceAttr("proxmox_container.test", "rootfs.size", "6"),
+					resource.TestCheckResourceAttr("proxmox_container.test", "mount_point.#", "1"),
+					resource.TestCheckResourceAttrSet("proxmox_container.test", "mount_point.0.volume"),
+					resource.TestCheckResourceAttr("proxmox_container.test", "mount_point.0.type", "volume"),
+					resource.TestCheckResourceAttr("proxmox_container.test", "mount_point.0.backup", "true"),
+					resource.TestCheckResourceAttr("proxmox_container.test", "network_interface.0.tag", "254"),
+					resource.TestCheckResourceAttr("proxmox_container.test", "started", "true"),
+				),
+			},
+		},
+	})
+}
+
+func TestContainerResource_Clone(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+resource "proxmox_container" "template" {
+  node       = "pve"
+  vmid       = 9102
+  hostname   = "terraform-template"
+  ostemplate = "local:vztmpl/debian-12-standard_12.7-1_amd64.tar.zst"
+  template   = true
+
+  rootfs {
+    storage = "local-lvm"
+    size    = 4
+  }
+
+  network_interface {
+    name   = "eth0"
+    bridge = "vmbr0"
+    ip     = "dhcp"
+  }
+}
+
+resource "proxmox_container" "test" {
+  node     = "pve"
+  vmid     = 9103
+  hostname = "terraform-clone"
+  memory   = 256
+  started  = true
+
+  clone {
+    source_vmid = proxmox_container.template.vmid
+    full        = false
+  }
+
+  rootfs {
+    storage = "local-lvm"
+    size    = 6
+  }
+
+  network_interface {
+    name   = "eth0"
+    bridge = "vmbr0"
+    ip     = "dhcp"
+    tag    = 254
+  }
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("proxmox_container.template", "template", "true"),
+					resource.TestCheckResourceAttr("proxmox_container.template", "started", "false"),
+					resource.TestCheckResourceAttr("proxmox_container.test", "id", "9103"),
+					resource.TestCheckResourceAttr("proxmox_container.test", "hostname", "terraform-clone"),
+					resource.TestCheckResourceAttr("proxmox_container.test", "memory", "256"),
+					resource.TestCheckResourceAttr("proxmox_container.test", "template", "false"),
+					resource.TestCheckResourceAttr("proxmox_container.test", "clone.full", "false"),
+					resource.TestCheckResourceAttr("proxmox_container.test", "rootfs.size", "6"),
+					resource.TestCheckResourceAttr("proxmox_container.test", "network_interface.0.tag", "254"),
+					resource.TestCheckResourceAttr("proxmox_container.test", "started", "true"),
+				),
+			},
+		},
+	})
+}
+
+func TestFeaturesValue(t *testing.T) {
+	features := &ContainerFeaturesModel{
+		Nesting: types.BoolValue(true),
+		Keyctl:  types.BoolValue(false),
+		Fuse:    types.BoolValue(true),
+		Mount:   types.ListValueMust(types.StringType, []attr.Value{types.StringValue("nfs"), types.StringValue("cifs")}),
+	}
+	value, diags := featuresValue(context.Background(), features)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	if value == nil || *value != "fuse=1,mount=nfs;cifs,nesting=1" {
+		t.Errorf("Incorrect features value: %v", value)
+	}
+
+	read := readFeatures(value, nil)
+	if !reflect.DeepEqual(read, features) {
+		t.Errorf("Incorrect features read. Expected %+v, got %+v", features, read)
+	}
+
+	empty := &ContainerFeaturesModel{Nesting: types.BoolValue(false), Keyctl: types.BoolValue(false), Fuse: types.BoolValue(false), Mount: types.ListNull(types.StringType)}
+	value, _ = featuresValue(context.Background(), empty)
+	if value != nil {
+		t.Errorf("Expected no value when every feature is disabled, got %v", *value)
+	}
+	if read := readFeatures(nil, empty); !reflect.DeepEqual(read, empty) {
+		t.Errorf("Expected an empty block to be kept, got %+v", read)
+	}
+	if read := readFeatures(nil, nil); read != nil {
+		t.Errorf("Expected no features, got %+v", read)
+	}
+}
+
+func TestStartupValue(t *testing.T) {
+	startup := &ContainerStartupModel{Order: types.Int64Value(1), UpDelay: types.Int64Null(), DownDelay: types.Int64Value(60)}
+	value := startupValue(startup)
+	if value == nil || *value != "down=60,order=1" {
+		t.Errorf("Incorrect startup value: %v", value)
+	}
+
+	read, err := readStartup(value, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(read, startup) {
+		t.Errorf("Incorrect startup read. Expected %+v, got %+v", startup, read)
+	}
+
+	invalid := "order=first"
+	_, err = readStartup(&invalid, nil)
+	if err == nil {
+		t.Error("Expected an error for an invalid order")
+	}
+}
+
+func TestKeepChanged(t *testing.T) {
+	hostname, current := "web", "web"
+	value := &hostname
+	keepChanged(&value, &current)
+	if value != nil {
+		t.Errorf("Expected an unchanged value to be cleared")
+	}
+
+	memory, currentMemory := int64(1024), int64(512)
+	memoryValue := &memory
+	keepChanged(&memoryValue, &currentMemory)
+	if memoryValue == nil {
+		t.Errorf("Expected a changed value to be kept")
+	}
+}
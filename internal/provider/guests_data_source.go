@@ -0,0 +1,293 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/clincha-org/proxmox-api/pkg/proxmox"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"regexp"
+	"sort"
+	"terraform-provider-proxmox/internal/api"
+)
+
+var (
+	_ datasource.DataSource              = &guestsDataSource{}
+	_ datasource.DataSourceWithConfigure = &guestsDataSource{}
+)
+
+// GuestFilter A guest is only returned when it matches every filter block. Every tag must be set on the guest.
+type GuestFilter struct {
+	Node      types.String `tfsdk:"node"`
+	Tags      types.List   `tfsdk:"tags"`
+	Pool      types.String `tfsdk:"pool"`
+	Status    types.String `tfsdk:"status"`
+	Template  types.Bool   `tfsdk:"template"`
+	NameRegex types.String `tfsdk:"name_regex"`
+}
+
+// Guest A virtual machine or container in the cluster
+type Guest struct {
+	VMID      types.Int64  `tfsdk:"vmid"`
+	Node      types.String `tfsdk:"node"`
+	Name      types.String `tfsdk:"name"`
+	Status    types.String `tfsdk:"status"`
+	Tags      types.List   `tfsdk:"tags"`
+	Pool      types.String `tfsdk:"pool"`
+	Template  types.Bool   `tfsdk:"template"`
+	MaxMemory types.Int64  `tfsdk:"max_memory"`
+	MaxDisk   types.Int64  `tfsdk:"max_disk"`
+	Uptime    types.Int64  `tfsdk:"uptime"`
+}
+
+// guestsDataSource lists the guests of one type in the cluster. The same data source backs proxmox_vms and
+// proxmox_containers, the guests are returned in the attribute with the name of the data source.
+type guestsDataSource struct {
+	client *proxmox.Client
+	// guestType is the type of the guests in the cluster resources, either qemu or lxc
+	guestType string
+	// name is both the suffix of the type name and the attribute that holds the guests
+	name string
+}
+
+func NewVirtualMachinesDataSource() datasource.DataSource {
+	return &guestsDataSource{guestType: "qemu", name: "vms"}
+}
+
+func NewContainersDataSource() datasource.DataSource {
+	return &guestsDataSource{guestType: "lxc", name: "containers"}
+}
+
+func (d *guestsDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_" + d.name
+}
+
+func (d *guestsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			d.name: schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "The matching guests, ordered by VMID",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"vmid": schema.Int64Attribute{
+							Computed: true,
+						},
+						"node": schema.StringAttribute{
+							Computed: true,
+						},
+						"name": schema.StringAttribute{
+							Computed: true,
+						},
+						"status": schema.StringAttribute{
+							Computed: true,
+						},
+						"tags": schema.ListAttribute{
+							Computed:    true,
+							ElementType: types.StringType,
+						},
+						"pool": schema.StringAttribute{
+							Computed: true,
+						},
+						"template": schema.BoolAttribute{
+							Computed: true,
+						},
+						"max_memory": schema.Int64Attribute{
+							Computed:    true,
+							Description: "The memory of the guest in bytes",
+						},
+						"max_disk": schema.Int64Attribute{
+							Computed:    true,
+							Description: "The size of the root disk of the guest in bytes",
+						},
+						"uptime": schema.Int64Attribute{
+							Computed:    true,
+							Description: "The number of seconds the guest has been running",
+						},
+					},
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"filter": schema.ListNestedBlock{
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"node": schema.StringAttribute{
+							Optional:    true,
+							Description: "Only return guests on this node",
+						},
+						"tags": schema.ListAttribute{
+							Optional:    true,
+							ElementType: types.StringType,
+							Description: "Only return guests that have all of these tags",
+						},
+						"pool": schema.StringAttribute{
+							Optional:    true,
+							Description: "Only return guests in this pool",
+						},
+						"status": schema.StringAttribute{
+							Optional:    true,
+							Description: "Only return guests with this status, for example running",
+						},
+						"template": schema.BoolAttribute{
+							Optional:    true,
+							Description: "Only return templates when true, or only guests that are not templates when false",
+						},
+						"name_regex": schema.StringAttribute{
+							Optional:    true,
+							Description: "Only return guests whose name matches this regular expression",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *guestsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var filters []GuestFilter
+	diags := req.Config.GetAttribute(ctx, path.Root("filter"), &filters)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var compiled []guestFilter
+	for i, filter := range filters {
+		guestFilter, err := newGuestFilter(filter)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("filter").AtListIndex(i).AtName("name_regex"), "Invalid guest filter", err.Error())
+			return
+		}
+		resp.Diagnostics.Append(filter.Tags.ElementsAs(ctx, &guestFilter.tags, false)...)
+		compiled = append(compiled, guestFilter)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resources, err := api.GetClusterResources(d.client, "vm")
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to read Proxmox cluster resources",
+			err.Error(),
+		)
+		return
+	}
+
+	guests := matchingGuests(resources, d.guestType, compiled)
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("filter"), filters)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(d.name), guests)...)
+}
+
+// guestFilter is a GuestFilter with its tags converted and its regular expression compiled
+type guestFilter struct {
+	GuestFilter
+	tags      []string
+	nameRegex *regexp.Regexp
+}
+
+func newGuestFilter(filter GuestFilter) (guestFilter, error) {
+	compiled := guestFilter{GuestFilter: filter}
+	if !filter.NameRegex.IsNull() {
+		nameRegex, err := regexp.Compile(filter.NameRegex.ValueString())
+		if err != nil {
+			return compiled, fmt.Errorf("the name_regex %q is not a valid regular expression: %w", filter.NameRegex.ValueString(), err)
+		}
+		compiled.nameRegex = nameRegex
+	}
+	return compiled, nil
+}
+
+func (f guestFilter) matches(resource api.ClusterResource) bool {
+	if !f.Node.IsNull() && resource.Node != f.Node.ValueString() {
+		return false
+	}
+	if !f.Pool.IsNull() && resource.Pool != f.Pool.ValueString() {
+		return false
+	}
+	if !f.Status.IsNull() && resource.Status != f.Status.ValueString() {
+		return false
+	}
+	if !f.Template.IsNull() && bool(resource.Template) != f.Template.ValueBool() {
+		return false
+	}
+	if f.nameRegex != nil && !f.nameRegex.MatchString(resource.Name) {
+		return false
+	}
+	for _, tag := range f.tags {
+		if !hasTag(resource.Tags, tag) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchingGuests returns the guests of the type that match every filter, ordered by VMID
+func matchingGuests(resources []api.ClusterResource, guestType string, filters []guestFilter) []Guest {
+	guests := []Guest{}
+	for _, resource := range resources {
+		if resource.Type != guestType || !matchesGuestFilters(resource, filters) {
+			continue
+		}
+		guests = append(guests, guestState(resource))
+	}
+	sort.Slice(guests, func(i, j int) bool {
+		return guests[i].VMID.ValueInt64() < guests[j].VMID.ValueInt64()
+	})
+	return guests
+}
+
+func matchesGuestFilters(resource api.ClusterResource, filters []guestFilter) bool {
+	for _, filter := range filters {
+		if !filter.matches(resource) {
+			return false
+		}
+	}
+	return true
+}
+
+func guestState(resource api.ClusterResource) Guest {
+	tags := []attr.Value{}
+	for _, tag := range splitTags(resource.Tags) {
+		tags = append(tags, types.StringValue(tag))
+	}
+
+	guest := Guest{
+		VMID:      types.Int64Value(int64(resource.VMID)),
+		Node:      types.StringValue(resource.Node),
+		Name:      types.StringValue(resource.Name),
+		Status:    types.StringValue(resource.Status),
+		Tags:      types.ListValueMust(types.StringType, tags),
+		Pool:      types.StringNull(),
+		Template:  types.BoolValue(bool(resource.Template)),
+		MaxMemory: types.Int64Value(int64(resource.MaxMem)),
+		MaxDisk:   types.Int64Value(int64(resource.MaxDisk)),
+		Uptime:    types.Int64Value(int64(resource.Uptime)),
+	}
+	if resource.Pool != "" {
+		guest.Pool = types.StringValue(resource.Pool)
+	}
+	return guest
+}
+
+func (d *guestsDataSource) Configure(_ context.Context, request datasource.ConfigureRequest, response *datasource.ConfigureResponse) {
+	if request.ProviderData == nil {
+		return
+	}
+
+	client, ok := request.ProviderData.(*proxmox.Client)
+	if !ok {
+		response.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *proxmox.Client, got %T. Please report this error to the developer", request.ProviderData),
+		)
+		return
+	}
+
+	d.client = client
+}
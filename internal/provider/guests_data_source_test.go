@@ -0,0 +1,89 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"regexp"
+	"terraform-provider-proxmox/internal/api"
+	"testing"
+)
+
+func TestVirtualMachinesDataSource_Read(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+resource "proxmox_vm" "test" {
+  node = "pve"
+  vmid = 9005
+  name = "terraform-inventory"
+  tags = ["terraform-inventory"]
+}
+
+data "proxmox_vms" "test" {
+  filter {
+    tags       = ["terraform-inventory"]
+    name_regex = "^terraform-"
+    template   = false
+  }
+
+  depends_on = [proxmox_vm.test]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.proxmox_vms.test", "vms.#", "1"),
+					resource.TestCheckResourceAttr("data.proxmox_vms.test", "vms.0.vmid", "9005"),
+					resource.TestCheckResourceAttr("data.proxmox_vms.test", "vms.0.node", "pve"),
+					resource.TestCheckResourceAttr("data.proxmox_vms.test", "vms.0.status", "stopped"),
+				),
+			},
+			{
+				Config:      providerConfig + `data "proxmox_containers" "test" { filter { name_regex = "(" } }`,
+				ExpectError: regexp.MustCompile("Invalid guest filter"),
+			},
+		},
+	})
+}
+
+func TestMatchingGuests(t *testing.T) {
+	resources := []api.ClusterResource{
+		{ID: "qemu/101", Type: "qemu", Node: "pve2", VMID: 101, Name: "web-2", Status: "running", Tags: "debian;web", Pool: "prod"},
+		{ID: "qemu/100", Type: "qemu", Node: "pve", VMID: 100, Name: "web-1", Status: "running", Tags: "web;debian"},
+		{ID: "lxc/102", Type: "lxc", Node: "pve", VMID: 102, Name: "web-3", Status: "running", Tags: "debian;web"},
+		{ID: "qemu/9000", Type: "qemu", Node: "pve", VMID: 9000, Name: "web-template", Status: "stopped", Tags: "debian;web", Template: true},
+		{ID: "storage/pve/local", Type: "storage", Node: "pve"},
+	}
+
+	filter, err := newGuestFilter(GuestFilter{
+		Node:      types.StringNull(),
+		Pool:      types.StringNull(),
+		Status:    types.StringNull(),
+		Template:  types.BoolValue(false),
+		NameRegex: types.StringValue("^web-"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	filter.tags = []string{"web", "debian"}
+
+	guests := matchingGuests(resources, "qemu", []guestFilter{filter})
+	if len(guests) != 2 || guests[0].VMID.ValueInt64() != 100 || guests[1].VMID.ValueInt64() != 101 {
+		t.Fatalf("Expected virtual machines 100 and 101, got %+v", guests)
+	}
+	if !guests[0].Pool.IsNull() || guests[1].Pool.ValueString() != "prod" || len(guests[1].Tags.Elements()) != 2 {
+		t.Errorf("Incorrect guests returned: %+v", guests)
+	}
+
+	filter.Pool = types.StringValue("prod")
+	if guests := matchingGuests(resources, "qemu", []guestFilter{filter}); len(guests) != 1 || guests[0].VMID.ValueInt64() != 101 {
+		t.Errorf("Expected only virtual machine 101 in the pool, got %+v", guests)
+	}
+	if guests := matchingGuests(resources, "lxc", nil); len(guests) != 1 || guests[0].Name.ValueString() != "web-3" {
+		t.Errorf("Expected only the container, got %+v", guests)
+	}
+
+	if _, err := newGuestFilter(GuestFilter{NameRegex: types.StringValue("(")}); err == nil {
+		t.Errorf("Expected an error for an invalid regular expression")
+	}
+}
@@ -0,0 +1,96 @@
+package provider
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Proxmox takes lists as comma separated strings and removes attributes through a "delete"
+// parameter that lists them by name. These helpers keep that translation out of the resources.
+
+// joinList converts a list of strings into the comma separated form Proxmox expects.
+// A null or unknown list is returned as nil so that nothing is sent to the API.
+func joinList(ctx context.Context, list types.List) (*string, diag.Diagnostics) {
+	if list.IsNull() || list.IsUnknown() {
+		return nil, nil
+	}
+
+	var elements []string
+	diags := list.ElementsAs(ctx, &elements, false)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	joined := strings.Join(elements, ",")
+	return &joined, diags
+}
+
+// splitList converts a comma, semicolon or space separated string from Proxmox into a list of strings
+func splitList(value *string) (types.List, diag.Diagnostics) {
+	if value == nil || *value == "" {
+		return types.ListNull(types.StringType), nil
+	}
+
+	var elements []attr.Value
+	for _, element := range strings.FieldsFunc(*value, func(r rune) bool {
+		return r == ',' || r == ';' || r == ' '
+	}) {
+		elements = append(elements, types.StringValue(element))
+	}
+
+	return types.ListValue(types.StringType, elements)
+}
+
+// joinSet converts a set of strings into the comma separated form Proxmox expects. The elements are
+// sorted so that the request does not depend on the order of the set.
+func joinSet(ctx context.Context, set types.Set) (*string, diag.Diagnostics) {
+	if set.IsNull() || set.IsUnknown() {
+		return nil, nil
+	}
+
+	var elements []string
+	diags := set.ElementsAs(ctx, &elements, false)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	sort.Strings(elements)
+	joined := strings.Join(elements, ",")
+	return &joined, diags
+}
+
+// splitSet converts a comma, semicolon or space separated string from Proxmox into a set of strings
+func splitSet(value *string) (types.Set, diag.Diagnostics) {
+	list, diags := splitList(value)
+	if list.IsNull() || diags.HasError() {
+		return types.SetNull(types.StringType), diags
+	}
+
+	set, setDiags := types.SetValue(types.StringType, list.Elements())
+	diags.Append(setDiags...)
+	return set, diags
+}
+
+// removedAttributes collects the API names of attributes that are set in the state but have been
+// removed from the plan. Proxmox keeps the old value unless it is explicitly deleted.
+type removedAttributes []string
+
+func (r *removedAttributes) check(name string, plan attr.Value, state attr.Value) {
+	if plan.IsNull() && !state.IsNull() {
+		*r = append(*r, name)
+	}
+}
+
+// value returns the delete parameter for the request, or nil when nothing was removed
+func (r *removedAttributes) value() *string {
+	if len(*r) == 0 {
+		return nil
+	}
+	joined := strings.Join(*r, ",")
+	return &joined
+}
@@ -0,0 +1,131 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/clincha-org/proxmox-api/pkg/proxmox"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"strconv"
+)
+
+var (
+	_ datasource.DataSource                   = &nextVMIDDataSource{}
+	_ datasource.DataSourceWithConfigure      = &nextVMIDDataSource{}
+	_ datasource.DataSourceWithValidateConfig = &nextVMIDDataSource{}
+)
+
+type NextVMIDDataSourceModel struct {
+	ID   types.String `tfsdk:"id"`
+	Min  types.Int64  `tfsdk:"min"`
+	Max  types.Int64  `tfsdk:"max"`
+	VMID types.Int64  `tfsdk:"vmid"`
+}
+
+// nextVMIDDataSource finds a free VMID. It shares the allocator with the resources, so every data source and
+// resource in the same run is given a different VMID.
+type nextVMIDDataSource struct {
+	client *proxmox.Client
+}
+
+func NewNextVMIDDataSource() datasource.DataSource {
+	return &nextVMIDDataSource{}
+}
+
+func (d *nextVMIDDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_next_vmid"
+}
+
+func (d *nextVMIDDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+			},
+			"min": schema.Int64Attribute{
+				Optional:    true,
+				Description: "The lowest VMID to return",
+			},
+			"max": schema.Int64Attribute{
+				Optional:    true,
+				Description: "The highest VMID to return. An error is returned when every VMID up to it is in use",
+			},
+			"vmid": schema.Int64Attribute{
+				Computed:    true,
+				Description: "The lowest free VMID in the range. It changes on every read, so it is not meant for the vmid of a resource",
+			},
+		},
+	}
+}
+
+func (d *nextVMIDDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	var config NextVMIDDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for _, limit := range []struct {
+		name  string
+		value types.Int64
+	}{{"min", config.Min}, {"max", config.Max}} {
+		if !limit.value.IsNull() && !limit.value.IsUnknown() && (limit.value.ValueInt64() < 100 || limit.value.ValueInt64() > maxVMID) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(limit.name),
+				"Invalid VMID range",
+				fmt.Sprintf("VMIDs are between 100 and %d. Got: %d", maxVMID, limit.value.ValueInt64()),
+			)
+		}
+	}
+
+	if !config.Min.IsNull() && !config.Max.IsNull() && config.Min.ValueInt64() > config.Max.ValueInt64() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("max"),
+			"Invalid VMID range",
+			fmt.Sprintf("max must not be lower than min. Got: min %d, max %d", config.Min.ValueInt64(), config.Max.ValueInt64()),
+		)
+	}
+}
+
+func (d *nextVMIDDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config NextVMIDDataSourceModel
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	vmid, err := allocator.allocate(d.client, config.Min.ValueInt64(), config.Max.ValueInt64())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to find a free Proxmox VMID",
+			err.Error(),
+		)
+		return
+	}
+
+	config.ID = types.StringValue(strconv.FormatInt(vmid, 10))
+	config.VMID = types.Int64Value(vmid)
+
+	diags = resp.State.Set(ctx, config)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (d *nextVMIDDataSource) Configure(_ context.Context, request datasource.ConfigureRequest, response *datasource.ConfigureResponse) {
+	if request.ProviderData == nil {
+		return
+	}
+
+	client, ok := request.ProviderData.(*proxmox.Client)
+	if !ok {
+		response.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *proxmox.Client, got %T. Please report this error to the developer", request.ProviderData),
+		)
+		return
+	}
+
+	d.client = client
+}
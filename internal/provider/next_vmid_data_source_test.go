@@ -0,0 +1,48 @@
+package provider
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"testing"
+)
+
+func TestNextVMIDDataSource_Read(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+data "proxmox_next_vmid" "first" {
+  min = 9100
+  max = 9199
+}
+
+data "proxmox_next_vmid" "second" {
+  min = 9100
+  max = 9199
+}
+
+resource "proxmox_vm" "test" {
+  node = "pve"
+  name = "terraform-next-vmid"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.proxmox_next_vmid.first", "vmid"),
+					resource.TestCheckResourceAttrSet("data.proxmox_next_vmid.second", "vmid"),
+					resource.TestCheckResourceAttrSet("proxmox_vm.test", "vmid"),
+					// Every VMID in the same run is handed out once
+					func(state *terraform.State) error {
+						first := state.RootModule().Resources["data.proxmox_next_vmid.first"].Primary.Attributes["vmid"]
+						second := state.RootModule().Resources["data.proxmox_next_vmid.second"].Primary.Attributes["vmid"]
+						if first == second {
+							return fmt.Errorf("both data sources returned the VMID %s", first)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
@@ -3,7 +3,10 @@ package provider
 import (
 	"context"
 	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"regexp"
+	"strings"
 
 	"github.com/clincha-org/proxmox-api/pkg/proxmox"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
@@ -19,8 +22,38 @@ type nodeDataSource struct {
 	client *proxmox.Client
 }
 
+// NodeModel The ID to Level attributes are only set when a single node is selected with Node
 type NodeModel struct {
-	Nodes []Node `tfsdk:"nodes"`
+	Node           types.String  `tfsdk:"node"`
+	Filters        []NodeFilter  `tfsdk:"filter"`
+	Nodes          []Node        `tfsdk:"nodes"`
+	TotalMemory    types.Int64   `tfsdk:"total_memory"`
+	FreeMemory     types.Int64   `tfsdk:"free_memory"`
+	TotalCPU       types.Int64   `tfsdk:"total_cpu"`
+	FreeCPU        types.Float64 `tfsdk:"free_cpu"`
+	TotalDisk      types.Int64   `tfsdk:"total_disk"`
+	FreeDisk       types.Int64   `tfsdk:"free_disk"`
+	ID             types.String  `tfsdk:"id"`
+	Type           types.String  `tfsdk:"type"`
+	Maxcpu         types.Int64   `tfsdk:"max_cpu"`
+	Cpu            types.Float64 `tfsdk:"cpu"`
+	Status         types.String  `tfsdk:"status"`
+	Maxmem         types.Int64   `tfsdk:"max_memory"`
+	SslFingerprint types.String  `tfsdk:"ssl_fingerprint"`
+	Mem            types.Int64   `tfsdk:"memory"`
+	Disk           types.Int64   `tfsdk:"disk"`
+	Uptime         types.Int64   `tfsdk:"uptime"`
+	Maxdisk        types.Int64   `tfsdk:"max_disk"`
+	Level          types.String  `tfsdk:"level"`
+}
+
+// NodeFilter A node is only returned when it matches every filter block
+type NodeFilter struct {
+	Status        types.String  `tfsdk:"status"`
+	NameRegex     types.String  `tfsdk:"name_regex"`
+	MinFreeMemory types.Int64   `tfsdk:"min_free_memory"`
+	MinFreeDisk   types.Int64   `tfsdk:"min_free_disk"`
+	MinFreeCPU    types.Float64 `tfsdk:"min_free_cpu"`
 }
 
 type Node struct {
@@ -48,60 +81,133 @@ func (d *nodeDataSource) Metadata(_ context.Context, req datasource.MetadataRequ
 }
 
 func (d *nodeDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	nodeObject := nodeAttributes()
+	nodeObject["node"] = schema.StringAttribute{
+		Computed: true,
+	}
+
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
+			"node": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only return the node with this name and set its attributes at the top level. An error is returned if it does not exist",
+			},
+			"total_memory": schema.Int64Attribute{
+				Computed:    true,
+				Description: "The memory of all returned nodes in bytes",
+			},
+			"free_memory": schema.Int64Attribute{
+				Computed:    true,
+				Description: "The unused memory of all returned nodes in bytes",
+			},
+			"total_cpu": schema.Int64Attribute{
+				Computed:    true,
+				Description: "The CPUs of all returned nodes",
+			},
+			"free_cpu": schema.Float64Attribute{
+				Computed:    true,
+				Description: "The idle CPUs of all returned nodes",
+			},
+			"total_disk": schema.Int64Attribute{
+				Computed:    true,
+				Description: "The root disk space of all returned nodes in bytes",
+			},
+			"free_disk": schema.Int64Attribute{
+				Computed:    true,
+				Description: "The unused root disk space of all returned nodes in bytes",
+			},
 			"nodes": schema.ListNestedAttribute{
 				Computed: true,
 				NestedObject: schema.NestedAttributeObject{
+					Attributes: nodeObject,
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"filter": schema.ListNestedBlock{
+				NestedObject: schema.NestedBlockObject{
 					Attributes: map[string]schema.Attribute{
-						"id": schema.StringAttribute{
-							Computed: true,
-						},
-						"type": schema.StringAttribute{
-							Computed: true,
-						},
-						"max_cpu": schema.Int64Attribute{
-							Computed: true,
-						},
-						"cpu": schema.Float64Attribute{
-							Computed: true,
-						},
 						"status": schema.StringAttribute{
-							Computed: true,
-						},
-						"max_memory": schema.Int64Attribute{
-							Computed: true,
-						},
-						"ssl_fingerprint": schema.StringAttribute{
-							Computed: true,
+							Optional:    true,
+							Description: "Only return nodes with this status, for example online",
 						},
-						"memory": schema.Int64Attribute{
-							Computed: true,
+						"name_regex": schema.StringAttribute{
+							Optional:    true,
+							Description: "Only return nodes whose name matches this regular expression",
 						},
-						"node": schema.StringAttribute{
-							Computed: true,
+						"min_free_memory": schema.Int64Attribute{
+							Optional:    true,
+							Description: "Only return nodes with at least this much unused memory in bytes",
 						},
-						"disk": schema.Int64Attribute{
-							Computed: true,
+						"min_free_disk": schema.Int64Attribute{
+							Optional:    true,
+							Description: "Only return nodes with at least this much unused root disk space in bytes",
 						},
-						"uptime": schema.Int64Attribute{
-							Computed: true,
-						},
-						"max_disk": schema.Int64Attribute{
-							Computed: true,
-						},
-						"level": schema.StringAttribute{
-							Computed: true,
+						"min_free_cpu": schema.Float64Attribute{
+							Optional:    true,
+							Description: "Only return nodes with at least this many idle CPUs",
 						},
 					},
 				},
 			},
 		},
 	}
+
+	// The attributes of the node selected with node are also set at the top level, they are null otherwise
+	for name, attribute := range nodeAttributes() {
+		resp.Schema.Attributes[name] = attribute
+	}
+}
+
+// nodeAttributes returns the computed attributes of a node, except for its name
+func nodeAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"id": schema.StringAttribute{
+			Computed: true,
+		},
+		"type": schema.StringAttribute{
+			Computed: true,
+		},
+		"max_cpu": schema.Int64Attribute{
+			Computed: true,
+		},
+		"cpu": schema.Float64Attribute{
+			Computed: true,
+		},
+		"status": schema.StringAttribute{
+			Computed: true,
+		},
+		"max_memory": schema.Int64Attribute{
+			Computed: true,
+		},
+		"ssl_fingerprint": schema.StringAttribute{
+			Computed: true,
+		},
+		"memory": schema.Int64Attribute{
+			Computed: true,
+		},
+		"disk": schema.Int64Attribute{
+			Computed: true,
+		},
+		"uptime": schema.Int64Attribute{
+			Computed: true,
+		},
+		"max_disk": schema.Int64Attribute{
+			Computed: true,
+		},
+		"level": schema.StringAttribute{
+			Computed: true,
+		},
+	}
 }
 
 func (d *nodeDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
 	var state NodeModel
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	nodes, err := d.client.GetNodes()
 	if err != nil {
@@ -112,32 +218,152 @@ func (d *nodeDataSource) Read(ctx context.Context, req datasource.ReadRequest, r
 		return
 	}
 
+	if !state.Node.IsNull() {
+		node, err := findNode(nodes, state.Node.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("node"), "Proxmox node not found", err.Error())
+			return
+		}
+		nodes = []proxmox.Node{node}
+
+		selected := nodeState(node)
+		state.ID = selected.ID
+		state.Type = selected.Type
+		state.Maxcpu = selected.Maxcpu
+		state.Cpu = selected.Cpu
+		state.Status = selected.Status
+		state.Maxmem = selected.Maxmem
+		state.SslFingerprint = selected.SslFingerprint
+		state.Mem = selected.Mem
+		state.Disk = selected.Disk
+		state.Uptime = selected.Uptime
+		state.Maxdisk = selected.Maxdisk
+		state.Level = selected.Level
+	}
+
+	var filters []nodeFilter
+	for i, filter := range state.Filters {
+		compiled, err := newNodeFilter(filter)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("filter").AtListIndex(i).AtName("name_regex"), "Invalid node filter", err.Error())
+			return
+		}
+		filters = append(filters, compiled)
+	}
+
+	var totalMemory, freeMemory, totalCPU, totalDisk, freeDisk int64
+	var freeCPU float64
 	for _, node := range nodes {
-		nodeState := Node{
-			Type:           types.StringValue(node.Type),
-			Maxcpu:         types.Int64Value(int64(node.Maxcpu)),
-			Cpu:            types.Float64Value(node.Cpu),
-			Status:         types.StringValue(node.Status),
-			Maxmem:         types.Int64Value(int64(node.Maxmem)),
-			SslFingerprint: types.StringValue(node.SslFingerprint),
-			Mem:            types.Int64Value(int64(node.Mem)),
-			ID:             types.StringValue(node.Id),
-			Node:           types.StringValue(node.Node),
-			Disk:           types.Int64Value(node.Disk),
-			Uptime:         types.Int64Value(int64(node.Uptime)),
-			Maxdisk:        types.Int64Value(node.Maxdisk),
-			Level:          types.StringValue(node.Level),
+		nodeState := nodeState(node)
+		if !matchesNodeFilters(nodeState, filters) {
+			continue
 		}
 		state.Nodes = append(state.Nodes, nodeState)
+
+		totalMemory += nodeState.Maxmem.ValueInt64()
+		freeMemory += nodeFreeMemory(nodeState)
+		totalCPU += nodeState.Maxcpu.ValueInt64()
+		freeCPU += nodeFreeCPU(nodeState)
+		totalDisk += nodeState.Maxdisk.ValueInt64()
+		freeDisk += nodeFreeDisk(nodeState)
 	}
 
-	diags := resp.State.Set(ctx, state)
+	state.TotalMemory = types.Int64Value(totalMemory)
+	state.FreeMemory = types.Int64Value(freeMemory)
+	state.TotalCPU = types.Int64Value(totalCPU)
+	state.FreeCPU = types.Float64Value(freeCPU)
+	state.TotalDisk = types.Int64Value(totalDisk)
+	state.FreeDisk = types.Int64Value(freeDisk)
+
+	diags = resp.State.Set(ctx, state)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 }
 
+// findNode returns the node with the given name, or an error listing the nodes that do exist
+func findNode(nodes []proxmox.Node, name string) (proxmox.Node, error) {
+	var names []string
+	for _, node := range nodes {
+		if node.Node == name {
+			return node, nil
+		}
+		names = append(names, node.Node)
+	}
+	return proxmox.Node{}, fmt.Errorf("the node %q does not exist in the cluster. Available nodes: %s", name, strings.Join(names, ", "))
+}
+
+// nodeFilter is a NodeFilter with its regular expression compiled
+type nodeFilter struct {
+	NodeFilter
+	nameRegex *regexp.Regexp
+}
+
+func newNodeFilter(filter NodeFilter) (nodeFilter, error) {
+	compiled := nodeFilter{NodeFilter: filter}
+	if !filter.NameRegex.IsNull() {
+		nameRegex, err := regexp.Compile(filter.NameRegex.ValueString())
+		if err != nil {
+			return compiled, fmt.Errorf("the name_regex %q is not a valid regular expression: %w", filter.NameRegex.ValueString(), err)
+		}
+		compiled.nameRegex = nameRegex
+	}
+	return compiled, nil
+}
+
+func matchesNodeFilters(node Node, filters []nodeFilter) bool {
+	for _, filter := range filters {
+		if !filter.Status.IsNull() && node.Status.ValueString() != filter.Status.ValueString() {
+			return false
+		}
+		if filter.nameRegex != nil && !filter.nameRegex.MatchString(node.Node.ValueString()) {
+			return false
+		}
+		if !filter.MinFreeMemory.IsNull() && nodeFreeMemory(node) < filter.MinFreeMemory.ValueInt64() {
+			return false
+		}
+		if !filter.MinFreeDisk.IsNull() && nodeFreeDisk(node) < filter.MinFreeDisk.ValueInt64() {
+			return false
+		}
+		if !filter.MinFreeCPU.IsNull() && nodeFreeCPU(node) < filter.MinFreeCPU.ValueFloat64() {
+			return false
+		}
+	}
+	return true
+}
+
+func nodeFreeMemory(node Node) int64 {
+	return node.Maxmem.ValueInt64() - node.Mem.ValueInt64()
+}
+
+func nodeFreeDisk(node Node) int64 {
+	return node.Maxdisk.ValueInt64() - node.Disk.ValueInt64()
+}
+
+// nodeFreeCPU is the number of idle CPUs. Proxmox reports the CPU usage as a fraction of all CPUs.
+func nodeFreeCPU(node Node) float64 {
+	return float64(node.Maxcpu.ValueInt64()) * (1 - node.Cpu.ValueFloat64())
+}
+
+func nodeState(node proxmox.Node) Node {
+	return Node{
+		Type:           types.StringValue(node.Type),
+		Maxcpu:         types.Int64Value(int64(node.Maxcpu)),
+		Cpu:            types.Float64Value(node.Cpu),
+		Status:         types.StringValue(node.Status),
+		Maxmem:         types.Int64Value(int64(node.Maxmem)),
+		SslFingerprint: types.StringValue(node.SslFingerprint),
+		Mem:            types.Int64Value(int64(node.Mem)),
+		ID:             types.StringValue(node.Id),
+		Node:           types.StringValue(node.Node),
+		Disk:           types.Int64Value(node.Disk),
+		Uptime:         types.Int64Value(int64(node.Uptime)),
+		Maxdisk:        types.Int64Value(node.Maxdisk),
+		Level:          types.StringValue(node.Level),
+	}
+}
+
 func (d *nodeDataSource) Configure(_ context.Context, request datasource.ConfigureRequest, response *datasource.ConfigureResponse) {
 	if request.ProviderData == nil {
 		return
@@ -1,7 +1,11 @@
 package provider
 
 import (
+	"github.com/clincha-org/proxmox-api/pkg/proxmox"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"regexp"
+	"strings"
 	"testing"
 )
 
@@ -18,6 +22,95 @@ func TestNodeDataSource_Read(t *testing.T) {
 					resource.TestCheckResourceAttr("data.proxmox_node.test", "nodes.0.status", "online"),
 				),
 			},
+			{
+				Config: providerConfig + `data "proxmox_node" "test" { node = "pve" }`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.proxmox_node.test", "nodes.#", "1"),
+					resource.TestCheckResourceAttr("data.proxmox_node.test", "nodes.0.node", "pve"),
+					resource.TestCheckResourceAttr("data.proxmox_node.test", "id", "node/pve"),
+					resource.TestCheckResourceAttr("data.proxmox_node.test", "status", "online"),
+					resource.TestCheckResourceAttrSet("data.proxmox_node.test", "max_memory"),
+				),
+			},
+			{
+				Config: providerConfig + `
+data "proxmox_node" "test" {
+  filter {
+    status          = "online"
+    name_regex      = "^pve"
+    min_free_memory = 1073741824
+  }
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.proxmox_node.test", "nodes.0.node", "pve"),
+					resource.TestCheckResourceAttrSet("data.proxmox_node.test", "total_memory"),
+					resource.TestCheckResourceAttrSet("data.proxmox_node.test", "free_memory"),
+					resource.TestCheckResourceAttrSet("data.proxmox_node.test", "free_cpu"),
+				),
+			},
+			{
+				Config:      providerConfig + `data "proxmox_node" "test" { node = "missing" }`,
+				ExpectError: regexp.MustCompile("Proxmox node not found"),
+			},
 		},
 	})
 }
+
+func TestFindNode(t *testing.T) {
+	nodes := []proxmox.Node{{Node: "pve1"}, {Node: "pve2"}}
+
+	node, err := findNode(nodes, "pve2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if node.Node != "pve2" {
+		t.Errorf("Incorrect node returned. Expected pve2, got %v", node.Node)
+	}
+
+	_, err = findNode(nodes, "pve3")
+	if err == nil {
+		t.Fatal("Expected an error for a node that does not exist")
+	}
+	if !strings.Contains(err.Error(), "pve1, pve2") {
+		t.Errorf("Expected the error to list the available nodes, got %v", err)
+	}
+}
+
+func TestMatchesNodeFilters(t *testing.T) {
+	node := nodeState(proxmox.Node{Node: "pve1", Status: "online", Maxmem: 8 << 30, Mem: 2 << 30, Maxcpu: 8, Cpu: 0.5, Maxdisk: 100 << 30, Disk: 40 << 30})
+
+	testCases := []struct {
+		name    string
+		filter  NodeFilter
+		matches bool
+	}{
+		{"status", NodeFilter{Status: types.StringValue("online")}, true},
+		{"wrong status", NodeFilter{Status: types.StringValue("offline")}, false},
+		{"name regex", NodeFilter{NameRegex: types.StringValue("^pve[0-9]$")}, true},
+		{"wrong name regex", NodeFilter{NameRegex: types.StringValue("^backup")}, false},
+		{"enough memory", NodeFilter{MinFreeMemory: types.Int64Value(6 << 30)}, true},
+		{"not enough memory", NodeFilter{MinFreeMemory: types.Int64Value(7 << 30)}, false},
+		{"enough disk", NodeFilter{MinFreeDisk: types.Int64Value(60 << 30)}, true},
+		{"not enough disk", NodeFilter{MinFreeDisk: types.Int64Value(61 << 30)}, false},
+		{"enough cpu", NodeFilter{MinFreeCPU: types.Float64Value(4)}, true},
+		{"not enough cpu", NodeFilter{MinFreeCPU: types.Float64Value(4.5)}, false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			compiled, err := newNodeFilter(testCase.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if matchesNodeFilters(node, []nodeFilter{compiled}) != testCase.matches {
+				t.Errorf("Expected the filter to return %v", testCase.matches)
+			}
+		})
+	}
+
+	_, err := newNodeFilter(NodeFilter{NameRegex: types.StringValue("(")})
+	if err == nil {
+		t.Error("Expected an error for an invalid regular expression")
+	}
+}
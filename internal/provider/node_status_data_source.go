@@ -0,0 +1,239 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"strconv"
+	"strings"
+
+	"github.com/clincha-org/proxmox-api/pkg/proxmox"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"terraform-provider-proxmox/internal/api"
+)
+
+var (
+	_ datasource.DataSource              = &nodeStatusDataSource{}
+	_ datasource.DataSourceWithConfigure = &nodeStatusDataSource{}
+)
+
+type nodeStatusDataSource struct {
+	client *proxmox.Client
+}
+
+type NodeStatusModel struct {
+	Node              types.String  `tfsdk:"node"`
+	Uptime            types.Int64   `tfsdk:"uptime"`
+	KernelVersion     types.String  `tfsdk:"kernel_version"`
+	KernelRelease     types.String  `tfsdk:"kernel_release"`
+	PVEVersion        types.String  `tfsdk:"pve_version"`
+	PVEManagerVersion types.String  `tfsdk:"pve_manager_version"`
+	CPUModel          types.String  `tfsdk:"cpu_model"`
+	CPUSockets        types.Int64   `tfsdk:"cpu_sockets"`
+	CPUCores          types.Int64   `tfsdk:"cpu_cores"`
+	CPUThreads        types.Int64   `tfsdk:"cpu_threads"`
+	CPUUsage          types.Float64 `tfsdk:"cpu_usage"`
+	LoadAverage       types.List    `tfsdk:"load_average"`
+	MemoryTotal       types.Int64   `tfsdk:"memory_total"`
+	MemoryUsed        types.Int64   `tfsdk:"memory_used"`
+	MemoryFree        types.Int64   `tfsdk:"memory_free"`
+	SwapTotal         types.Int64   `tfsdk:"swap_total"`
+	SwapUsed          types.Int64   `tfsdk:"swap_used"`
+	SwapFree          types.Int64   `tfsdk:"swap_free"`
+	RootFSTotal       types.Int64   `tfsdk:"rootfs_total"`
+	RootFSUsed        types.Int64   `tfsdk:"rootfs_used"`
+	RootFSAvailable   types.Int64   `tfsdk:"rootfs_available"`
+	BootMode          types.String  `tfsdk:"boot_mode"`
+	SecureBoot        types.Bool    `tfsdk:"secure_boot"`
+	KSMShared         types.Int64   `tfsdk:"ksm_shared"`
+}
+
+func NewNodeStatusDataSource() datasource.DataSource {
+	return &nodeStatusDataSource{}
+}
+
+func (d *nodeStatusDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_node_status"
+}
+
+func (d *nodeStatusDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"node": schema.StringAttribute{
+				Required: true,
+			},
+			"uptime": schema.Int64Attribute{
+				Computed: true,
+			},
+			"kernel_version": schema.StringAttribute{
+				Computed:    true,
+				Description: "The full kernel version string, for example Linux 6.8.12-1-pve #1 SMP PREEMPT_DYNAMIC",
+			},
+			"kernel_release": schema.StringAttribute{
+				Computed:    true,
+				Description: "The release of the running kernel, for example 6.8.12-1-pve",
+			},
+			"pve_version": schema.StringAttribute{
+				Computed:    true,
+				Description: "The full version string, for example pve-manager/8.2.4/faa83925c9641325",
+			},
+			"pve_manager_version": schema.StringAttribute{
+				Computed:    true,
+				Description: "The pve-manager version, for example 8.2.4",
+			},
+			"cpu_model": schema.StringAttribute{
+				Computed: true,
+			},
+			"cpu_sockets": schema.Int64Attribute{
+				Computed: true,
+			},
+			"cpu_cores": schema.Int64Attribute{
+				Computed:    true,
+				Description: "The number of cores per socket",
+			},
+			"cpu_threads": schema.Int64Attribute{
+				Computed:    true,
+				Description: "The number of logical CPUs",
+			},
+			"cpu_usage": schema.Float64Attribute{
+				Computed: true,
+			},
+			"load_average": schema.ListAttribute{
+				Computed:    true,
+				ElementType: types.Float64Type,
+				Description: "The 1, 5 and 15 minute load averages",
+			},
+			"memory_total": schema.Int64Attribute{
+				Computed: true,
+			},
+			"memory_used": schema.Int64Attribute{
+				Computed: true,
+			},
+			"memory_free": schema.Int64Attribute{
+				Computed: true,
+			},
+			"swap_total": schema.Int64Attribute{
+				Computed: true,
+			},
+			"swap_used": schema.Int64Attribute{
+				Computed: true,
+			},
+			"swap_free": schema.Int64Attribute{
+				Computed: true,
+			},
+			"rootfs_total": schema.Int64Attribute{
+				Computed: true,
+			},
+			"rootfs_used": schema.Int64Attribute{
+				Computed: true,
+			},
+			"rootfs_available": schema.Int64Attribute{
+				Computed: true,
+			},
+			"boot_mode": schema.StringAttribute{
+				Computed:    true,
+				Description: "Either efi or legacy-bios",
+			},
+			"secure_boot": schema.BoolAttribute{
+				Computed: true,
+			},
+			"ksm_shared": schema.Int64Attribute{
+				Computed:    true,
+				Description: "The memory shared by kernel same-page merging in bytes",
+			},
+		},
+	}
+}
+
+func (d *nodeStatusDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state NodeStatusModel
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	status, err := api.GetNodeStatus(d.client, state.Node.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to read Proxmox node status",
+			err.Error(),
+		)
+		return
+	}
+
+	state = NodeStatusModel{
+		Node:              state.Node,
+		Uptime:            types.Int64Value(int64(status.Uptime)),
+		KernelVersion:     types.StringValue(status.KernelVersion),
+		KernelRelease:     types.StringValue(status.CurrentKernel.Release),
+		PVEVersion:        types.StringValue(status.PVEVersion),
+		PVEManagerVersion: types.StringValue(pveManagerVersion(status.PVEVersion)),
+		CPUModel:          types.StringValue(status.CPUInfo.Model),
+		CPUSockets:        types.Int64Value(int64(status.CPUInfo.Sockets)),
+		CPUCores:          types.Int64Value(int64(status.CPUInfo.Cores)),
+		CPUThreads:        types.Int64Value(int64(status.CPUInfo.CPUs)),
+		CPUUsage:          types.Float64Value(status.CPU),
+		MemoryTotal:       types.Int64Value(int64(status.Memory.Total)),
+		MemoryUsed:        types.Int64Value(int64(status.Memory.Used)),
+		MemoryFree:        types.Int64Value(int64(status.Memory.Free)),
+		SwapTotal:         types.Int64Value(int64(status.Swap.Total)),
+		SwapUsed:          types.Int64Value(int64(status.Swap.Used)),
+		SwapFree:          types.Int64Value(int64(status.Swap.Free)),
+		RootFSTotal:       types.Int64Value(int64(status.RootFS.Total)),
+		RootFSUsed:        types.Int64Value(int64(status.RootFS.Used)),
+		RootFSAvailable:   types.Int64Value(int64(status.RootFS.Available)),
+		BootMode:          types.StringValue(status.BootInfo.Mode),
+		SecureBoot:        types.BoolValue(bool(status.BootInfo.SecureBoot)),
+		KSMShared:         types.Int64Value(int64(status.KSM.Shared)),
+	}
+
+	var loadAverages []attr.Value
+	for _, loadAverage := range status.LoadAverage {
+		value, err := strconv.ParseFloat(loadAverage, 64)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to read Proxmox node status",
+				fmt.Sprintf("Could not parse the load average %q: %s", loadAverage, err.Error()),
+			)
+			return
+		}
+		loadAverages = append(loadAverages, types.Float64Value(value))
+	}
+	state.LoadAverage, diags = types.ListValue(types.Float64Type, loadAverages)
+	resp.Diagnostics.Append(diags...)
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// pveManagerVersion extracts 8.2.4 from pve-manager/8.2.4/faa83925c9641325
+func pveManagerVersion(pveVersion string) string {
+	parts := strings.Split(pveVersion, "/")
+	if len(parts) < 2 {
+		return pveVersion
+	}
+	return parts[1]
+}
+
+func (d *nodeStatusDataSource) Configure(_ context.Context, request datasource.ConfigureRequest, response *datasource.ConfigureResponse) {
+	if request.ProviderData == nil {
+		return
+	}
+
+	client, ok := request.ProviderData.(*proxmox.Client)
+	if !ok {
+		response.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *proxmox.Client, got %T. Please report this error to the developer", request.ProviderData),
+		)
+		return
+	}
+
+	d.client = client
+}
@@ -0,0 +1,31 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"regexp"
+	"testing"
+)
+
+func TestNodeStatusDataSource_Read(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `data "proxmox_node_status" "test" { node = "pve" }`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.proxmox_node_status.test", "node", "pve"),
+					resource.TestMatchResourceAttr("data.proxmox_node_status.test", "pve_manager_version", regexp.MustCompile(`^[0-9]+\.[0-9]+`)),
+					resource.TestMatchResourceAttr("data.proxmox_node_status.test", "kernel_release", regexp.MustCompile(`-pve$`)),
+					resource.TestCheckResourceAttr("data.proxmox_node_status.test", "load_average.#", "3"),
+				),
+			},
+		},
+	})
+}
+
+func TestPVEManagerVersion(t *testing.T) {
+	version := pveManagerVersion("pve-manager/8.2.4/faa83925c9641325")
+	if version != "8.2.4" {
+		t.Errorf("Incorrect version returned. Expected 8.2.4, got %v", version)
+	}
+}
@@ -189,11 +189,27 @@ func (p *proxmoxProvider) Configure(ctx context.Context, req provider.ConfigureR
 func (p *proxmoxProvider) DataSources(_ context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
 		NewNodeDataSource,
+		NewClusterNodeDataSource,
+		NewNodeStatusDataSource,
+		NewSDNIPAMStatusDataSource,
+		NewVirtualMachineTemplateDataSource,
+		NewNextVMIDDataSource,
+		NewVirtualMachinesDataSource,
+		NewContainersDataSource,
+		NewClusterResourcesDataSource,
+		NewStorageDataSource,
 	}
 }
 
 func (p *proxmoxProvider) Resources(_ context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewNetworkResource,
+		NewSDNControllerResource,
+		NewSDNIPAMResource,
+		NewSDNDNSResource,
+		NewVirtualMachineResource,
+		NewVirtualMachineSnapshotResource,
+		NewContainerResource,
+		NewStorageResource,
 	}
 }
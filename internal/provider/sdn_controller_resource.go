@@ -0,0 +1,363 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/clincha-org/proxmox-api/pkg/proxmox"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"terraform-provider-proxmox/internal/api"
+)
+
+var (
+	_ resource.Resource                   = &sdnControllerResource{}
+	_ resource.ResourceWithConfigure      = &sdnControllerResource{}
+	_ resource.ResourceWithImportState    = &sdnControllerResource{}
+	_ resource.ResourceWithValidateConfig = &sdnControllerResource{}
+)
+
+type SDNControllerResourceModel struct {
+	ID                      types.String `tfsdk:"id"`
+	Controller              types.String `tfsdk:"controller"`
+	Type                    types.String `tfsdk:"type"`
+	ASN                     types.Int64  `tfsdk:"asn"`
+	Peers                   types.List   `tfsdk:"peers"`
+	EBGP                    types.Bool   `tfsdk:"ebgp"`
+	EBGPMultihop            types.Int64  `tfsdk:"ebgp_multihop"`
+	BGPMultipathAsPathRelax types.Bool   `tfsdk:"bgp_multipath_as_path_relax"`
+	Loopback                types.String `tfsdk:"loopback"`
+	Node                    types.String `tfsdk:"node"`
+	ISISDomain              types.String `tfsdk:"isis_domain"`
+	ISISInterfaces          types.List   `tfsdk:"isis_interfaces"`
+	ISISNet                 types.String `tfsdk:"isis_net"`
+}
+
+type sdnControllerResource struct {
+	client *proxmox.Client
+}
+
+func NewSDNControllerResource() resource.Resource {
+	return &sdnControllerResource{}
+}
+
+func (r *sdnControllerResource) Metadata(_ context.Context, request resource.MetadataRequest, response *resource.MetadataResponse) {
+	response.TypeName = request.ProviderTypeName + "_sdn_controller"
+}
+
+func (r *sdnControllerResource) Configure(_ context.Context, request resource.ConfigureRequest, response *resource.ConfigureResponse) {
+	if request.ProviderData == nil {
+		return
+	}
+
+	client, ok := request.ProviderData.(*proxmox.Client)
+
+	if !ok {
+		response.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *proxmox.Client, got %T. Please report this issue to the developers", request.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+func (r *sdnControllerResource) Schema(_ context.Context, _ resource.SchemaRequest, response *resource.SchemaResponse) {
+	response.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"controller": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"type": schema.StringAttribute{
+				Required:    true,
+				Description: "One of evpn, bgp or isis",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"asn": schema.Int64Attribute{
+				Optional: true,
+			},
+			"peers": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"ebgp": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Only supported by bgp controllers",
+			},
+			"ebgp_multihop": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Only supported by bgp controllers",
+			},
+			"bgp_multipath_as_path_relax": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Only supported by bgp controllers",
+			},
+			"loopback": schema.StringAttribute{
+				Optional: true,
+			},
+			"node": schema.StringAttribute{
+				Optional: true,
+			},
+			"isis_domain": schema.StringAttribute{
+				Optional: true,
+			},
+			"isis_interfaces": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"isis_net": schema.StringAttribute{
+				Optional: true,
+			},
+		},
+	}
+}
+
+func (r *sdnControllerResource) ValidateConfig(ctx context.Context, request resource.ValidateConfigRequest, response *resource.ValidateConfigResponse) {
+	var config SDNControllerResourceModel
+	response.Diagnostics.Append(request.Config.Get(ctx, &config)...)
+	if response.Diagnostics.HasError() || config.Type.IsUnknown() {
+		return
+	}
+
+	// Each controller type needs a different set of attributes, Proxmox only reports this when the SDN is applied
+	required := map[string]map[string]bool{
+		"evpn": {"asn": config.ASN.IsNull(), "peers": config.Peers.IsNull()},
+		"bgp":  {"asn": config.ASN.IsNull(), "peers": config.Peers.IsNull(), "node": config.Node.IsNull()},
+		"isis": {"isis_domain": config.ISISDomain.IsNull(), "isis_interfaces": config.ISISInterfaces.IsNull(), "isis_net": config.ISISNet.IsNull(), "node": config.Node.IsNull()},
+	}
+
+	missing, ok := required[config.Type.ValueString()]
+	if !ok {
+		response.Diagnostics.AddAttributeError(
+			path.Root("type"),
+			"Unsupported SDN controller type",
+			fmt.Sprintf("The controller type must be one of evpn, bgp or isis. Got: %q", config.Type.ValueString()),
+		)
+		return
+	}
+
+	for attribute, isNull := range missing {
+		if isNull {
+			response.Diagnostics.AddAttributeError(
+				path.Root(attribute),
+				"Missing SDN controller attribute",
+				fmt.Sprintf("The %s attribute is required for %s controllers", attribute, config.Type.ValueString()),
+			)
+		}
+	}
+
+	if config.Type.ValueString() == "bgp" {
+		return
+	}
+
+	// Proxmox rejects the BGP options for the other controller types
+	bgpOnly := map[string]bool{
+		"ebgp":                        config.EBGP.IsNull(),
+		"ebgp_multihop":               config.EBGPMultihop.IsNull(),
+		"bgp_multipath_as_path_relax": config.BGPMultipathAsPathRelax.IsNull(),
+	}
+	for attribute, isNull := range bgpOnly {
+		if !isNull {
+			response.Diagnostics.AddAttributeError(
+				path.Root(attribute),
+				"Unsupported SDN controller attribute",
+				fmt.Sprintf("The %s attribute is only supported by bgp controllers", attribute),
+			)
+		}
+	}
+}
+
+func (r *sdnControllerResource) ImportState(ctx context.Context, request resource.ImportStateRequest, response *resource.ImportStateResponse) {
+	response.Diagnostics.Append(response.State.SetAttribute(ctx, path.Root("controller"), request.ID)...)
+}
+
+// sdnControllerRequest builds the API request from the plan. The controller and type are left
+// out because Proxmox only accepts them when the controller is created, and the BGP options are
+// only sent for bgp controllers because Proxmox rejects them for the other types.
+func sdnControllerRequest(ctx context.Context, plan SDNControllerResourceModel) (api.SDNControllerRequest, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	controllerRequest := api.SDNControllerRequest{
+		ASN:        plan.ASN.ValueInt64Pointer(),
+		Loopback:   plan.Loopback.ValueStringPointer(),
+		Node:       plan.Node.ValueStringPointer(),
+		ISISDomain: plan.ISISDomain.ValueStringPointer(),
+		ISISNet:    plan.ISISNet.ValueStringPointer(),
+	}
+
+	if plan.Type.ValueString() == "bgp" {
+		controllerRequest.EBGP = api.NewBool(plan.EBGP.ValueBoolPointer())
+		controllerRequest.EBGPMultihop = plan.EBGPMultihop.ValueInt64Pointer()
+		controllerRequest.BGPMultipathAsPathRelax = api.NewBool(plan.BGPMultipathAsPathRelax.ValueBoolPointer())
+	}
+
+	peers, listDiags := joinList(ctx, plan.Peers)
+	diags.Append(listDiags...)
+	controllerRequest.Peers = peers
+
+	interfaces, listDiags := joinList(ctx, plan.ISISInterfaces)
+	diags.Append(listDiags...)
+	controllerRequest.ISISInterfaces = interfaces
+
+	return controllerRequest, diags
+}
+
+func sdnControllerState(controller api.SDNController) (SDNControllerResourceModel, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	state := SDNControllerResourceModel{
+		ID:                      types.StringValue(controller.Controller),
+		Controller:              types.StringValue(controller.Controller),
+		Type:                    types.StringValue(controller.Type),
+		ASN:                     types.Int64PointerValue(controller.ASN.Pointer()),
+		EBGP:                    types.BoolPointerValue(controller.EBGP.Pointer()),
+		EBGPMultihop:            types.Int64PointerValue(controller.EBGPMultihop.Pointer()),
+		BGPMultipathAsPathRelax: types.BoolPointerValue(controller.BGPMultipathAsPathRelax.Pointer()),
+		Loopback:                types.StringPointerValue(controller.Loopback),
+		Node:                    types.StringPointerValue(controller.Node),
+		ISISDomain:              types.StringPointerValue(controller.ISISDomain),
+		ISISNet:                 types.StringPointerValue(controller.ISISNet),
+	}
+
+	peers, listDiags := splitList(controller.Peers)
+	diags.Append(listDiags...)
+	state.Peers = peers
+
+	interfaces, listDiags := splitList(controller.ISISInterfaces)
+	diags.Append(listDiags...)
+	state.ISISInterfaces = interfaces
+
+	return state, diags
+}
+
+func (r *sdnControllerResource) Create(ctx context.Context, request resource.CreateRequest, response *resource.CreateResponse) {
+	var plan SDNControllerResourceModel
+	diags := request.Plan.Get(ctx, &plan)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	controllerRequest, diags := sdnControllerRequest(ctx, plan)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+	controllerRequest.Controller = plan.Controller.ValueString()
+	controllerRequest.Type = plan.Type.ValueString()
+
+	controller, err := api.CreateSDNController(r.client, &controllerRequest)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error creating Proxmox SDN controller",
+			"Could not create the Proxmox SDN controller: "+plan.Controller.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+
+	state, diags := sdnControllerState(controller)
+	response.Diagnostics.Append(diags...)
+
+	diags = response.State.Set(ctx, state)
+	response.Diagnostics.Append(diags...)
+}
+
+func (r *sdnControllerResource) Read(ctx context.Context, request resource.ReadRequest, response *resource.ReadResponse) {
+	var state SDNControllerResourceModel
+	diags := request.State.Get(ctx, &state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	controller, err := api.GetSDNController(r.client, state.Controller.ValueString())
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error reading Proxmox SDN controller",
+			"Could not read the Proxmox SDN controller: "+state.Controller.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+
+	state, diags = sdnControllerState(controller)
+	response.Diagnostics.Append(diags...)
+
+	diags = response.State.Set(ctx, &state)
+	response.Diagnostics.Append(diags...)
+}
+
+func (r *sdnControllerResource) Update(ctx context.Context, request resource.UpdateRequest, response *resource.UpdateResponse) {
+	var plan, state SDNControllerResourceModel
+	response.Diagnostics.Append(request.Plan.Get(ctx, &plan)...)
+	response.Diagnostics.Append(request.State.Get(ctx, &state)...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	controllerRequest, diags := sdnControllerRequest(ctx, plan)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	var removed removedAttributes
+	removed.check("asn", plan.ASN, state.ASN)
+	removed.check("peers", plan.Peers, state.Peers)
+	removed.check("ebgp", plan.EBGP, state.EBGP)
+	removed.check("ebgp-multihop", plan.EBGPMultihop, state.EBGPMultihop)
+	removed.check("bgp-multipath-as-path-relax", plan.BGPMultipathAsPathRelax, state.BGPMultipathAsPathRelax)
+	removed.check("loopback", plan.Loopback, state.Loopback)
+	removed.check("node", plan.Node, state.Node)
+	removed.check("isis-domain", plan.ISISDomain, state.ISISDomain)
+	removed.check("isis-ifaces", plan.ISISInterfaces, state.ISISInterfaces)
+	removed.check("isis-net", plan.ISISNet, state.ISISNet)
+	controllerRequest.Delete = removed.value()
+
+	controller, err := api.UpdateSDNController(r.client, plan.Controller.ValueString(), &controllerRequest)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error updating Proxmox SDN controller",
+			"Could not update the Proxmox SDN controller: "+plan.Controller.ValueString()+". Got this error: "+err.Error(),
+		)
+		return
+	}
+
+	plan, diags = sdnControllerState(controller)
+	response.Diagnostics.Append(diags...)
+
+	diags = response.State.Set(ctx, plan)
+	response.Diagnostics.Append(diags...)
+}
+
+func (r *sdnControllerResource) Delete(ctx context.Context, request resource.DeleteRequest, response *resource.DeleteResponse) {
+	var state SDNControllerResourceModel
+	diags := request.State.Get(ctx, &state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+	err := api.DeleteSDNController(r.client, state.Controller.ValueString())
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error deleting Proxmox SDN controller",
+			"Could not delete the Proxmox SDN controller: "+state.Controller.ValueString()+". Got this error: "+err.Error(),
+		)
+		return
+	}
+}
@@ -0,0 +1,80 @@
+package provider
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"testing"
+)
+
+func TestSDNControllerResource_Create(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+resource "proxmox_sdn_controller" "evpn" {
+  controller = "evpn1"
+  type       = "evpn"
+  asn        = 65000
+  peers      = ["10.0.0.1", "10.0.0.2"]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("proxmox_sdn_controller.evpn", "controller", "evpn1"),
+					resource.TestCheckResourceAttr("proxmox_sdn_controller.evpn", "type", "evpn"),
+					resource.TestCheckResourceAttr("proxmox_sdn_controller.evpn", "asn", "65000"),
+					resource.TestCheckResourceAttr("proxmox_sdn_controller.evpn", "peers.#", "2"),
+				),
+			},
+			{
+				ResourceName:      "proxmox_sdn_controller.evpn",
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateId:     "evpn1",
+			},
+			{
+				Config: providerConfig + `
+resource "proxmox_sdn_controller" "evpn" {
+  controller = "evpn1"
+  type       = "evpn"
+  asn        = 65001
+  peers      = ["10.0.0.1"]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("proxmox_sdn_controller.evpn", "asn", "65001"),
+					resource.TestCheckResourceAttr("proxmox_sdn_controller.evpn", "peers.0", "10.0.0.1"),
+				),
+			},
+		},
+	})
+}
+
+func TestSDNControllerRequest(t *testing.T) {
+	plan := SDNControllerResourceModel{
+		Type:                    types.StringValue("evpn"),
+		ASN:                     types.Int64Value(65000),
+		Peers:                   types.ListNull(types.StringType),
+		EBGP:                    types.BoolValue(false),
+		BGPMultipathAsPathRelax: types.BoolValue(false),
+		ISISInterfaces:          types.ListNull(types.StringType),
+	}
+
+	controllerRequest, diags := sdnControllerRequest(context.Background(), plan)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	if controllerRequest.EBGP != nil || controllerRequest.BGPMultipathAsPathRelax != nil {
+		t.Errorf("Expected no BGP options for an evpn controller, got %+v", controllerRequest)
+	}
+
+	plan.Type = types.StringValue("bgp")
+	controllerRequest, diags = sdnControllerRequest(context.Background(), plan)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	if controllerRequest.EBGP == nil || controllerRequest.BGPMultipathAsPathRelax == nil {
+		t.Errorf("Expected the BGP options for a bgp controller, got %+v", controllerRequest)
+	}
+}
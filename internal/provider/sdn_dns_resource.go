@@ -0,0 +1,241 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/clincha-org/proxmox-api/pkg/proxmox"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"terraform-provider-proxmox/internal/api"
+)
+
+var (
+	_ resource.Resource                   = &sdnDNSResource{}
+	_ resource.ResourceWithConfigure      = &sdnDNSResource{}
+	_ resource.ResourceWithImportState    = &sdnDNSResource{}
+	_ resource.ResourceWithValidateConfig = &sdnDNSResource{}
+)
+
+type SDNDNSResourceModel struct {
+	ID            types.String `tfsdk:"id"`
+	DNS           types.String `tfsdk:"dns"`
+	Type          types.String `tfsdk:"type"`
+	URL           types.String `tfsdk:"url"`
+	Key           types.String `tfsdk:"key"`
+	ReverseMaskV6 types.Int64  `tfsdk:"reverse_mask_v6"`
+	TTL           types.Int64  `tfsdk:"ttl"`
+}
+
+type sdnDNSResource struct {
+	client *proxmox.Client
+}
+
+func NewSDNDNSResource() resource.Resource {
+	return &sdnDNSResource{}
+}
+
+func (r *sdnDNSResource) Metadata(_ context.Context, request resource.MetadataRequest, response *resource.MetadataResponse) {
+	response.TypeName = request.ProviderTypeName + "_sdn_dns"
+}
+
+func (r *sdnDNSResource) Configure(_ context.Context, request resource.ConfigureRequest, response *resource.ConfigureResponse) {
+	if request.ProviderData == nil {
+		return
+	}
+
+	client, ok := request.ProviderData.(*proxmox.Client)
+
+	if !ok {
+		response.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *proxmox.Client, got %T. Please report this issue to the developers", request.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+func (r *sdnDNSResource) Schema(_ context.Context, _ resource.SchemaRequest, response *resource.SchemaResponse) {
+	response.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"dns": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"type": schema.StringAttribute{
+				Required:    true,
+				Description: "Only powerdns is supported by Proxmox",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"url": schema.StringAttribute{
+				Required: true,
+			},
+			"key": schema.StringAttribute{
+				Required:  true,
+				Sensitive: true,
+			},
+			"reverse_mask_v6": schema.Int64Attribute{
+				Optional: true,
+			},
+			"ttl": schema.Int64Attribute{
+				Optional: true,
+			},
+		},
+	}
+}
+
+func (r *sdnDNSResource) ValidateConfig(ctx context.Context, request resource.ValidateConfigRequest, response *resource.ValidateConfigResponse) {
+	var config SDNDNSResourceModel
+	response.Diagnostics.Append(request.Config.Get(ctx, &config)...)
+	if response.Diagnostics.HasError() || config.Type.IsUnknown() || config.Type.IsNull() {
+		return
+	}
+
+	if config.Type.ValueString() != "powerdns" {
+		response.Diagnostics.AddAttributeError(
+			path.Root("type"),
+			"Unsupported SDN DNS type",
+			fmt.Sprintf("The DNS type must be powerdns. Got: %q", config.Type.ValueString()),
+		)
+	}
+}
+
+func (r *sdnDNSResource) ImportState(ctx context.Context, request resource.ImportStateRequest, response *resource.ImportStateResponse) {
+	response.Diagnostics.Append(response.State.SetAttribute(ctx, path.Root("dns"), request.ID)...)
+}
+
+// sdnDNSState converts the API response into state. Proxmox may not return the key, in which
+// case the key that Terraform already knows about is kept.
+func sdnDNSState(dns api.SDNDNS, key types.String) SDNDNSResourceModel {
+	state := SDNDNSResourceModel{
+		ID:            types.StringValue(dns.DNS),
+		DNS:           types.StringValue(dns.DNS),
+		Type:          types.StringValue(dns.Type),
+		URL:           types.StringPointerValue(dns.URL),
+		Key:           types.StringPointerValue(dns.Key),
+		ReverseMaskV6: types.Int64PointerValue(dns.ReverseMaskV6.Pointer()),
+		TTL:           types.Int64PointerValue(dns.TTL.Pointer()),
+	}
+	if dns.Key == nil {
+		state.Key = key
+	}
+	return state
+}
+
+func (r *sdnDNSResource) Create(ctx context.Context, request resource.CreateRequest, response *resource.CreateResponse) {
+	var plan SDNDNSResourceModel
+	diags := request.Plan.Get(ctx, &plan)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	dnsRequest := api.SDNDNSRequest{
+		DNS:           plan.DNS.ValueString(),
+		Type:          plan.Type.ValueString(),
+		URL:           plan.URL.ValueStringPointer(),
+		Key:           plan.Key.ValueStringPointer(),
+		ReverseMaskV6: plan.ReverseMaskV6.ValueInt64Pointer(),
+		TTL:           plan.TTL.ValueInt64Pointer(),
+	}
+
+	dns, err := api.CreateSDNDNS(r.client, &dnsRequest)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error creating Proxmox SDN DNS",
+			"Could not create the Proxmox SDN DNS: "+plan.DNS.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+
+	diags = response.State.Set(ctx, sdnDNSState(dns, plan.Key))
+	response.Diagnostics.Append(diags...)
+}
+
+func (r *sdnDNSResource) Read(ctx context.Context, request resource.ReadRequest, response *resource.ReadResponse) {
+	var state SDNDNSResourceModel
+	diags := request.State.Get(ctx, &state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	dns, err := api.GetSDNDNS(r.client, state.DNS.ValueString())
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error reading Proxmox SDN DNS",
+			"Could not read the Proxmox SDN DNS: "+state.DNS.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+
+	state = sdnDNSState(dns, state.Key)
+
+	diags = response.State.Set(ctx, &state)
+	response.Diagnostics.Append(diags...)
+}
+
+func (r *sdnDNSResource) Update(ctx context.Context, request resource.UpdateRequest, response *resource.UpdateResponse) {
+	var plan, state SDNDNSResourceModel
+	response.Diagnostics.Append(request.Plan.Get(ctx, &plan)...)
+	response.Diagnostics.Append(request.State.Get(ctx, &state)...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	dnsRequest := api.SDNDNSRequest{
+		URL:           plan.URL.ValueStringPointer(),
+		Key:           plan.Key.ValueStringPointer(),
+		ReverseMaskV6: plan.ReverseMaskV6.ValueInt64Pointer(),
+		TTL:           plan.TTL.ValueInt64Pointer(),
+	}
+
+	var removed removedAttributes
+	removed.check("reversemaskv6", plan.ReverseMaskV6, state.ReverseMaskV6)
+	removed.check("ttl", plan.TTL, state.TTL)
+	dnsRequest.Delete = removed.value()
+
+	dns, err := api.UpdateSDNDNS(r.client, plan.DNS.ValueString(), &dnsRequest)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error updating Proxmox SDN DNS",
+			"Could not update the Proxmox SDN DNS: "+plan.DNS.ValueString()+". Got this error: "+err.Error(),
+		)
+		return
+	}
+
+	diags := response.State.Set(ctx, sdnDNSState(dns, plan.Key))
+	response.Diagnostics.Append(diags...)
+}
+
+func (r *sdnDNSResource) Delete(ctx context.Context, request resource.DeleteRequest, response *resource.DeleteResponse) {
+	var state SDNDNSResourceModel
+	diags := request.State.Get(ctx, &state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+	err := api.DeleteSDNDNS(r.client, state.DNS.ValueString())
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error deleting Proxmox SDN DNS",
+			"Could not delete the Proxmox SDN DNS: "+state.DNS.ValueString()+". Got this error: "+err.Error(),
+		)
+		return
+	}
+}
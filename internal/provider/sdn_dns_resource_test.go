@@ -0,0 +1,37 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"testing"
+)
+
+func TestSDNDNSResource_Create(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+resource "proxmox_sdn_dns" "powerdns" {
+  dns  = "powerdns"
+  type = "powerdns"
+  url  = "http://powerdns.example.com:8081/api/v1/servers/localhost"
+  key  = "0123456789abcdef"
+  ttl  = 3600
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("proxmox_sdn_dns.powerdns", "dns", "powerdns"),
+					resource.TestCheckResourceAttr("proxmox_sdn_dns.powerdns", "type", "powerdns"),
+					resource.TestCheckResourceAttr("proxmox_sdn_dns.powerdns", "ttl", "3600"),
+				),
+			},
+			{
+				ResourceName:            "proxmox_sdn_dns.powerdns",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateId:           "powerdns",
+				ImportStateVerifyIgnore: []string{"key"},
+			},
+		},
+	})
+}
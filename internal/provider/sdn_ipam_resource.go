@@ -0,0 +1,254 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/clincha-org/proxmox-api/pkg/proxmox"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"terraform-provider-proxmox/internal/api"
+)
+
+var (
+	_ resource.Resource                   = &sdnIPAMResource{}
+	_ resource.ResourceWithConfigure      = &sdnIPAMResource{}
+	_ resource.ResourceWithImportState    = &sdnIPAMResource{}
+	_ resource.ResourceWithValidateConfig = &sdnIPAMResource{}
+)
+
+type SDNIPAMResourceModel struct {
+	ID      types.String `tfsdk:"id"`
+	IPAM    types.String `tfsdk:"ipam"`
+	Type    types.String `tfsdk:"type"`
+	URL     types.String `tfsdk:"url"`
+	Token   types.String `tfsdk:"token"`
+	Section types.Int64  `tfsdk:"section"`
+}
+
+type sdnIPAMResource struct {
+	client *proxmox.Client
+}
+
+func NewSDNIPAMResource() resource.Resource {
+	return &sdnIPAMResource{}
+}
+
+func (r *sdnIPAMResource) Metadata(_ context.Context, request resource.MetadataRequest, response *resource.MetadataResponse) {
+	response.TypeName = request.ProviderTypeName + "_sdn_ipam"
+}
+
+func (r *sdnIPAMResource) Configure(_ context.Context, request resource.ConfigureRequest, response *resource.ConfigureResponse) {
+	if request.ProviderData == nil {
+		return
+	}
+
+	client, ok := request.ProviderData.(*proxmox.Client)
+
+	if !ok {
+		response.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *proxmox.Client, got %T. Please report this issue to the developers", request.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+func (r *sdnIPAMResource) Schema(_ context.Context, _ resource.SchemaRequest, response *resource.SchemaResponse) {
+	response.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"ipam": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"type": schema.StringAttribute{
+				Required:    true,
+				Description: "One of pve, netbox or phpipam",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"url": schema.StringAttribute{
+				Optional: true,
+			},
+			"token": schema.StringAttribute{
+				Optional:  true,
+				Sensitive: true,
+			},
+			"section": schema.Int64Attribute{
+				Optional:    true,
+				Description: "The phpIPAM section ID",
+			},
+		},
+	}
+}
+
+func (r *sdnIPAMResource) ValidateConfig(ctx context.Context, request resource.ValidateConfigRequest, response *resource.ValidateConfigResponse) {
+	var config SDNIPAMResourceModel
+	response.Diagnostics.Append(request.Config.Get(ctx, &config)...)
+	if response.Diagnostics.HasError() || config.Type.IsUnknown() {
+		return
+	}
+
+	switch config.Type.ValueString() {
+	case "pve":
+		if !config.URL.IsNull() || !config.Token.IsNull() || !config.Section.IsNull() {
+			response.Diagnostics.AddError(
+				"Unexpected SDN IPAM attribute",
+				"The built-in pve IPAM does not take a url, token or section",
+			)
+		}
+	case "netbox", "phpipam":
+		if config.URL.IsNull() {
+			response.Diagnostics.AddAttributeError(path.Root("url"), "Missing SDN IPAM attribute", "The url attribute is required for "+config.Type.ValueString()+" IPAMs")
+		}
+		if config.Token.IsNull() {
+			response.Diagnostics.AddAttributeError(path.Root("token"), "Missing SDN IPAM attribute", "The token attribute is required for "+config.Type.ValueString()+" IPAMs")
+		}
+		if config.Type.ValueString() == "phpipam" && config.Section.IsNull() {
+			response.Diagnostics.AddAttributeError(path.Root("section"), "Missing SDN IPAM attribute", "The section attribute is required for phpipam IPAMs")
+		}
+	default:
+		response.Diagnostics.AddAttributeError(
+			path.Root("type"),
+			"Unsupported SDN IPAM type",
+			fmt.Sprintf("The IPAM type must be one of pve, netbox or phpipam. Got: %q", config.Type.ValueString()),
+		)
+	}
+}
+
+func (r *sdnIPAMResource) ImportState(ctx context.Context, request resource.ImportStateRequest, response *resource.ImportStateResponse) {
+	response.Diagnostics.Append(response.State.SetAttribute(ctx, path.Root("ipam"), request.ID)...)
+}
+
+// sdnIPAMState converts the API response into state. Proxmox may not return the token, in which
+// case the token that Terraform already knows about is kept.
+func sdnIPAMState(ipam api.SDNIPAM, token types.String) SDNIPAMResourceModel {
+	state := SDNIPAMResourceModel{
+		ID:      types.StringValue(ipam.IPAM),
+		IPAM:    types.StringValue(ipam.IPAM),
+		Type:    types.StringValue(ipam.Type),
+		URL:     types.StringPointerValue(ipam.URL),
+		Token:   types.StringPointerValue(ipam.Token),
+		Section: types.Int64PointerValue(ipam.Section.Pointer()),
+	}
+	if ipam.Token == nil {
+		state.Token = token
+	}
+	return state
+}
+
+func (r *sdnIPAMResource) Create(ctx context.Context, request resource.CreateRequest, response *resource.CreateResponse) {
+	var plan SDNIPAMResourceModel
+	diags := request.Plan.Get(ctx, &plan)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	ipamRequest := api.SDNIPAMRequest{
+		IPAM:    plan.IPAM.ValueString(),
+		Type:    plan.Type.ValueString(),
+		URL:     plan.URL.ValueStringPointer(),
+		Token:   plan.Token.ValueStringPointer(),
+		Section: plan.Section.ValueInt64Pointer(),
+	}
+
+	ipam, err := api.CreateSDNIPAM(r.client, &ipamRequest)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error creating Proxmox SDN IPAM",
+			"Could not create the Proxmox SDN IPAM: "+plan.IPAM.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+
+	diags = response.State.Set(ctx, sdnIPAMState(ipam, plan.Token))
+	response.Diagnostics.Append(diags...)
+}
+
+func (r *sdnIPAMResource) Read(ctx context.Context, request resource.ReadRequest, response *resource.ReadResponse) {
+	var state SDNIPAMResourceModel
+	diags := request.State.Get(ctx, &state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	ipam, err := api.GetSDNIPAM(r.client, state.IPAM.ValueString())
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error reading Proxmox SDN IPAM",
+			"Could not read the Proxmox SDN IPAM: "+state.IPAM.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+
+	state = sdnIPAMState(ipam, state.Token)
+
+	diags = response.State.Set(ctx, &state)
+	response.Diagnostics.Append(diags...)
+}
+
+func (r *sdnIPAMResource) Update(ctx context.Context, request resource.UpdateRequest, response *resource.UpdateResponse) {
+	var plan, state SDNIPAMResourceModel
+	response.Diagnostics.Append(request.Plan.Get(ctx, &plan)...)
+	response.Diagnostics.Append(request.State.Get(ctx, &state)...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	ipamRequest := api.SDNIPAMRequest{
+		URL:     plan.URL.ValueStringPointer(),
+		Token:   plan.Token.ValueStringPointer(),
+		Section: plan.Section.ValueInt64Pointer(),
+	}
+
+	var removed removedAttributes
+	removed.check("url", plan.URL, state.URL)
+	removed.check("token", plan.Token, state.Token)
+	removed.check("section", plan.Section, state.Section)
+	ipamRequest.Delete = removed.value()
+
+	ipam, err := api.UpdateSDNIPAM(r.client, plan.IPAM.ValueString(), &ipamRequest)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error updating Proxmox SDN IPAM",
+			"Could not update the Proxmox SDN IPAM: "+plan.IPAM.ValueString()+". Got this error: "+err.Error(),
+		)
+		return
+	}
+
+	diags := response.State.Set(ctx, sdnIPAMState(ipam, plan.Token))
+	response.Diagnostics.Append(diags...)
+}
+
+func (r *sdnIPAMResource) Delete(ctx context.Context, request resource.DeleteRequest, response *resource.DeleteResponse) {
+	var state SDNIPAMResourceModel
+	diags := request.State.Get(ctx, &state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+	err := api.DeleteSDNIPAM(r.client, state.IPAM.ValueString())
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error deleting Proxmox SDN IPAM",
+			"Could not delete the Proxmox SDN IPAM: "+state.IPAM.ValueString()+". Got this error: "+err.Error(),
+		)
+		return
+	}
+}
@@ -0,0 +1,36 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"testing"
+)
+
+func TestSDNIPAMResource_Create(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+resource "proxmox_sdn_ipam" "netbox" {
+  ipam  = "netbox"
+  type  = "netbox"
+  url   = "https://netbox.example.com/api"
+  token = "0123456789abcdef"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("proxmox_sdn_ipam.netbox", "ipam", "netbox"),
+					resource.TestCheckResourceAttr("proxmox_sdn_ipam.netbox", "type", "netbox"),
+					resource.TestCheckResourceAttr("proxmox_sdn_ipam.netbox", "url", "https://netbox.example.com/api"),
+				),
+			},
+			{
+				ResourceName:            "proxmox_sdn_ipam.netbox",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateId:           "netbox",
+				ImportStateVerifyIgnore: []string{"token"},
+			},
+		},
+	})
+}
@@ -0,0 +1,166 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/clincha-org/proxmox-api/pkg/proxmox"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"terraform-provider-proxmox/internal/api"
+)
+
+var (
+	_ datasource.DataSource              = &sdnIPAMStatusDataSource{}
+	_ datasource.DataSourceWithConfigure = &sdnIPAMStatusDataSource{}
+)
+
+type sdnIPAMStatusDataSource struct {
+	client *proxmox.Client
+}
+
+type SDNIPAMStatusModel struct {
+	IPAM        types.String        `tfsdk:"ipam"`
+	VNet        types.String        `tfsdk:"vnet"`
+	VMID        types.Int64         `tfsdk:"vmid"`
+	Allocations []SDNIPAMAllocation `tfsdk:"allocations"`
+}
+
+type SDNIPAMAllocation struct {
+	VNet     types.String `tfsdk:"vnet"`
+	Zone     types.String `tfsdk:"zone"`
+	Subnet   types.String `tfsdk:"subnet"`
+	IP       types.String `tfsdk:"ip"`
+	MAC      types.String `tfsdk:"mac"`
+	VMID     types.Int64  `tfsdk:"vmid"`
+	Hostname types.String `tfsdk:"hostname"`
+	Gateway  types.Bool   `tfsdk:"gateway"`
+}
+
+func NewSDNIPAMStatusDataSource() datasource.DataSource {
+	return &sdnIPAMStatusDataSource{}
+}
+
+func (d *sdnIPAMStatusDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_sdn_ipam_status"
+}
+
+func (d *sdnIPAMStatusDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"ipam": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "The IPAM to read the allocations from. Defaults to the built-in pve IPAM",
+			},
+			"vnet": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only return allocations in this vnet",
+			},
+			"vmid": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Only return allocations for this guest",
+			},
+			"allocations": schema.ListNestedAttribute{
+				Computed: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"vnet": schema.StringAttribute{
+							Computed: true,
+						},
+						"zone": schema.StringAttribute{
+							Computed: true,
+						},
+						"subnet": schema.StringAttribute{
+							Computed: true,
+						},
+						"ip": schema.StringAttribute{
+							Computed: true,
+						},
+						"mac": schema.StringAttribute{
+							Computed: true,
+						},
+						"vmid": schema.Int64Attribute{
+							Computed: true,
+						},
+						"hostname": schema.StringAttribute{
+							Computed: true,
+						},
+						"gateway": schema.BoolAttribute{
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *sdnIPAMStatusDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state SDNIPAMStatusModel
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if state.IPAM.IsNull() {
+		state.IPAM = types.StringValue("pve")
+	}
+
+	allocations, err := api.GetSDNIPAMStatus(d.client, state.IPAM.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to read Proxmox SDN IPAM status",
+			err.Error(),
+		)
+		return
+	}
+
+	state.Allocations = []SDNIPAMAllocation{}
+	for _, allocation := range allocations {
+		if !state.VNet.IsNull() && allocation.VNet != state.VNet.ValueString() {
+			continue
+		}
+		vmid := allocation.VMID.Pointer()
+		if !state.VMID.IsNull() && (vmid == nil || *vmid != state.VMID.ValueInt64()) {
+			continue
+		}
+
+		allocationState := SDNIPAMAllocation{
+			VNet:     types.StringValue(allocation.VNet),
+			Zone:     types.StringValue(allocation.Zone),
+			Subnet:   types.StringValue(allocation.Subnet),
+			IP:       types.StringValue(allocation.IP),
+			MAC:      types.StringPointerValue(allocation.MAC),
+			VMID:     types.Int64PointerValue(vmid),
+			Hostname: types.StringPointerValue(allocation.Hostname),
+			Gateway:  types.BoolValue(allocation.Gateway != nil && bool(*allocation.Gateway)),
+		}
+		state.Allocations = append(state.Allocations, allocationState)
+	}
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (d *sdnIPAMStatusDataSource) Configure(_ context.Context, request datasource.ConfigureRequest, response *datasource.ConfigureResponse) {
+	if request.ProviderData == nil {
+		return
+	}
+
+	client, ok := request.ProviderData.(*proxmox.Client)
+	if !ok {
+		response.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *proxmox.Client, got %T. Please report this error to the developer", request.ProviderData),
+		)
+		return
+	}
+
+	d.client = client
+}
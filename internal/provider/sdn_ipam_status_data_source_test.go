@@ -0,0 +1,21 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"testing"
+)
+
+func TestSDNIPAMStatusDataSource_Read(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `data "proxmox_sdn_ipam_status" "test" {}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.proxmox_sdn_ipam_status.test", "ipam", "pve"),
+					resource.TestCheckResourceAttrSet("data.proxmox_sdn_ipam_status.test", "allocations.#"),
+				),
+			},
+		},
+	})
+}
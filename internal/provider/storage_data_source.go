@@ -0,0 +1,203 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/clincha-org/proxmox-api/pkg/proxmox"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"sort"
+	"terraform-provider-proxmox/internal/api"
+)
+
+var (
+	_ datasource.DataSource              = &storageDataSource{}
+	_ datasource.DataSourceWithConfigure = &storageDataSource{}
+)
+
+type storageDataSource struct {
+	client *proxmox.Client
+}
+
+type StorageDataSourceModel struct {
+	Node     types.String         `tfsdk:"node"`
+	Content  types.String         `tfsdk:"content"`
+	Enabled  types.Bool           `tfsdk:"enabled"`
+	Storages []StorageStatusModel `tfsdk:"storages"`
+}
+
+// StorageStatusModel The status of a storage on the node. The sizes are in bytes and are zero when the storage is not
+// active, for example when an NFS server can not be reached.
+type StorageStatusModel struct {
+	Storage   types.String `tfsdk:"storage"`
+	Type      types.String `tfsdk:"type"`
+	Content   types.Set    `tfsdk:"content"`
+	Active    types.Bool   `tfsdk:"active"`
+	Enabled   types.Bool   `tfsdk:"enabled"`
+	Shared    types.Bool   `tfsdk:"shared"`
+	Total     types.Int64  `tfsdk:"total"`
+	Used      types.Int64  `tfsdk:"used"`
+	Available types.Int64  `tfsdk:"available"`
+}
+
+func NewStorageDataSource() datasource.DataSource {
+	return &storageDataSource{}
+}
+
+func (d *storageDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_storage"
+}
+
+func (d *storageDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"node": schema.StringAttribute{
+				Required:    true,
+				Description: "The node to read the storage status from",
+			},
+			"content": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only return storage that can hold this content type, for example images or rootdir",
+			},
+			"enabled": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Only return enabled storage when true, or only disabled storage when false",
+			},
+			"storages": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "The storage of the node, ordered by available space with the most free space first",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"storage": schema.StringAttribute{
+							Computed: true,
+						},
+						"type": schema.StringAttribute{
+							Computed:    true,
+							Description: "The type of the storage, for example dir, lvmthin or nfs",
+						},
+						"content": schema.SetAttribute{
+							Computed:    true,
+							ElementType: types.StringType,
+						},
+						"active": schema.BoolAttribute{
+							Computed:    true,
+							Description: "Whether the node can currently use the storage",
+						},
+						"enabled": schema.BoolAttribute{
+							Computed: true,
+						},
+						"shared": schema.BoolAttribute{
+							Computed:    true,
+							Description: "Whether every node uses the same storage",
+						},
+						"total": schema.Int64Attribute{
+							Computed:    true,
+							Description: "The size of the storage in bytes",
+						},
+						"used": schema.Int64Attribute{
+							Computed:    true,
+							Description: "The used space in bytes",
+						},
+						"available": schema.Int64Attribute{
+							Computed:    true,
+							Description: "The free space in bytes",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *storageDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state StorageDataSourceModel
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	storages, err := api.GetNodeStorages(d.client, state.Node.ValueString(), state.Content.ValueString(), state.Enabled.ValueBool())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to read Proxmox storage status",
+			err.Error(),
+		)
+		return
+	}
+
+	state.Storages, diags = storageStatuses(filterStorageEnabled(storages, state.Enabled))
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// filterStorageEnabled only keeps the storage that is enabled or disabled as requested. Proxmox treats enabled=0 as
+// no filter, so disabled storage is filtered here.
+func filterStorageEnabled(storages []api.NodeStorage, enabled types.Bool) []api.NodeStorage {
+	if enabled.IsNull() {
+		return storages
+	}
+
+	var filtered []api.NodeStorage
+	for _, storage := range storages {
+		if bool(storage.Enabled) == enabled.ValueBool() {
+			filtered = append(filtered, storage)
+		}
+	}
+	return filtered
+}
+
+// storageStatuses converts the storage into the model, ordered by available space and then by name
+func storageStatuses(storages []api.NodeStorage) ([]StorageStatusModel, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	sort.Slice(storages, func(i, j int) bool {
+		if storages[i].Available != storages[j].Available {
+			return storages[i].Available > storages[j].Available
+		}
+		return storages[i].Storage < storages[j].Storage
+	})
+
+	statuses := []StorageStatusModel{}
+	for _, storage := range storages {
+		content, contentDiags := splitSet(&storage.Content)
+		diags.Append(contentDiags...)
+
+		statuses = append(statuses, StorageStatusModel{
+			Storage:   types.StringValue(storage.Storage),
+			Type:      types.StringValue(storage.Type),
+			Content:   content,
+			Active:    types.BoolValue(bool(storage.Active)),
+			Enabled:   types.BoolValue(bool(storage.Enabled)),
+			Shared:    types.BoolValue(bool(storage.Shared)),
+			Total:     types.Int64Value(int64(storage.Total)),
+			Used:      types.Int64Value(int64(storage.Used)),
+			Available: types.Int64Value(int64(storage.Available)),
+		})
+	}
+	return statuses, diags
+}
+
+func (d *storageDataSource) Configure(_ context.Context, request datasource.ConfigureRequest, response *datasource.ConfigureResponse) {
+	if request.ProviderData == nil {
+		return
+	}
+
+	client, ok := request.ProviderData.(*proxmox.Client)
+	if !ok {
+		response.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *proxmox.Client, got %T. Please report this error to the developer", request.ProviderData),
+		)
+		return
+	}
+
+	d.client = client
+}
@@ -0,0 +1,75 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"terraform-provider-proxmox/internal/api"
+	"testing"
+)
+
+func TestStorageDataSource_Read(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+data "proxmox_storage" "test" {
+  node    = "pve"
+  content = "images"
+  enabled = true
+}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.proxmox_storage.test", "node", "pve"),
+					resource.TestCheckResourceAttrSet("data.proxmox_storage.test", "storages.0.storage"),
+					resource.TestCheckResourceAttr("data.proxmox_storage.test", "storages.0.enabled", "true"),
+					resource.TestCheckTypeSetElemAttr("data.proxmox_storage.test", "storages.0.content.*", "images"),
+				),
+			},
+		},
+	})
+}
+
+func TestStorageStatuses(t *testing.T) {
+	statuses, diags := storageStatuses([]api.NodeStorage{
+		{Storage: "nas", Type: "nfs", Content: "images,backup", Enabled: true, Shared: true},
+		{Storage: "local-lvm", Type: "lvmthin", Content: "rootdir,images", Active: true, Enabled: true, Total: 100, Used: 30, Available: 70},
+		{Storage: "fast", Type: "zfspool", Content: "images", Active: true, Enabled: true, Total: 500, Used: 100, Available: 400},
+		{Storage: "backup", Type: "dir", Content: "backup", Active: true, Enabled: true, Total: 100, Used: 30, Available: 70},
+	})
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+
+	var order []string
+	for _, status := range statuses {
+		order = append(order, status.Storage.ValueString())
+	}
+	if len(order) != 4 || order[0] != "fast" || order[1] != "backup" || order[2] != "local-lvm" || order[3] != "nas" {
+		t.Errorf("Incorrect storage order: %v", order)
+	}
+	if len(statuses[2].Content.Elements()) != 2 || statuses[2].Available.ValueInt64() != 70 {
+		t.Errorf("Incorrect storage: %+v", statuses[2])
+	}
+	if statuses[3].Active.ValueBool() || !statuses[3].Shared.ValueBool() || statuses[3].Total.ValueInt64() != 0 {
+		t.Errorf("Incorrect inactive storage: %+v", statuses[3])
+	}
+}
+
+func TestFilterStorageEnabled(t *testing.T) {
+	storages := []api.NodeStorage{
+		{Storage: "local-lvm", Enabled: true},
+		{Storage: "old-nas", Enabled: false},
+	}
+
+	if filtered := filterStorageEnabled(storages, types.BoolNull()); len(filtered) != 2 {
+		t.Errorf("Expected every storage without a filter, got %v", filtered)
+	}
+	filtered := filterStorageEnabled(storages, types.BoolValue(false))
+	if len(filtered) != 1 || filtered[0].Storage != "old-nas" {
+		t.Errorf("Expected only the disabled storage, got %v", filtered)
+	}
+	filtered = filterStorageEnabled(storages, types.BoolValue(true))
+	if len(filtered) != 1 || filtered[0].Storage != "local-lvm" {
+		t.Errorf("Expected only the enabled storage, got %v", filtered)
+	}
+}
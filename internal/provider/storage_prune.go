@@ -0,0 +1,148 @@
+package provider
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"strconv"
+	"terraform-provider-proxmox/internal/api"
+)
+
+// StoragePruneBackupsModel How many backups on the storage are kept when old backups are pruned.
+// Each attribute keeps the newest backup of that many periods, a backup counts for the first period that keeps it.
+type StoragePruneBackupsModel struct {
+	KeepAll     types.Bool  `tfsdk:"keep_all"`
+	KeepLast    types.Int64 `tfsdk:"keep_last"`
+	KeepHourly  types.Int64 `tfsdk:"keep_hourly"`
+	KeepDaily   types.Int64 `tfsdk:"keep_daily"`
+	KeepWeekly  types.Int64 `tfsdk:"keep_weekly"`
+	KeepMonthly types.Int64 `tfsdk:"keep_monthly"`
+	KeepYearly  types.Int64 `tfsdk:"keep_yearly"`
+}
+
+func storagePruneBackupsBlock() schema.SingleNestedBlock {
+	return schema.SingleNestedBlock{
+		Description: "The retention of backups on the storage. Without the block every backup is kept unless the backup job prunes them",
+		Attributes: map[string]schema.Attribute{
+			"keep_all": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Keep every backup. Can not be combined with the other attributes",
+			},
+			"keep_last": schema.Int64Attribute{
+				Optional: true,
+			},
+			"keep_hourly": schema.Int64Attribute{
+				Optional: true,
+			},
+			"keep_daily": schema.Int64Attribute{
+				Optional: true,
+			},
+			"keep_weekly": schema.Int64Attribute{
+				Optional: true,
+			},
+			"keep_monthly": schema.Int64Attribute{
+				Optional: true,
+			},
+			"keep_yearly": schema.Int64Attribute{
+				Optional: true,
+			},
+		},
+	}
+}
+
+// pruneBackupsKeeps pairs the Proxmox names of the retention periods with the attributes of the model
+func pruneBackupsKeeps(pruneBackups *StoragePruneBackupsModel) map[string]*types.Int64 {
+	return map[string]*types.Int64{
+		"keep-last":    &pruneBackups.KeepLast,
+		"keep-hourly":  &pruneBackups.KeepHourly,
+		"keep-daily":   &pruneBackups.KeepDaily,
+		"keep-weekly":  &pruneBackups.KeepWeekly,
+		"keep-monthly": &pruneBackups.KeepMonthly,
+		"keep-yearly":  &pruneBackups.KeepYearly,
+	}
+}
+
+// pruneBackupsValue is the property string of the retention, for example keep-daily=7,keep-last=3
+func pruneBackupsValue(pruneBackups *StoragePruneBackupsModel) *string {
+	if pruneBackups == nil {
+		return nil
+	}
+
+	properties := api.PropertyString{}
+	if !pruneBackups.KeepAll.IsNull() {
+		properties.SetFlag("keep-all", pruneBackups.KeepAll.ValueBool())
+	}
+	for key, keep := range pruneBackupsKeeps(pruneBackups) {
+		if !keep.IsNull() {
+			properties[key] = strconv.FormatInt(keep.ValueInt64(), 10)
+		}
+	}
+	value := properties.String()
+	return &value
+}
+
+// readPruneBackups converts the prune-backups property string into the model
+func readPruneBackups(value *string) (*StoragePruneBackupsModel, error) {
+	if value == nil || *value == "" {
+		return nil, nil
+	}
+
+	properties := api.ParsePropertyString(*value)
+	pruneBackups := &StoragePruneBackupsModel{KeepAll: types.BoolNull()}
+	if _, ok := properties["keep-all"]; ok {
+		pruneBackups.KeepAll = types.BoolValue(properties.Flag("keep-all", false))
+	}
+	for key, keep := range pruneBackupsKeeps(pruneBackups) {
+		*keep = types.Int64Null()
+		if properties[key] == "" {
+			continue
+		}
+		number, err := strconv.ParseInt(properties[key], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("prune-backups: the %s %q is not a number", key, properties[key])
+		}
+		*keep = types.Int64Value(number)
+	}
+	return pruneBackups, nil
+}
+
+// validatePruneBackups checks that the block keeps something and that keep_all is not combined with a period
+func validatePruneBackups(pruneBackups *StoragePruneBackupsModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if pruneBackups == nil {
+		return diags
+	}
+
+	periods := 0
+	for key, keep := range pruneBackupsKeeps(pruneBackups) {
+		if keep.IsNull() || keep.IsUnknown() {
+			continue
+		}
+		periods++
+		if keep.ValueInt64() < 1 {
+			diags.AddAttributeError(
+				path.Root("prune_backups"),
+				"Invalid backup retention",
+				fmt.Sprintf("The %s must keep at least one backup. Got: %d", key, keep.ValueInt64()),
+			)
+		}
+	}
+
+	if pruneBackups.KeepAll.ValueBool() && periods > 0 {
+		diags.AddAttributeError(
+			path.Root("prune_backups").AtName("keep_all"),
+			"Invalid backup retention",
+			"keep_all can not be combined with the other retention periods",
+		)
+	}
+	if pruneBackups.KeepAll.IsNull() && periods == 0 {
+		diags.AddAttributeError(
+			path.Root("prune_backups"),
+			"Invalid backup retention",
+			"Set keep_all or at least one retention period",
+		)
+	}
+	return diags
+}
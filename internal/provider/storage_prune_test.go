@@ -0,0 +1,70 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"reflect"
+	"testing"
+)
+
+func TestPruneBackups(t *testing.T) {
+	pruneBackups := &StoragePruneBackupsModel{
+		KeepAll:     types.BoolNull(),
+		KeepLast:    types.Int64Value(3),
+		KeepHourly:  types.Int64Null(),
+		KeepDaily:   types.Int64Value(7),
+		KeepWeekly:  types.Int64Null(),
+		KeepMonthly: types.Int64Value(6),
+		KeepYearly:  types.Int64Null(),
+	}
+
+	value := pruneBackupsValue(pruneBackups)
+	if *value != "keep-daily=7,keep-last=3,keep-monthly=6" {
+		t.Errorf("Incorrect property string: %s", *value)
+	}
+
+	read, err := readPruneBackups(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(read, pruneBackups) {
+		t.Errorf("Incorrect retention read. Expected %+v, got %+v", pruneBackups, read)
+	}
+
+	if read, err = readPruneBackups(nil); err != nil || read != nil {
+		t.Errorf("Expected no retention, got %+v %v", read, err)
+	}
+	invalid := "keep-last=three"
+	if _, err = readPruneBackups(&invalid); err == nil {
+		t.Error("Expected an error for a retention that is not a number")
+	}
+}
+
+func TestValidatePruneBackups(t *testing.T) {
+	pruneBackups := &StoragePruneBackupsModel{
+		KeepAll:     types.BoolValue(true),
+		KeepLast:    types.Int64Value(3),
+		KeepHourly:  types.Int64Null(),
+		KeepDaily:   types.Int64Null(),
+		KeepWeekly:  types.Int64Null(),
+		KeepMonthly: types.Int64Null(),
+		KeepYearly:  types.Int64Null(),
+	}
+	if diags := validatePruneBackups(pruneBackups); diags.ErrorsCount() != 1 {
+		t.Errorf("Expected an error for keep_all with a period, got %v", diags)
+	}
+
+	pruneBackups.KeepAll = types.BoolNull()
+	pruneBackups.KeepLast = types.Int64Value(0)
+	if diags := validatePruneBackups(pruneBackups); diags.ErrorsCount() != 1 {
+		t.Errorf("Expected an error for keeping no backups, got %v", diags)
+	}
+
+	pruneBackups.KeepLast = types.Int64Null()
+	if diags := validatePruneBackups(pruneBackups); diags.ErrorsCount() != 1 {
+		t.Errorf("Expected an error for an empty block, got %v", diags)
+	}
+
+	if diags := validatePruneBackups(nil); diags.HasError() {
+		t.Errorf("Expected no errors without the block, got %v", diags)
+	}
+}
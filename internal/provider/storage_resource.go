@@ -0,0 +1,631 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/clincha-org/proxmox-api/pkg/proxmox"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"sort"
+	"strings"
+	"terraform-provider-proxmox/internal/api"
+)
+
+var (
+	_ resource.Resource                   = &storageResource{}
+	_ resource.ResourceWithConfigure      = &storageResource{}
+	_ resource.ResourceWithImportState    = &storageResource{}
+	_ resource.ResourceWithValidateConfig = &storageResource{}
+)
+
+type StorageResourceModel struct {
+	ID            types.String              `tfsdk:"id"`
+	Storage       types.String              `tfsdk:"storage"`
+	Type          types.String              `tfsdk:"type"`
+	Content       types.Set                 `tfsdk:"content"`
+	Nodes         types.Set                 `tfsdk:"nodes"`
+	Disable       types.Bool                `tfsdk:"disable"`
+	Shared        types.Bool                `tfsdk:"shared"`
+	PruneBackups  *StoragePruneBackupsModel `tfsdk:"prune_backups"`
+	Path          types.String              `tfsdk:"path"`
+	Server        types.String              `tfsdk:"server"`
+	Export        types.String              `tfsdk:"export"`
+	Options       types.String              `tfsdk:"options"`
+	Share         types.String              `tfsdk:"share"`
+	Domain        types.String              `tfsdk:"domain"`
+	SMBVersion    types.String              `tfsdk:"smb_version"`
+	Subdir        types.String              `tfsdk:"subdir"`
+	Username      types.String              `tfsdk:"username"`
+	Password      types.String              `tfsdk:"password"`
+	VGName        types.String              `tfsdk:"vgname"`
+	ThinPool      types.String              `tfsdk:"thinpool"`
+	Pool          types.String              `tfsdk:"pool"`
+	BlockSize     types.String              `tfsdk:"blocksize"`
+	Sparse        types.Bool                `tfsdk:"sparse"`
+	MonHost       types.List                `tfsdk:"monhost"`
+	Keyring       types.String              `tfsdk:"keyring"`
+	KRBD          types.Bool                `tfsdk:"krbd"`
+	FSName        types.String              `tfsdk:"fs_name"`
+	Datastore     types.String              `tfsdk:"datastore"`
+	Namespace     types.String              `tfsdk:"namespace"`
+	Fingerprint   types.String              `tfsdk:"fingerprint"`
+	EncryptionKey types.String              `tfsdk:"encryption_key"`
+}
+
+// storageTypes lists the attributes that each type of storage takes, the attributes that every storage takes
+// are left out. Proxmox only reports unknown attributes when the storage is created.
+var storageTypes = map[string]struct {
+	required []string
+	optional []string
+}{
+	"dir":     {required: []string{"path"}},
+	"nfs":     {required: []string{"server", "export"}, optional: []string{"path", "options"}},
+	"cifs":    {required: []string{"server", "share"}, optional: []string{"path", "username", "password", "domain", "smb_version", "subdir"}},
+	"lvm":     {required: []string{"vgname"}},
+	"lvmthin": {required: []string{"vgname", "thinpool"}},
+	"zfspool": {required: []string{"pool"}, optional: []string{"blocksize", "sparse"}},
+	"rbd":     {required: []string{"pool"}, optional: []string{"monhost", "username", "keyring", "krbd", "namespace"}},
+	"cephfs":  {optional: []string{"path", "monhost", "username", "keyring", "fs_name", "subdir"}},
+	"pbs":     {required: []string{"server", "datastore", "username", "password"}, optional: []string{"namespace", "fingerprint", "encryption_key"}},
+}
+
+type storageResource struct {
+	client *proxmox.Client
+}
+
+func NewStorageResource() resource.Resource {
+	return &storageResource{}
+}
+
+func (r *storageResource) Metadata(_ context.Context, request resource.MetadataRequest, response *resource.MetadataResponse) {
+	response.TypeName = request.ProviderTypeName + "_storage"
+}
+
+func (r *storageResource) Configure(_ context.Context, request resource.ConfigureRequest, response *resource.ConfigureResponse) {
+	if request.ProviderData == nil {
+		return
+	}
+
+	client, ok := request.ProviderData.(*proxmox.Client)
+
+	if !ok {
+		response.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *proxmox.Client, got %T. Please report this issue to the developers", request.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+func (r *storageResource) Schema(_ context.Context, _ resource.SchemaRequest, response *resource.SchemaResponse) {
+	requiresReplace := []planmodifier.String{
+		stringplanmodifier.RequiresReplace(),
+	}
+
+	response.Schema = schema.Schema{
+		Description: "A storage definition of the cluster. Destroying it only removes the definition, the data on the storage is kept",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"storage": schema.StringAttribute{
+				Required:      true,
+				Description:   "The ID of the storage, for example nas",
+				PlanModifiers: requiresReplace,
+			},
+			"type": schema.StringAttribute{
+				Required:      true,
+				Description:   "One of dir, nfs, cifs, lvm, lvmthin, zfspool, rbd, cephfs or pbs",
+				PlanModifiers: requiresReplace,
+			},
+			"content": schema.SetAttribute{
+				Optional:    true,
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "What the storage holds: images, rootdir, vztmpl, iso, backup, snippets or import. Defaults to the content Proxmox picks for the type",
+				PlanModifiers: []planmodifier.Set{
+					setplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"nodes": schema.SetAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "The nodes that can use the storage. Defaults to every node",
+			},
+			"disable": schema.BoolAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+			},
+			"shared": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "The same dir or lvm storage is available on every node, for example a SAN. Network storages are always shared",
+			},
+			"path": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "The directory of a dir storage, or where nfs, cifs and cephfs storages are mounted. Defaults to /mnt/pve/<storage> for those",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"server": schema.StringAttribute{
+				Optional:      true,
+				Description:   "The address of the nfs, cifs or pbs server",
+				PlanModifiers: requiresReplace,
+			},
+			"export": schema.StringAttribute{
+				Optional:      true,
+				Description:   "The exported directory of the NFS server",
+				PlanModifiers: requiresReplace,
+			},
+			"options": schema.StringAttribute{
+				Optional:    true,
+				Description: "The NFS mount options, for example vers=4.2",
+			},
+			"share": schema.StringAttribute{
+				Optional:      true,
+				Description:   "The name of the CIFS share",
+				PlanModifiers: requiresReplace,
+			},
+			"domain": schema.StringAttribute{
+				Optional: true,
+			},
+			"smb_version": schema.StringAttribute{
+				Optional:    true,
+				Description: "The SMB protocol version, for example 3. Defaults to the highest version the server supports",
+			},
+			"subdir": schema.StringAttribute{
+				Optional:    true,
+				Description: "The directory of the cifs share or cephfs filesystem to use",
+			},
+			"username": schema.StringAttribute{
+				Optional:    true,
+				Description: "The user for cifs, rbd, cephfs and pbs storages. For pbs it includes the realm, for example backup@pbs",
+			},
+			"password": schema.StringAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				Description: "The password of the cifs or pbs user. Proxmox does not return it, so changes made outside Terraform are not detected",
+			},
+			"vgname": schema.StringAttribute{
+				Optional:      true,
+				Description:   "The LVM volume group",
+				PlanModifiers: requiresReplace,
+			},
+			"thinpool": schema.StringAttribute{
+				Optional:      true,
+				Description:   "The LVM thin pool in the volume group",
+				PlanModifiers: requiresReplace,
+			},
+			"pool": schema.StringAttribute{
+				Optional:      true,
+				Description:   "The ZFS pool or dataset, or the Ceph pool of an rbd storage",
+				PlanModifiers: requiresReplace,
+			},
+			"blocksize": schema.StringAttribute{
+				Optional:    true,
+				Description: "The block size of new ZFS volumes, for example 16k",
+			},
+			"sparse": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Create thin provisioned ZFS volumes",
+			},
+			"monhost": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "The monitors of an external Ceph cluster. Leave unset for the Ceph cluster of the nodes",
+			},
+			"keyring": schema.StringAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				Description: "The keyring of an rbd storage or the secret of a cephfs storage on an external Ceph cluster. Proxmox does not return it",
+			},
+			"krbd": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Access rbd volumes through the kernel module instead of librbd",
+			},
+			"fs_name": schema.StringAttribute{
+				Optional:    true,
+				Description: "The Ceph filesystem to use. Defaults to the default filesystem of the Ceph cluster",
+			},
+			"datastore": schema.StringAttribute{
+				Optional:      true,
+				Description:   "The datastore on the Proxmox Backup Server",
+				PlanModifiers: requiresReplace,
+			},
+			"namespace": schema.StringAttribute{
+				Optional:    true,
+				Description: "The namespace in the pbs datastore or rbd pool",
+			},
+			"fingerprint": schema.StringAttribute{
+				Optional:    true,
+				Description: "The fingerprint of the certificate of the Proxmox Backup Server, needed when it is not trusted by the nodes",
+			},
+			"encryption_key": schema.StringAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				Description: "The key that encrypts backups on the Proxmox Backup Server, or autogen to generate one. Proxmox does not return it",
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"prune_backups": storagePruneBackupsBlock(),
+		},
+	}
+}
+
+// storageAttributeValues returns the type specific attributes of the model by name
+func storageAttributeValues(model StorageResourceModel) map[string]attr.Value {
+	return map[string]attr.Value{
+		"path":           model.Path,
+		"server":         model.Server,
+		"export":         model.Export,
+		"options":        model.Options,
+		"share":          model.Share,
+		"domain":         model.Domain,
+		"smb_version":    model.SMBVersion,
+		"subdir":         model.Subdir,
+		"username":       model.Username,
+		"password":       model.Password,
+		"vgname":         model.VGName,
+		"thinpool":       model.ThinPool,
+		"pool":           model.Pool,
+		"blocksize":      model.BlockSize,
+		"sparse":         model.Sparse,
+		"monhost":        model.MonHost,
+		"keyring":        model.Keyring,
+		"krbd":           model.KRBD,
+		"fs_name":        model.FSName,
+		"datastore":      model.Datastore,
+		"namespace":      model.Namespace,
+		"fingerprint":    model.Fingerprint,
+		"encryption_key": model.EncryptionKey,
+	}
+}
+
+// validateStorage checks that the configuration sets the attributes that its type requires and no attributes
+// of other types
+func validateStorage(config StorageResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if config.Type.IsUnknown() {
+		return diags
+	}
+
+	storageType, ok := storageTypes[config.Type.ValueString()]
+	if !ok {
+		var names []string
+		for name := range storageTypes {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		diags.AddAttributeError(
+			path.Root("type"),
+			"Unsupported storage type",
+			fmt.Sprintf("The storage type must be one of %s. Got: %q", strings.Join(names, ", "), config.Type.ValueString()),
+		)
+		return diags
+	}
+
+	values := storageAttributeValues(config)
+	allowed := map[string]bool{}
+	for _, name := range storageType.required {
+		allowed[name] = true
+		if values[name].IsNull() {
+			diags.AddAttributeError(
+				path.Root(name),
+				"Missing storage attribute",
+				fmt.Sprintf("The %s attribute is required for %s storages", name, config.Type.ValueString()),
+			)
+		}
+	}
+	for _, name := range storageType.optional {
+		allowed[name] = true
+	}
+
+	for _, name := range sortedKeys(values) {
+		if !allowed[name] && !values[name].IsNull() && !values[name].IsUnknown() {
+			diags.AddAttributeError(
+				path.Root(name),
+				"Unexpected storage attribute",
+				fmt.Sprintf("The %s attribute is not used by %s storages", name, config.Type.ValueString()),
+			)
+		}
+	}
+
+	if config.Shared.ValueBool() && !storageTakesShared(config.Type.ValueString()) {
+		diags.AddAttributeError(
+			path.Root("shared"),
+			"Unexpected storage attribute",
+			fmt.Sprintf("Only dir and lvm storages can be marked as shared, %s storages are shared by their type", config.Type.ValueString()),
+		)
+	}
+
+	diags.Append(validatePruneBackups(config.PruneBackups)...)
+	return diags
+}
+
+// storageTakesShared reports whether the shared flag can be set on the type. Network storages are always shared,
+// the flag is only for local storage types that every node can reach, for example a SAN.
+func storageTakesShared(storageType string) bool {
+	return storageType == "dir" || storageType == "lvm"
+}
+
+func (r *storageResource) ValidateConfig(ctx context.Context, request resource.ValidateConfigRequest, response *resource.ValidateConfigResponse) {
+	var config StorageResourceModel
+	response.Diagnostics.Append(request.Config.Get(ctx, &config)...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	response.Diagnostics.Append(validateStorage(config)...)
+}
+
+func (r *storageResource) ImportState(ctx context.Context, request resource.ImportStateRequest, response *resource.ImportStateResponse) {
+	response.Diagnostics.Append(response.State.SetAttribute(ctx, path.Root("storage"), request.ID)...)
+}
+
+// storageRequest builds the API request from the plan. The storage, type and the attributes that locate the
+// storage are left out because Proxmox only accepts them when the storage is created. The credentials are
+// only sent when they differ from the prior ones, Proxmox does not return them to compare against.
+func storageRequest(ctx context.Context, plan StorageResourceModel, prior StorageResourceModel) (api.StorageRequest, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	storageRequest := api.StorageRequest{
+		Disable:      api.NewBool(plan.Disable.ValueBoolPointer()),
+		PruneBackups: pruneBackupsValue(plan.PruneBackups),
+		Options:      plan.Options.ValueStringPointer(),
+		Domain:       plan.Domain.ValueStringPointer(),
+		SMBVersion:   plan.SMBVersion.ValueStringPointer(),
+		Subdir:       plan.Subdir.ValueStringPointer(),
+		Username:     plan.Username.ValueStringPointer(),
+		BlockSize:    plan.BlockSize.ValueStringPointer(),
+		Sparse:       api.NewBool(plan.Sparse.ValueBoolPointer()),
+		KRBD:         api.NewBool(plan.KRBD.ValueBoolPointer()),
+		FSName:       plan.FSName.ValueStringPointer(),
+		Namespace:    plan.Namespace.ValueStringPointer(),
+		Fingerprint:  plan.Fingerprint.ValueStringPointer(),
+	}
+
+	if plan.Shared.ValueBool() && storageTakesShared(plan.Type.ValueString()) {
+		storageRequest.Shared = api.NewBool(plan.Shared.ValueBoolPointer())
+	}
+	if !plan.Password.Equal(prior.Password) {
+		storageRequest.Password = plan.Password.ValueStringPointer()
+	}
+	if !plan.Keyring.Equal(prior.Keyring) {
+		storageRequest.Keyring = plan.Keyring.ValueStringPointer()
+	}
+	if !plan.EncryptionKey.Equal(prior.EncryptionKey) {
+		storageRequest.EncryptionKey = plan.EncryptionKey.ValueStringPointer()
+	}
+
+	content, setDiags := joinSet(ctx, plan.Content)
+	diags.Append(setDiags...)
+	storageRequest.Content = content
+
+	nodes, setDiags := joinSet(ctx, plan.Nodes)
+	diags.Append(setDiags...)
+	storageRequest.Nodes = nodes
+
+	monHost, listDiags := joinList(ctx, plan.MonHost)
+	diags.Append(listDiags...)
+	storageRequest.MonHost = monHost
+
+	return storageRequest, diags
+}
+
+// storageState converts the API response into state. Proxmox does not return the credentials, so the
+// ones in the prior model are kept. Proxmox reports network storages as shared, for them the flag of the
+// prior model is kept because it can not be set.
+func storageState(storage api.Storage, prior StorageResourceModel) (StorageResourceModel, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	state := StorageResourceModel{
+		ID:            types.StringValue(storage.Storage),
+		Storage:       types.StringValue(storage.Storage),
+		Type:          types.StringValue(storage.Type),
+		Disable:       types.BoolValue(storage.Disable != nil && bool(*storage.Disable)),
+		Shared:        types.BoolValue(storage.Shared != nil && bool(*storage.Shared)),
+		Path:          types.StringPointerValue(storage.Path),
+		Server:        types.StringPointerValue(storage.Server),
+		Export:        types.StringPointerValue(storage.Export),
+		Options:       types.StringPointerValue(storage.Options),
+		Share:         types.StringPointerValue(storage.Share),
+		Domain:        types.StringPointerValue(storage.Domain),
+		SMBVersion:    types.StringPointerValue(storage.SMBVersion),
+		Subdir:        types.StringPointerValue(storage.Subdir),
+		Username:      types.StringPointerValue(storage.Username),
+		Password:      prior.Password,
+		VGName:        types.StringPointerValue(storage.VGName),
+		ThinPool:      types.StringPointerValue(storage.ThinPool),
+		Pool:          types.StringPointerValue(storage.Pool),
+		BlockSize:     types.StringPointerValue(storage.BlockSize),
+		Sparse:        types.BoolPointerValue(storage.Sparse.Pointer()),
+		Keyring:       prior.Keyring,
+		KRBD:          types.BoolPointerValue(storage.KRBD.Pointer()),
+		FSName:        types.StringPointerValue(storage.FSName),
+		Datastore:     types.StringPointerValue(storage.Datastore),
+		Namespace:     types.StringPointerValue(storage.Namespace),
+		Fingerprint:   types.StringPointerValue(storage.Fingerprint),
+		EncryptionKey: prior.EncryptionKey,
+	}
+
+	if !storageTakesShared(storage.Type) {
+		state.Shared = types.BoolValue(prior.Shared.ValueBool())
+	}
+
+	pruneBackups, err := readPruneBackups(storage.PruneBackups)
+	if err != nil {
+		diags.AddError("Error reading Proxmox storage", "Could not read the Proxmox storage: "+storage.Storage+": "+err.Error())
+	}
+	state.PruneBackups = pruneBackups
+
+	content, setDiags := splitSet(storage.Content)
+	diags.Append(setDiags...)
+	state.Content = content
+
+	nodes, setDiags := splitSet(storage.Nodes)
+	diags.Append(setDiags...)
+	state.Nodes = nodes
+
+	monHost, listDiags := splitList(storage.MonHost)
+	diags.Append(listDiags...)
+	state.MonHost = monHost
+
+	return state, diags
+}
+
+func (r *storageResource) Create(ctx context.Context, request resource.CreateRequest, response *resource.CreateResponse) {
+	var plan StorageResourceModel
+	diags := request.Plan.Get(ctx, &plan)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	storageRequest, diags := storageRequest(ctx, plan, StorageResourceModel{})
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+	storageRequest.Storage = plan.Storage.ValueString()
+	storageRequest.Type = plan.Type.ValueString()
+	if !plan.Path.IsUnknown() {
+		storageRequest.Path = plan.Path.ValueStringPointer()
+	}
+	storageRequest.Server = plan.Server.ValueStringPointer()
+	storageRequest.Export = plan.Export.ValueStringPointer()
+	storageRequest.Share = plan.Share.ValueStringPointer()
+	storageRequest.VGName = plan.VGName.ValueStringPointer()
+	storageRequest.ThinPool = plan.ThinPool.ValueStringPointer()
+	storageRequest.Pool = plan.Pool.ValueStringPointer()
+	storageRequest.Datastore = plan.Datastore.ValueStringPointer()
+
+	storage, err := api.CreateStorage(r.client, &storageRequest)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error creating Proxmox storage",
+			"Could not create the Proxmox storage: "+plan.Storage.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+
+	state, diags := storageState(storage, plan)
+	response.Diagnostics.Append(diags...)
+
+	diags = response.State.Set(ctx, state)
+	response.Diagnostics.Append(diags...)
+}
+
+func (r *storageResource) Read(ctx context.Context, request resource.ReadRequest, response *resource.ReadResponse) {
+	var state StorageResourceModel
+	diags := request.State.Get(ctx, &state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	storage, err := api.GetStorage(r.client, state.Storage.ValueString())
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error reading Proxmox storage",
+			"Could not read the Proxmox storage: "+state.Storage.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+
+	state, diags = storageState(storage, state)
+	response.Diagnostics.Append(diags...)
+
+	diags = response.State.Set(ctx, &state)
+	response.Diagnostics.Append(diags...)
+}
+
+func (r *storageResource) Update(ctx context.Context, request resource.UpdateRequest, response *resource.UpdateResponse) {
+	var plan, state StorageResourceModel
+	response.Diagnostics.Append(request.Plan.Get(ctx, &plan)...)
+	response.Diagnostics.Append(request.State.Get(ctx, &state)...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	storageRequest, diags := storageRequest(ctx, plan, state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	var removed removedAttributes
+	removed.check("nodes", plan.Nodes, state.Nodes)
+	removed.check("options", plan.Options, state.Options)
+	removed.check("domain", plan.Domain, state.Domain)
+	removed.check("smbversion", plan.SMBVersion, state.SMBVersion)
+	removed.check("subdir", plan.Subdir, state.Subdir)
+	removed.check("username", plan.Username, state.Username)
+	removed.check("password", plan.Password, state.Password)
+	removed.check("blocksize", plan.BlockSize, state.BlockSize)
+	removed.check("sparse", plan.Sparse, state.Sparse)
+	removed.check("monhost", plan.MonHost, state.MonHost)
+	removed.check("keyring", plan.Keyring, state.Keyring)
+	removed.check("krbd", plan.KRBD, state.KRBD)
+	removed.check("fs-name", plan.FSName, state.FSName)
+	removed.check("namespace", plan.Namespace, state.Namespace)
+	removed.check("fingerprint", plan.Fingerprint, state.Fingerprint)
+	removed.check("encryption-key", plan.EncryptionKey, state.EncryptionKey)
+	if plan.PruneBackups == nil && state.PruneBackups != nil {
+		removed = append(removed, "prune-backups")
+	}
+	if !plan.Shared.ValueBool() && state.Shared.ValueBool() {
+		removed = append(removed, "shared")
+	}
+	storageRequest.Delete = removed.value()
+
+	storage, err := api.UpdateStorage(r.client, plan.Storage.ValueString(), &storageRequest)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error updating Proxmox storage",
+			"Could not update the Proxmox storage: "+plan.Storage.ValueString()+". Got this error: "+err.Error(),
+		)
+		return
+	}
+
+	plan, diags = storageState(storage, plan)
+	response.Diagnostics.Append(diags...)
+
+	diags = response.State.Set(ctx, plan)
+	response.Diagnostics.Append(diags...)
+}
+
+func (r *storageResource) Delete(ctx context.Context, request resource.DeleteRequest, response *resource.DeleteResponse) {
+	var state StorageResourceModel
+	diags := request.State.Get(ctx, &state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	err := api.DeleteStorage(r.client, state.Storage.ValueString())
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error deleting Proxmox storage",
+			"Could not delete the Proxmox storage: "+state.Storage.ValueString()+". Got this error: "+err.Error(),
+		)
+		return
+	}
+}
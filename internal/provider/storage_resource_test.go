@@ -0,0 +1,199 @@
+package provider
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"terraform-provider-proxmox/internal/api"
+	"testing"
+)
+
+func TestStorageResource_Create(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+resource "proxmox_storage" "test" {
+  storage = "terraform-test"
+  type    = "dir"
+  path    = "/var/lib/terraform-test"
+  content = ["backup", "iso"]
+  nodes   = ["pve"]
+
+  prune_backups {
+    keep_last  = 3
+    keep_daily = 7
+  }
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("proxmox_storage.test", "id", "terraform-test"),
+					resource.TestCheckResourceAttr("proxmox_storage.test", "type", "dir"),
+					resource.TestCheckResourceAttr("proxmox_storage.test", "content.#", "2"),
+					resource.TestCheckResourceAttr("proxmox_storage.test", "nodes.#", "1"),
+					resource.TestCheckResourceAttr("proxmox_storage.test", "disable", "false"),
+					resource.TestCheckResourceAttr("proxmox_storage.test", "prune_backups.keep_last", "3"),
+				),
+			},
+			{
+				ResourceName:      "proxmox_storage.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateId:     "terraform-test",
+			},
+			{
+				Config: providerConfig + `
+resource "proxmox_storage" "test" {
+  storage = "terraform-test"
+  type    = "dir"
+  path    = "/var/lib/terraform-test"
+  content = ["backup", "iso", "vztmpl"]
+  disable = true
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("proxmox_storage.test", "content.#", "3"),
+					resource.TestCheckNoResourceAttr("proxmox_storage.test", "nodes.#"),
+					resource.TestCheckResourceAttr("proxmox_storage.test", "disable", "true"),
+					resource.TestCheckNoResourceAttr("proxmox_storage.test", "prune_backups.keep_last"),
+				),
+			},
+		},
+	})
+}
+
+func TestValidateStorage(t *testing.T) {
+	nfs := StorageResourceModel{
+		Type:   types.StringValue("nfs"),
+		Server: types.StringValue("10.0.0.10"),
+		Export: types.StringValue("/srv/proxmox"),
+	}
+	if diags := validateStorage(nfs); diags.HasError() {
+		t.Errorf("Expected the nfs storage to be valid, got %v", diags)
+	}
+
+	nfs.Pool = types.StringValue("tank")
+	nfs.Shared = types.BoolValue(true)
+	if diags := validateStorage(nfs); diags.ErrorsCount() != 2 {
+		t.Errorf("Expected errors for the pool and shared flag of an nfs storage, got %v", diags)
+	}
+
+	pbs := StorageResourceModel{
+		Type:      types.StringValue("pbs"),
+		Server:    types.StringValue("pbs.example.com"),
+		Datastore: types.StringValue("backups"),
+	}
+	if diags := validateStorage(pbs); diags.ErrorsCount() != 2 {
+		t.Errorf("Expected errors for the missing username and password, got %v", diags)
+	}
+
+	lvm := StorageResourceModel{
+		Type:   types.StringValue("lvm"),
+		VGName: types.StringValue("data"),
+		Shared: types.BoolValue(true),
+	}
+	if diags := validateStorage(lvm); diags.HasError() {
+		t.Errorf("Expected a shared lvm storage to be valid, got %v", diags)
+	}
+
+	if diags := validateStorage(StorageResourceModel{Type: types.StringValue("iscsi")}); diags.ErrorsCount() != 1 {
+		t.Errorf("Expected an error for an unsupported type, got %v", diags)
+	}
+}
+
+func TestStorageResource_NFS(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+resource "proxmox_storage" "nfs" {
+  storage = "terraform-nfs"
+  type    = "nfs"
+  server  = "127.0.0.1"
+  export  = "/srv/nfs/terraform-test"
+  content = ["backup"]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("proxmox_storage.nfs", "type", "nfs"),
+					resource.TestCheckResourceAttr("proxmox_storage.nfs", "shared", "false"),
+					resource.TestCheckResourceAttr("proxmox_storage.nfs", "path", "/mnt/pve/terraform-nfs"),
+				),
+			},
+			{
+				ResourceName:      "proxmox_storage.nfs",
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateId:     "terraform-nfs",
+			},
+			{
+				Config: providerConfig + `
+resource "proxmox_storage" "nfs" {
+  storage = "terraform-nfs"
+  type    = "nfs"
+  server  = "127.0.0.1"
+  export  = "/srv/nfs/terraform-test"
+  content = ["backup", "iso"]
+  nodes   = ["pve"]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("proxmox_storage.nfs", "content.#", "2"),
+					resource.TestCheckResourceAttr("proxmox_storage.nfs", "nodes.#", "1"),
+					resource.TestCheckResourceAttr("proxmox_storage.nfs", "shared", "false"),
+				),
+			},
+		},
+	})
+}
+
+func TestStorageState(t *testing.T) {
+	shared := api.Bool(true)
+	server := "10.0.0.10"
+	nfs := api.Storage{Storage: "nas", Type: "nfs", Server: &server, Shared: &shared}
+
+	// Proxmox reports every network storage as shared, the flag can not be set on them
+	state, diags := storageState(nfs, StorageResourceModel{Shared: types.BoolValue(false)})
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	if state.Shared.IsNull() || state.Shared.ValueBool() {
+		t.Errorf("Expected the nfs storage not to be marked as shared, got %v", state.Shared)
+	}
+
+	// After an import there is no prior flag
+	state, _ = storageState(nfs, StorageResourceModel{})
+	if state.Shared.IsNull() || state.Shared.ValueBool() {
+		t.Errorf("Expected the imported nfs storage not to be marked as shared, got %v", state.Shared)
+	}
+
+	path := "/mnt/san"
+	state, _ = storageState(api.Storage{Storage: "san", Type: "dir", Path: &path, Shared: &shared}, StorageResourceModel{Shared: types.BoolValue(false)})
+	if !state.Shared.ValueBool() {
+		t.Errorf("Expected the dir storage to be marked as shared, got %v", state.Shared)
+	}
+}
+
+func TestStorageRequest(t *testing.T) {
+	plan := StorageResourceModel{
+		Type:    types.StringValue("nfs"),
+		Server:  types.StringValue("10.0.0.10"),
+		Export:  types.StringValue("/srv/proxmox"),
+		Options: types.StringValue("vers=4.2"),
+		Shared:  types.BoolValue(false),
+	}
+
+	// Proxmox refuses updates that contain the attributes that locate the storage
+	storageRequest, diags := storageRequest(context.Background(), plan, plan)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	if storageRequest.Server != nil || storageRequest.Export != nil || storageRequest.Path != nil {
+		t.Errorf("Expected no location attributes in the request, got %+v", storageRequest)
+	}
+	if storageRequest.Options == nil || *storageRequest.Options != "vers=4.2" {
+		t.Errorf("Incorrect options in the request: %v", storageRequest.Options)
+	}
+}
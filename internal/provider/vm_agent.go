@@ -0,0 +1,186 @@
+package provider
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"net"
+	"reflect"
+	"terraform-provider-proxmox/internal/api"
+	"time"
+)
+
+// VirtualMachineAgentModel The QEMU guest agent settings. Timeout is only used by the provider.
+type VirtualMachineAgentModel struct {
+	Enabled types.Bool  `tfsdk:"enabled"`
+	Timeout types.Int64 `tfsdk:"timeout"`
+}
+
+func virtualMachineAgentBlock() schema.SingleNestedBlock {
+	return schema.SingleNestedBlock{
+		Description: "The QEMU guest agent, it reports the addresses of the guest",
+		Attributes: map[string]schema.Attribute{
+			"enabled": schema.BoolAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+			},
+			"timeout": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(300),
+				Description: "The number of seconds to wait for the guest agent to report an address after the virtual machine starts",
+			},
+		},
+	}
+}
+
+// agentValue is the agent configuration that Proxmox expects, for example enabled=1
+func agentValue(agent *VirtualMachineAgentModel) *string {
+	if agent == nil {
+		return nil
+	}
+	properties := api.PropertyString{}
+	properties.SetFlag("enabled", agent.Enabled.ValueBool())
+	value := properties.String()
+	return &value
+}
+
+// readAgent converts the agent configuration into the model. Proxmox returns either a plain 1 or 0, or a property
+// string such as enabled=1,fstrim_cloned_disks=1.
+func readAgent(value *string, prior *VirtualMachineAgentModel) *VirtualMachineAgentModel {
+	if value == nil && prior == nil {
+		return nil
+	}
+
+	agent := VirtualMachineAgentModel{Enabled: types.BoolValue(false), Timeout: types.Int64Value(300)}
+	if prior != nil {
+		agent.Timeout = prior.Timeout
+	}
+	if value != nil {
+		properties := api.ParsePropertyString(*value)
+		if _, ok := properties["enabled"]; ok {
+			agent.Enabled = types.BoolValue(properties.Flag("enabled", false))
+		} else {
+			agent.Enabled = types.BoolValue(properties.Flag("", false))
+		}
+	}
+	return &agent
+}
+
+// guestAddresses The addresses reported by the guest agent, one entry per network interface
+type guestAddresses struct {
+	names []attr.Value
+	macs  []attr.Value
+	ipv4  []attr.Value
+	ipv6  []attr.Value
+}
+
+// newGuestAddresses keeps the interfaces and addresses that can be reached from outside the guest.
+// Loopback interfaces and loopback and link-local addresses are left out.
+func newGuestAddresses(interfaces []api.GuestNetworkInterface) guestAddresses {
+	addresses := guestAddresses{names: []attr.Value{}, macs: []attr.Value{}, ipv4: []attr.Value{}, ipv6: []attr.Value{}}
+	for _, guestInterface := range interfaces {
+		if guestInterface.Name == "lo" {
+			continue
+		}
+
+		ipv4 := []attr.Value{}
+		ipv6 := []attr.Value{}
+		for _, address := range guestInterface.IPAddresses {
+			ip := net.ParseIP(address.Address)
+			if ip == nil || ip.IsLoopback() || ip.IsLinkLocalUnicast() {
+				continue
+			}
+			if ip.To4() != nil {
+				ipv4 = append(ipv4, types.StringValue(address.Address))
+			} else {
+				ipv6 = append(ipv6, types.StringValue(address.Address))
+			}
+		}
+
+		addresses.names = append(addresses.names, types.StringValue(guestInterface.Name))
+		addresses.macs = append(addresses.macs, types.StringValue(guestInterface.HardwareAddress))
+		addresses.ipv4 = append(addresses.ipv4, types.ListValueMust(types.StringType, ipv4))
+		addresses.ipv6 = append(addresses.ipv6, types.ListValueMust(types.StringType, ipv6))
+	}
+	return addresses
+}
+
+// hasAddress reports whether any interface has an address, which means the guest has finished configuring its network
+func (a guestAddresses) hasAddress() bool {
+	for i := range a.names {
+		if len(a.ipv4[i].(types.List).Elements()) > 0 || len(a.ipv6[i].(types.List).Elements()) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (a guestAddresses) setModel(model *VirtualMachineResourceModel) {
+	model.NetworkInterfaceNames = types.ListValueMust(types.StringType, a.names)
+	model.MACAddresses = types.ListValueMust(types.StringType, a.macs)
+	model.IPv4Addresses = types.ListValueMust(types.ListType{ElemType: types.StringType}, a.ipv4)
+	model.IPv6Addresses = types.ListValueMust(types.ListType{ElemType: types.StringType}, a.ipv6)
+}
+
+// readGuestAddresses asks the guest agent for the addresses of a running virtual machine. The agent may not be
+// running yet, so a failure leaves the addresses empty.
+func (r *virtualMachineResource) readGuestAddresses(model *VirtualMachineResourceModel) {
+	addresses := newGuestAddresses(nil)
+	if model.Started.ValueBool() && model.Agent != nil && model.Agent.Enabled.ValueBool() {
+		interfaces, err := api.GetVirtualMachineNetworkInterfaces(r.client, model.Node.ValueString(), model.VMID.ValueInt64())
+		if err == nil {
+			addresses = newGuestAddresses(interfaces)
+		}
+	}
+	addresses.setModel(model)
+}
+
+// waitForGuestAddresses polls the guest agent of a running virtual machine until it reports an address.
+// Not every guest gets an address, so running out of time is a warning.
+func (r *virtualMachineResource) waitForGuestAddresses(plan VirtualMachineResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if plan.Agent == nil || !plan.Agent.Enabled.ValueBool() {
+		return diags
+	}
+
+	node := plan.Node.ValueString()
+	vmid := plan.VMID.ValueInt64()
+	status, err := api.GetVirtualMachineStatus(r.client, node, vmid)
+	if err != nil || status.Status != "running" {
+		return diags
+	}
+
+	timeout := time.Duration(plan.Agent.Timeout.ValueInt64()) * time.Second
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		interfaces, err := api.GetVirtualMachineNetworkInterfaces(r.client, node, vmid)
+		if err == nil && newGuestAddresses(interfaces).hasAddress() {
+			return diags
+		}
+		time.Sleep(api.TaskPollInterval)
+	}
+
+	diags.AddWarning(
+		"Proxmox guest agent did not report an address",
+		fmt.Sprintf("The guest agent of the Proxmox virtual machine %d did not report an address within %s. The addresses are updated the next time the virtual machine is refreshed", vmid, timeout),
+	)
+	return diags
+}
+
+// guestAddressesMayChange reports whether an update can change the addresses reported by the guest agent, because the
+// virtual machine is started or its network configuration changed. Otherwise the addresses are not waited for.
+func guestAddressesMayChange(plan VirtualMachineResourceModel, state VirtualMachineResourceModel) bool {
+	if plan.Started.ValueBool() && !state.Started.ValueBool() {
+		return true
+	}
+	if state.Agent == nil || !state.Agent.Enabled.ValueBool() {
+		return true
+	}
+	return !reflect.DeepEqual(plan.NetworkDevices, state.NetworkDevices) || !reflect.DeepEqual(plan.Initialization, state.Initialization)
+}
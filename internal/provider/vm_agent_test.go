@@ -0,0 +1,112 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"terraform-provider-proxmox/internal/api"
+	"testing"
+)
+
+func TestNewGuestAddresses(t *testing.T) {
+	interfaces := []api.GuestNetworkInterface{
+		{Name: "lo", HardwareAddress: "00:00:00:00:00:00", IPAddresses: []api.GuestIPAddress{
+			{Address: "127.0.0.1", Type: "ipv4", Prefix: 8},
+			{Address: "::1", Type: "ipv6", Prefix: 128},
+		}},
+		{Name: "eth0", HardwareAddress: "bc:24:11:00:00:01", IPAddresses: []api.GuestIPAddress{
+			{Address: "10.0.0.10", Type: "ipv4", Prefix: 24},
+			{Address: "169.254.1.2", Type: "ipv4", Prefix: 16},
+			{Address: "2001:db8::10", Type: "ipv6", Prefix: 64},
+			{Address: "fe80::be24:11ff:fe00:1", Type: "ipv6", Prefix: 64},
+		}},
+		{Name: "eth1", HardwareAddress: "bc:24:11:00:00:02"},
+	}
+
+	addresses := newGuestAddresses(interfaces)
+	var model VirtualMachineResourceModel
+	addresses.setModel(&model)
+
+	expectedNames := types.ListValueMust(types.StringType, []attr.Value{types.StringValue("eth0"), types.StringValue("eth1")})
+	if !model.NetworkInterfaceNames.Equal(expectedNames) {
+		t.Errorf("Incorrect names. Expected %v, got %v", expectedNames, model.NetworkInterfaceNames)
+	}
+	expectedIPv4 := types.ListValueMust(types.ListType{ElemType: types.StringType}, []attr.Value{
+		types.ListValueMust(types.StringType, []attr.Value{types.StringValue("10.0.0.10")}),
+		types.ListValueMust(types.StringType, []attr.Value{}),
+	})
+	if !model.IPv4Addresses.Equal(expectedIPv4) {
+		t.Errorf("Incorrect IPv4 addresses. Expected %v, got %v", expectedIPv4, model.IPv4Addresses)
+	}
+	expectedIPv6 := types.ListValueMust(types.ListType{ElemType: types.StringType}, []attr.Value{
+		types.ListValueMust(types.StringType, []attr.Value{types.StringValue("2001:db8::10")}),
+		types.ListValueMust(types.StringType, []attr.Value{}),
+	})
+	if !model.IPv6Addresses.Equal(expectedIPv6) {
+		t.Errorf("Incorrect IPv6 addresses. Expected %v, got %v", expectedIPv6, model.IPv6Addresses)
+	}
+	if !addresses.hasAddress() {
+		t.Error("Expected the guest to have an address")
+	}
+
+	if newGuestAddresses(interfaces[:1]).hasAddress() {
+		t.Error("Expected only loopback addresses to not count as an address")
+	}
+}
+
+func TestReadAgent(t *testing.T) {
+	testCases := map[string]bool{"1": true, "0": false, "enabled=1,fstrim_cloned_disks=1": true, "enabled=0": false}
+	for value, expected := range testCases {
+		value := value
+		agent := readAgent(&value, nil)
+		if agent.Enabled.ValueBool() != expected {
+			t.Errorf("Incorrect enabled for %q. Expected %v, got %v", value, expected, agent.Enabled)
+		}
+	}
+
+	prior := &VirtualMachineAgentModel{Enabled: types.BoolValue(true), Timeout: types.Int64Value(60)}
+	agent := readAgent(nil, prior)
+	if agent.Enabled.ValueBool() || agent.Timeout.ValueInt64() != 60 {
+		t.Errorf("Expected a disabled agent with the prior timeout, got %+v", agent)
+	}
+
+	if readAgent(nil, nil) != nil {
+		t.Error("Expected no agent block when the agent is not configured")
+	}
+}
+
+func TestGuestAddressesMayChange(t *testing.T) {
+	agent := &VirtualMachineAgentModel{Enabled: types.BoolValue(true), Timeout: types.Int64Value(60)}
+	state := VirtualMachineResourceModel{
+		Started: types.BoolValue(true),
+		Agent:   agent,
+		NetworkDevices: []VirtualMachineNetworkDeviceModel{
+			{Model: types.StringValue("virtio"), Bridge: types.StringValue("vmbr0"), MAC: types.StringValue("BC:24:11:00:00:01")},
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		plan     VirtualMachineResourceModel
+		expected bool
+	}{
+		{"unchanged", state, false},
+		{"memory changed", VirtualMachineResourceModel{Started: state.Started, Agent: agent, NetworkDevices: state.NetworkDevices, Memory: types.Int64Value(4096)}, false},
+		{"bridge changed", VirtualMachineResourceModel{Started: state.Started, Agent: agent, NetworkDevices: []VirtualMachineNetworkDeviceModel{
+			{Model: types.StringValue("virtio"), Bridge: types.StringValue("vmbr1"), MAC: types.StringValue("BC:24:11:00:00:01")},
+		}}, true},
+		{"cloud-init added", VirtualMachineResourceModel{Started: state.Started, Agent: agent, NetworkDevices: state.NetworkDevices, Initialization: &VirtualMachineInitializationModel{Interface: types.StringValue("ide2")}}, true},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if changed := guestAddressesMayChange(testCase.plan, state); changed != testCase.expected {
+				t.Errorf("Expected %v, got %v", testCase.expected, changed)
+			}
+		})
+	}
+
+	stopped := state
+	stopped.Started = types.BoolValue(false)
+	if !guestAddressesMayChange(state, stopped) {
+		t.Error("Expected the addresses to be waited for when the virtual machine is started")
+	}
+}
@@ -0,0 +1,271 @@
+package provider
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"terraform-provider-proxmox/internal/api"
+	"time"
+)
+
+// VirtualMachineCloneModel The virtual machine or template that a virtual machine is cloned from.
+// The new virtual machine is created on the node of the resource.
+type VirtualMachineCloneModel struct {
+	SourceVMID types.Int64  `tfsdk:"source_vmid"`
+	SourceName types.String `tfsdk:"source_name"`
+	SourceNode types.String `tfsdk:"source_node"`
+	Full       types.Bool   `tfsdk:"full"`
+	Storage    types.String `tfsdk:"storage"`
+	Pool       types.String `tfsdk:"pool"`
+	Timeout    types.Int64  `tfsdk:"timeout"`
+}
+
+func virtualMachineCloneBlock() schema.SingleNestedBlock {
+	return schema.SingleNestedBlock{
+		Description: "Create the virtual machine by cloning another virtual machine or template, the rest of the configuration is applied to the clone",
+		Attributes: map[string]schema.Attribute{
+			"source_vmid": schema.Int64Attribute{
+				Optional:    true,
+				Description: "The VMID to clone. Exactly one of source_vmid and source_name must be set",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"source_name": schema.StringAttribute{
+				Optional:    true,
+				Description: "The name of the virtual machine to clone, it must be unique on the source node",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"source_node": schema.StringAttribute{
+				Optional:    true,
+				Description: "The node of the virtual machine to clone. Defaults to the node of the new virtual machine",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"full": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(true),
+				Description: "Copy the disks instead of creating a linked clone. Linked clones can only be made from templates",
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"storage": schema.StringAttribute{
+				Optional:    true,
+				Description: "The storage for the disks of a full clone. Defaults to the storage of the source disks",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"pool": schema.StringAttribute{
+				Optional:    true,
+				Description: "The resource pool to add the new virtual machine to",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"timeout": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(1800),
+				Description: "The number of seconds to wait for the clone to finish",
+			},
+		},
+	}
+}
+
+// validateClone checks the clone block when the configuration is validated
+func validateClone(clone *VirtualMachineCloneModel) diag.Diagnostics {
+	if clone == nil {
+		return nil
+	}
+	return validateCloneSource(clone.SourceVMID, clone.SourceName, clone.Full, clone.Storage)
+}
+
+// validateCloneSource checks the attributes that the clone blocks of virtual machines and containers share
+func validateCloneSource(sourceVMID types.Int64, sourceName types.String, full types.Bool, storage types.String) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if sourceVMID.IsUnknown() || sourceName.IsUnknown() {
+		return diags
+	}
+
+	if sourceVMID.IsNull() == sourceName.IsNull() {
+		diags.AddAttributeError(
+			path.Root("clone"),
+			"Invalid clone source",
+			"Exactly one of source_vmid and source_name must be set",
+		)
+	}
+	if !storage.IsNull() && !full.IsNull() && !full.IsUnknown() && !full.ValueBool() {
+		diags.AddAttributeError(
+			path.Root("clone").AtName("storage"),
+			"Storage requires a full clone",
+			"The volumes of a linked clone stay on the storage of the template, remove storage or set full to true",
+		)
+	}
+	return diags
+}
+
+// findVirtualMachineByName returns the VMID of the only virtual machine with the name
+func findVirtualMachineByName(virtualMachines []api.VirtualMachineStatus, name string) (int64, error) {
+	var matches []int64
+	for _, virtualMachine := range virtualMachines {
+		if virtualMachine.Name == name {
+			matches = append(matches, int64(virtualMachine.VMID))
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return 0, fmt.Errorf("no virtual machine is called %s", name)
+	case 1:
+		return matches[0], nil
+	default:
+		return 0, fmt.Errorf("%d virtual machines are called %s: %v", len(matches), name, matches)
+	}
+}
+
+// cloneVirtualMachine clones the source of the clone block to the VMID of the plan and waits for it to finish
+func (r *virtualMachineResource) cloneVirtualMachine(plan VirtualMachineResourceModel) error {
+	clone := plan.Clone
+	sourceNode := plan.Node.ValueString()
+	if !clone.SourceNode.IsNull() {
+		sourceNode = clone.SourceNode.ValueString()
+	}
+
+	sourceVMID := clone.SourceVMID.ValueInt64()
+	if clone.SourceVMID.IsNull() {
+		virtualMachines, err := api.GetVirtualMachines(r.client, sourceNode)
+		if err != nil {
+			return err
+		}
+		sourceVMID, err = findVirtualMachineByName(virtualMachines, clone.SourceName.ValueString())
+		if err != nil {
+			return fmt.Errorf("could not find the source on %s: %w", sourceNode, err)
+		}
+	}
+
+	cloneRequest := api.VirtualMachineCloneRequest{
+		NewID:   plan.VMID.ValueInt64(),
+		Name:    plan.Name.ValueStringPointer(),
+		Full:    api.NewBool(clone.Full.ValueBoolPointer()),
+		Storage: clone.Storage.ValueStringPointer(),
+		Pool:    clone.Pool.ValueStringPointer(),
+	}
+	if sourceNode != plan.Node.ValueString() {
+		cloneRequest.Target = plan.Node.ValueStringPointer()
+	}
+
+	upid, err := api.CloneVirtualMachine(r.client, sourceNode, sourceVMID, &cloneRequest)
+	if err != nil {
+		return err
+	}
+	return api.WaitForTask(r.client, upid, time.Duration(clone.Timeout.ValueInt64())*time.Second)
+}
+
+// sourceValue plans an attribute with a default that the configuration of a clone does not set. The clone keeps
+// the value of its source instead of the default, which is only known once the clone exists.
+func sourceValue[T attr.Value](value *T, config T, state T, unknown T, exists bool) {
+	if !config.IsNull() {
+		return
+	}
+	if exists {
+		*value = state
+	} else {
+		*value = unknown
+	}
+}
+
+// knownValue sets an attribute that is unknown until the clone exists to the value read back from the clone
+func knownValue[T attr.Value](value *T, clone T) {
+	if (*value).IsUnknown() {
+		*value = clone
+	}
+}
+
+// planCloneDefaults keeps the settings of the source of a clone that the configuration does not set
+func planCloneDefaults(plan *VirtualMachineResourceModel, config VirtualMachineResourceModel, state VirtualMachineResourceModel, exists bool) {
+	sourceValue(&plan.Cores, config.Cores, state.Cores, types.Int64Unknown(), exists)
+	sourceValue(&plan.Sockets, config.Sockets, state.Sockets, types.Int64Unknown(), exists)
+	sourceValue(&plan.Memory, config.Memory, state.Memory, types.Int64Unknown(), exists)
+	sourceValue(&plan.BIOS, config.BIOS, state.BIOS, types.StringUnknown(), exists)
+	sourceValue(&plan.OSType, config.OSType, state.OSType, types.StringUnknown(), exists)
+	sourceValue(&plan.OnBoot, config.OnBoot, state.OnBoot, types.BoolUnknown(), exists)
+}
+
+// setCloneDefaults sets the settings that planCloneDefaults left unknown to those of the clone
+func setCloneDefaults(plan *VirtualMachineResourceModel, clone VirtualMachineResourceModel) {
+	knownValue(&plan.Cores, clone.Cores)
+	knownValue(&plan.Sockets, clone.Sockets)
+	knownValue(&plan.Memory, clone.Memory)
+	knownValue(&plan.BIOS, clone.BIOS)
+	knownValue(&plan.OSType, clone.OSType)
+	knownValue(&plan.OnBoot, clone.OnBoot)
+}
+
+// keepDeclared leaves the settings and devices of a clone that the prior model does not declare out of the model.
+// They come from the source of the clone and are not managed, so they are neither shown as changes nor removed.
+// Devices that are declared by their position keep the first devices of the clone.
+func keepDeclared(model *VirtualMachineResourceModel, prior VirtualMachineResourceModel) {
+	if prior.Name.IsNull() {
+		model.Name = types.StringNull()
+	}
+	if prior.CPUType.IsNull() {
+		model.CPUType = types.StringNull()
+	}
+	if prior.Machine.IsNull() {
+		model.Machine = types.StringNull()
+	}
+	if prior.Description.IsNull() {
+		model.Description = types.StringNull()
+	}
+	if prior.Balloon.IsNull() {
+		model.Balloon = types.Int64Null()
+	}
+	if prior.Tags.IsNull() {
+		model.Tags = types.SetNull(types.StringType)
+	}
+
+	declared := map[string]bool{}
+	for _, disk := range prior.Disks {
+		declared[disk.Interface.ValueString()] = true
+	}
+	disks := []VirtualMachineDiskModel{}
+	for _, disk := range model.Disks {
+		if declared[disk.Interface.ValueString()] {
+			disks = append(disks, disk)
+		}
+	}
+	model.Disks = disks
+
+	model.NetworkDevices = model.NetworkDevices[:min(len(model.NetworkDevices), len(prior.NetworkDevices))]
+	model.HostPCI = model.HostPCI[:min(len(model.HostPCI), len(prior.HostPCI))]
+	model.USB = model.USB[:min(len(model.USB), len(prior.USB))]
+	model.Serial = model.Serial[:min(len(model.Serial), len(prior.Serial))]
+
+	if prior.EFIDisk == nil {
+		model.EFIDisk = nil
+	}
+	if prior.TPMState == nil {
+		model.TPMState = nil
+	}
+	if prior.Agent == nil {
+		model.Agent = nil
+	}
+	if prior.Initialization == nil {
+		model.Initialization = nil
+	}
+}
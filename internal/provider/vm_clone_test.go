@@ -0,0 +1,193 @@
+package provider
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"reflect"
+	"terraform-provider-proxmox/internal/api"
+	"testing"
+)
+
+func TestFindVirtualMachineByName(t *testing.T) {
+	virtualMachines := []api.VirtualMachineStatus{
+		{VMID: 9000, Name: "debian-template"},
+		{VMID: 100, Name: "web"},
+		{VMID: 101, Name: "web"},
+	}
+
+	vmid, err := findVirtualMachineByName(virtualMachines, "debian-template")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vmid != 9000 {
+		t.Errorf("Incorrect VMID returned. Expected 9000, got %d", vmid)
+	}
+
+	_, err = findVirtualMachineByName(virtualMachines, "web")
+	if err == nil {
+		t.Error("Expected an error when more than one virtual machine has the name")
+	}
+
+	_, err = findVirtualMachineByName(virtualMachines, "db")
+	if err == nil {
+		t.Error("Expected an error when no virtual machine has the name")
+	}
+}
+
+func TestValidateClone(t *testing.T) {
+	clone := VirtualMachineCloneModel{
+		SourceVMID: types.Int64Value(9000),
+		SourceName: types.StringNull(),
+		Full:       types.BoolNull(),
+		Storage:    types.StringValue("local-lvm"),
+	}
+	if diags := validateClone(&clone); diags.HasError() {
+		t.Errorf("Expected the clone to be valid, got %v", diags)
+	}
+
+	clone.Full = types.BoolValue(false)
+	if diags := validateClone(&clone); diags.ErrorsCount() != 1 {
+		t.Errorf("Expected an error for storage on a linked clone, got %v", diags)
+	}
+
+	clone.Full = types.BoolNull()
+	clone.SourceName = types.StringValue("debian-template")
+	if diags := validateClone(&clone); diags.ErrorsCount() != 1 {
+		t.Errorf("Expected an error when both sources are set, got %v", diags)
+	}
+
+	if diags := validateClone(nil); diags.HasError() {
+		t.Errorf("Expected no errors without a clone block, got %v", diags)
+	}
+}
+
+func TestCloneChanges(t *testing.T) {
+	memory := int64(2048)
+	tests := []struct {
+		name      string
+		configure func(config *VirtualMachineResourceModel)
+		expected  api.VirtualMachineRequest
+	}{
+		{
+			name:      "minimal configuration",
+			configure: func(config *VirtualMachineResourceModel) {},
+			expected:  api.VirtualMachineRequest{Devices: map[string]string{}},
+		},
+		{
+			name: "configured memory and network device",
+			configure: func(config *VirtualMachineResourceModel) {
+				config.Memory = types.Int64Value(memory)
+				config.NetworkDevices = []VirtualMachineNetworkDeviceModel{{
+					Model:    types.StringValue("virtio"),
+					Bridge:   types.StringValue("vmbr2"),
+					Trunks:   types.ListNull(types.Int64Type),
+					Firewall: types.BoolValue(false),
+					MAC:      types.StringUnknown(),
+					LinkDown: types.BoolValue(false),
+				}}
+			},
+			expected: api.VirtualMachineRequest{
+				Memory:  &memory,
+				Devices: map[string]string{"net0": "bridge=vmbr2,macaddr=BC:24:11:2E:61:1A,model=virtio"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := VirtualMachineResourceModel{
+				Node:           types.StringValue("pve"),
+				VMID:           types.Int64Value(100),
+				Clone:          &VirtualMachineCloneModel{SourceVMID: types.Int64Value(9000)},
+				Disks:          []VirtualMachineDiskModel{},
+				NetworkDevices: []VirtualMachineNetworkDeviceModel{},
+			}
+			test.configure(&config)
+
+			// The framework plans the defaults of the schema for the attributes that are not configured
+			plan := config
+			for _, value := range []*types.Int64{&plan.Cores, &plan.Sockets} {
+				if value.IsNull() {
+					*value = types.Int64Value(1)
+				}
+			}
+			if plan.Memory.IsNull() {
+				plan.Memory = types.Int64Value(512)
+			}
+			plan.BIOS = types.StringValue("seabios")
+			plan.OSType = types.StringValue("other")
+			plan.OnBoot = types.BoolValue(false)
+			plan.Boot = types.StringUnknown()
+			planCloneDefaults(&plan, config, VirtualMachineResourceModel{}, false)
+
+			clone := readTemplateClone(t, plan)
+			setCloneDefaults(&plan, clone)
+			if plan.Cores.ValueInt64() != 4 || plan.BIOS.ValueString() != "ovmf" || !plan.OnBoot.ValueBool() {
+				t.Errorf("Expected the settings of the template, got %+v", plan)
+			}
+
+			changes, diags, err := planVirtualMachineChanges(context.Background(), plan, clone)
+			if err != nil || diags.HasError() {
+				t.Fatal(err, diags)
+			}
+			if !reflect.DeepEqual(changes.request, test.expected) {
+				t.Errorf("Incorrect request. Expected %+v, got %+v", test.expected, changes.request)
+			}
+			if len(changes.disks.removed) > 0 || len(changes.disks.removedVolumes) > 0 || changes.initialization.changed() {
+				t.Errorf("Expected the devices of the template to be kept, got %+v", changes)
+			}
+		})
+	}
+}
+
+// readTemplateClone reads a clone of a template with a disk, an EFI disk, two network devices and cloud-init like
+// readVirtualMachine does
+func readTemplateClone(t *testing.T, plan VirtualMachineResourceModel) VirtualMachineResourceModel {
+	description, agent := "Debian 12", "1"
+	config := api.VirtualMachineConfig{
+		Description: &description,
+		Devices: map[string]string{
+			"scsi0":     "local-lvm:vm-100-disk-1,iothread=1,size=8G",
+			"efidisk0":  "local-lvm:vm-100-disk-0,efitype=4m,pre-enrolled-keys=1,size=4M",
+			"net0":      "virtio=BC:24:11:2E:61:1A,bridge=vmbr0",
+			"net1":      "virtio=BC:24:11:2E:61:1B,bridge=vmbr1,tag=20",
+			"ide2":      "local-lvm:vm-100-cloudinit,media=cdrom",
+			"ipconfig0": "ip=dhcp",
+		},
+	}
+
+	clone := plan
+	clone.Name = types.StringValue("debian-template")
+	clone.Cores = types.Int64Value(4)
+	clone.Sockets = types.Int64Value(1)
+	clone.Memory = types.Int64Value(4096)
+	clone.Balloon = types.Int64Value(1024)
+	clone.CPUType = types.StringValue("host")
+	clone.Machine = types.StringValue("q35")
+	clone.BIOS = types.StringValue("ovmf")
+	clone.Boot = types.StringValue("order=scsi0")
+	clone.OSType = types.StringValue("l26")
+	clone.OnBoot = types.BoolValue(true)
+	clone.Description = types.StringValue(description)
+	clone.Tags = types.SetValueMust(types.StringType, []attr.Value{types.StringValue("debian")})
+	clone.Agent = readAgent(&agent, nil)
+
+	var err error
+	clone.Disks, err = readDisks(config.Devices, plan.Disks)
+	if err == nil {
+		clone.NetworkDevices, err = readNetworkDevices(config.Devices)
+	}
+	if err == nil {
+		clone.Initialization, err = readInitialization(config, nil)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	readPassthrough(config.Devices, &clone)
+	clone.EFIDisk = readEFIDisk(config.Devices)
+	clone.TPMState = readTPMState(config.Devices)
+
+	keepDeclared(&clone, plan)
+	return clone
+}
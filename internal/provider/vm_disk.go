@@ -0,0 +1,335 @@
+package provider
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"terraform-provider-proxmox/internal/api"
+)
+
+// diskInterface matches the configuration keys that can hold a virtual machine disk
+var diskInterface = regexp.MustCompile(`^(scsi|virtio|sata|ide)\d+$`)
+
+type VirtualMachineDiskModel struct {
+	Interface types.String `tfsdk:"interface"`
+	Storage   types.String `tfsdk:"storage"`
+	Size      types.Int64  `tfsdk:"size"`
+	Cache     types.String `tfsdk:"cache"`
+	Discard   types.Bool   `tfsdk:"discard"`
+	IOThread  types.Bool   `tfsdk:"iothread"`
+	SSD       types.Bool   `tfsdk:"ssd"`
+	Backup    types.Bool   `tfsdk:"backup"`
+	Replicate types.Bool   `tfsdk:"replicate"`
+	Volume    types.String `tfsdk:"volume"`
+}
+
+func virtualMachineDiskBlock() schema.ListNestedBlock {
+	return schema.ListNestedBlock{
+		NestedObject: schema.NestedBlockObject{
+			Attributes: map[string]schema.Attribute{
+				"interface": schema.StringAttribute{
+					Required:    true,
+					Description: "The bus and slot of the disk, for example scsi0, virtio1, sata0 or ide0",
+				},
+				"storage": schema.StringAttribute{
+					Required:    true,
+					Description: "Changing the storage moves the disk",
+				},
+				"size": schema.Int64Attribute{
+					Required:    true,
+					Description: "The size in GiB. Disks can grow in place but can not shrink",
+				},
+				"cache": schema.StringAttribute{
+					Optional:    true,
+					Description: "One of none, directsync, writethrough, writeback or unsafe",
+				},
+				"discard": schema.BoolAttribute{
+					Optional: true,
+					Computed: true,
+					Default:  booldefault.StaticBool(false),
+				},
+				"iothread": schema.BoolAttribute{
+					Optional: true,
+					Computed: true,
+					Default:  booldefault.StaticBool(false),
+				},
+				"ssd": schema.BoolAttribute{
+					Optional: true,
+					Computed: true,
+					Default:  booldefault.StaticBool(false),
+				},
+				"backup": schema.BoolAttribute{
+					Optional: true,
+					Computed: true,
+					Default:  booldefault.StaticBool(true),
+				},
+				"replicate": schema.BoolAttribute{
+					Optional: true,
+					Computed: true,
+					Default:  booldefault.StaticBool(true),
+				},
+				"volume": schema.StringAttribute{
+					Computed:    true,
+					Description: "The volume that backs the disk, for example local-lvm:vm-100-disk-0",
+				},
+			},
+		},
+	}
+}
+
+// diskOptions returns the properties of the disk other than the volume and size
+func diskOptions(disk VirtualMachineDiskModel) api.PropertyString {
+	properties := api.PropertyString{}
+	if !disk.Cache.IsNull() {
+		properties["cache"] = disk.Cache.ValueString()
+	}
+	if disk.Discard.ValueBool() {
+		properties["discard"] = "on"
+	}
+	if disk.IOThread.ValueBool() {
+		properties.SetFlag("iothread", true)
+	}
+	if disk.SSD.ValueBool() {
+		properties.SetFlag("ssd", true)
+	}
+	if !disk.Backup.IsNull() && !disk.Backup.ValueBool() {
+		properties.SetFlag("backup", false)
+	}
+	if !disk.Replicate.IsNull() && !disk.Replicate.ValueBool() {
+		properties.SetFlag("replicate", false)
+	}
+	return properties
+}
+
+// newDiskValue is the property string that allocates a new volume, for example local-lvm:32,ssd=1
+func newDiskValue(disk VirtualMachineDiskModel) string {
+	properties := diskOptions(disk)
+	properties[""] = fmt.Sprintf("%s:%d", disk.Storage.ValueString(), disk.Size.ValueInt64())
+	return properties.String()
+}
+
+// existingDiskValue is the property string that changes the options of an existing volume
+func existingDiskValue(disk VirtualMachineDiskModel, volume string) string {
+	properties := diskOptions(disk)
+	properties[""] = volume
+	return properties.String()
+}
+
+// diskSizeGiB converts a Proxmox size such as 32G, 512M or 1T into GiB, rounding up
+func diskSizeGiB(size string) (int64, error) {
+	if size == "" {
+		return 0, nil
+	}
+
+	multipliers := map[byte]float64{'K': 1.0 / (1024 * 1024), 'M': 1.0 / 1024, 'G': 1, 'T': 1024}
+	multiplier := 1.0 / (1024 * 1024 * 1024)
+	number := size
+	if value, ok := multipliers[size[len(size)-1]]; ok {
+		multiplier = value
+		number = size[:len(size)-1]
+	}
+
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, fmt.Errorf("the disk size %q is not valid: %w", size, err)
+	}
+	return int64(math.Ceil(value * multiplier)), nil
+}
+
+// isDisk reports whether a device entry is a disk rather than a CD-ROM drive or cloud-init drive
+func isDisk(key string, value string) bool {
+	if !diskInterface.MatchString(key) {
+		return false
+	}
+	properties := api.ParsePropertyString(value)
+	return properties["media"] != "cdrom" && !strings.Contains(properties[""], "cloudinit")
+}
+
+// readDisks converts the disks in the configuration into the model. The disks are returned in the
+// order of the prior disks so that reading does not reorder the list, followed by any new disks.
+func readDisks(devices map[string]string, prior []VirtualMachineDiskModel) ([]VirtualMachineDiskModel, error) {
+	var interfaces []string
+	seen := map[string]bool{}
+	for _, disk := range prior {
+		key := disk.Interface.ValueString()
+		if value, ok := devices[key]; ok && isDisk(key, value) && !seen[key] {
+			interfaces = append(interfaces, key)
+			seen[key] = true
+		}
+	}
+	var others []string
+	for key, value := range devices {
+		if isDisk(key, value) && !seen[key] {
+			others = append(others, key)
+		}
+	}
+	sort.Strings(others)
+	interfaces = append(interfaces, others...)
+
+	disks := []VirtualMachineDiskModel{}
+	for _, key := range interfaces {
+		properties := api.ParsePropertyString(devices[key])
+		volume := properties[""]
+		storage, _, _ := strings.Cut(volume, ":")
+
+		size, err := diskSizeGiB(properties["size"])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+
+		disk := VirtualMachineDiskModel{
+			Interface: types.StringValue(key),
+			Storage:   types.StringValue(storage),
+			Size:      types.Int64Value(size),
+			Cache:     types.StringNull(),
+			Discard:   types.BoolValue(properties["discard"] == "on"),
+			IOThread:  types.BoolValue(properties.Flag("iothread", false)),
+			SSD:       types.BoolValue(properties.Flag("ssd", false)),
+			Backup:    types.BoolValue(properties.Flag("backup", true)),
+			Replicate: types.BoolValue(properties.Flag("replicate", true)),
+			Volume:    types.StringValue(volume),
+		}
+		if cache, ok := properties["cache"]; ok {
+			disk.Cache = types.StringValue(cache)
+		}
+		disks = append(disks, disk)
+	}
+	return disks, nil
+}
+
+// diskChanges describes how the disks of an existing virtual machine need to change
+type diskChanges struct {
+	// devices are the configuration entries for new disks and disks with changed options
+	devices map[string]string
+	// removed are the interfaces of disks that should be detached
+	removed []string
+	// removedVolumes are the volumes of the detached disks, they become unused disks
+	removedVolumes []string
+	// moves maps the interface to the new storage
+	moves map[string]string
+	// resizes maps the interface to the new size in GiB
+	resizes map[string]int64
+}
+
+func planDiskChanges(plan []VirtualMachineDiskModel, state []VirtualMachineDiskModel) diskChanges {
+	changes := diskChanges{devices: map[string]string{}, moves: map[string]string{}, resizes: map[string]int64{}}
+
+	current := map[string]VirtualMachineDiskModel{}
+	for _, disk := range state {
+		current[disk.Interface.ValueString()] = disk
+	}
+
+	planned := map[string]bool{}
+	for _, disk := range plan {
+		key := disk.Interface.ValueString()
+		planned[key] = true
+
+		existing, ok := current[key]
+		if !ok {
+			changes.devices[key] = newDiskValue(disk)
+			continue
+		}
+
+		if diskOptions(disk).String() != diskOptions(existing).String() {
+			changes.devices[key] = existingDiskValue(disk, existing.Volume.ValueString())
+		}
+		if disk.Storage.ValueString() != existing.Storage.ValueString() {
+			changes.moves[key] = disk.Storage.ValueString()
+		}
+		if disk.Size.ValueInt64() > existing.Size.ValueInt64() {
+			changes.resizes[key] = disk.Size.ValueInt64()
+		}
+	}
+
+	for _, disk := range state {
+		key := disk.Interface.ValueString()
+		if !planned[key] {
+			changes.removed = append(changes.removed, key)
+			changes.removedVolumes = append(changes.removedVolumes, disk.Volume.ValueString())
+		}
+	}
+	sort.Strings(changes.removed)
+
+	return changes
+}
+
+// unusedDisks returns the unusedN entries that point at one of the volumes
+func unusedDisks(devices map[string]string, volumes []string) []string {
+	var keys []string
+	for key, value := range devices {
+		if !strings.HasPrefix(key, "unused") {
+			continue
+		}
+		for _, volume := range volumes {
+			if api.ParsePropertyString(value)[""] == volume {
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// validateDiskPlan rejects disk changes that would lose data. Shrinking a disk is not supported by
+// Proxmox and recreating it would destroy its contents, so it is reported when planning.
+func validateDiskPlan(plan []VirtualMachineDiskModel, state []VirtualMachineDiskModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	current := map[string]VirtualMachineDiskModel{}
+	for _, disk := range state {
+		current[disk.Interface.ValueString()] = disk
+	}
+
+	seen := map[string]bool{}
+	for i, disk := range plan {
+		if disk.Interface.IsUnknown() || disk.Size.IsUnknown() {
+			continue
+		}
+		key := disk.Interface.ValueString()
+		diskPath := path.Root("disk").AtListIndex(i)
+
+		if !diskInterface.MatchString(key) {
+			diags.AddAttributeError(diskPath.AtName("interface"), "Invalid disk interface",
+				fmt.Sprintf("The interface %q must be scsi, virtio, sata or ide followed by a number, for example scsi0", key))
+			continue
+		}
+		if seen[key] {
+			diags.AddAttributeError(diskPath.AtName("interface"), "Duplicate disk interface",
+				fmt.Sprintf("The interface %s is used by more than one disk", key))
+		}
+		seen[key] = true
+
+		existing, ok := current[key]
+		if ok && disk.Size.ValueInt64() < existing.Size.ValueInt64() {
+			diags.AddAttributeError(diskPath.AtName("size"), "Disks can not shrink",
+				fmt.Sprintf("The disk %s can not shrink from %dG to %dG. Proxmox only supports growing disks and recreating it would destroy its data", key, existing.Size.ValueInt64(), disk.Size.ValueInt64()))
+		}
+	}
+	return diags
+}
+
+// planDiskVolumes keeps the known volume of disks that stay on the same storage, so that the plan
+// only shows the volume as unknown for new and moved disks
+func planDiskVolumes(plan []VirtualMachineDiskModel, state []VirtualMachineDiskModel) {
+	current := map[string]VirtualMachineDiskModel{}
+	for _, disk := range state {
+		current[disk.Interface.ValueString()] = disk
+	}
+
+	for i, disk := range plan {
+		existing, ok := current[disk.Interface.ValueString()]
+		if ok && !disk.Storage.IsUnknown() && disk.Storage.ValueString() == existing.Storage.ValueString() {
+			plan[i].Volume = existing.Volume
+		} else {
+			plan[i].Volume = types.StringUnknown()
+		}
+	}
+}
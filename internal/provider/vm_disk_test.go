@@ -0,0 +1,145 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"reflect"
+	"testing"
+)
+
+func TestDiskSizeGiB(t *testing.T) {
+	testCases := map[string]int64{"32G": 32, "1T": 1024, "512M": 1, "2048M": 2, "": 0}
+	for size, expected := range testCases {
+		actual, err := diskSizeGiB(size)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual != expected {
+			t.Errorf("Incorrect size for %q. Expected %d, got %d", size, expected, actual)
+		}
+	}
+
+	_, err := diskSizeGiB("large")
+	if err == nil {
+		t.Error("Expected an error for an invalid size")
+	}
+}
+
+func TestReadDisks(t *testing.T) {
+	devices := map[string]string{
+		"scsi0": "local-lvm:vm-100-disk-0,cache=writeback,discard=on,size=32G,ssd=1",
+		"sata1": "nfs:100/vm-100-disk-1.qcow2,backup=0,size=10G",
+		"ide2":  "local:iso/debian.iso,media=cdrom",
+		"net0":  "virtio=BC:24:11:00:00:01,bridge=vmbr0",
+	}
+
+	prior := []VirtualMachineDiskModel{{Interface: types.StringValue("sata1")}, {Interface: types.StringValue("scsi0")}}
+	disks, err := readDisks(devices, prior)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(disks) != 2 {
+		t.Fatalf("Expected 2 disks, got %d", len(disks))
+	}
+	if disks[0].Interface.ValueString() != "sata1" || disks[1].Interface.ValueString() != "scsi0" {
+		t.Errorf("Expected the disks to keep the prior order, got %v and %v", disks[0].Interface, disks[1].Interface)
+	}
+	if disks[0].Backup.ValueBool() || disks[0].Storage.ValueString() != "nfs" {
+		t.Errorf("Incorrect sata1 disk returned: %+v", disks[0])
+	}
+	scsi0 := disks[1]
+	if scsi0.Cache.ValueString() != "writeback" || !scsi0.Discard.ValueBool() || !scsi0.SSD.ValueBool() || scsi0.Size.ValueInt64() != 32 {
+		t.Errorf("Incorrect scsi0 disk returned: %+v", scsi0)
+	}
+	if scsi0.Volume.ValueString() != "local-lvm:vm-100-disk-0" {
+		t.Errorf("Incorrect volume returned: %v", scsi0.Volume)
+	}
+}
+
+func TestPlanDiskChanges(t *testing.T) {
+	state := []VirtualMachineDiskModel{
+		{Interface: types.StringValue("scsi0"), Storage: types.StringValue("local-lvm"), Size: types.Int64Value(32), Volume: types.StringValue("local-lvm:vm-100-disk-0")},
+		{Interface: types.StringValue("scsi1"), Storage: types.StringValue("local-lvm"), Size: types.Int64Value(10), Volume: types.StringValue("local-lvm:vm-100-disk-1")},
+		{Interface: types.StringValue("scsi2"), Storage: types.StringValue("local-lvm"), Size: types.Int64Value(10), Volume: types.StringValue("local-lvm:vm-100-disk-2")},
+	}
+	plan := []VirtualMachineDiskModel{
+		{Interface: types.StringValue("scsi0"), Storage: types.StringValue("local-lvm"), Size: types.Int64Value(64), SSD: types.BoolValue(true), Volume: types.StringValue("local-lvm:vm-100-disk-0")},
+		{Interface: types.StringValue("scsi1"), Storage: types.StringValue("ceph"), Size: types.Int64Value(10), Volume: types.StringValue("local-lvm:vm-100-disk-1")},
+		{Interface: types.StringValue("virtio0"), Storage: types.StringValue("local-lvm"), Size: types.Int64Value(8), Volume: types.StringUnknown()},
+	}
+
+	changes := planDiskChanges(plan, state)
+
+	expectedDevices := map[string]string{
+		"scsi0":   "local-lvm:vm-100-disk-0,ssd=1",
+		"virtio0": "local-lvm:8",
+	}
+	if !reflect.DeepEqual(changes.devices, expectedDevices) {
+		t.Errorf("Incorrect devices. Expected %v, got %v", expectedDevices, changes.devices)
+	}
+	if !reflect.DeepEqual(changes.resizes, map[string]int64{"scsi0": 64}) {
+		t.Errorf("Incorrect resizes: %v", changes.resizes)
+	}
+	if !reflect.DeepEqual(changes.moves, map[string]string{"scsi1": "ceph"}) {
+		t.Errorf("Incorrect moves: %v", changes.moves)
+	}
+	if !reflect.DeepEqual(changes.removed, []string{"scsi2"}) || !reflect.DeepEqual(changes.removedVolumes, []string{"local-lvm:vm-100-disk-2"}) {
+		t.Errorf("Incorrect removals: %v %v", changes.removed, changes.removedVolumes)
+	}
+}
+
+func TestValidateDiskPlan(t *testing.T) {
+	state := []VirtualMachineDiskModel{{Interface: types.StringValue("scsi0"), Storage: types.StringValue("local-lvm"), Size: types.Int64Value(32)}}
+
+	testCases := []struct {
+		name   string
+		plan   []VirtualMachineDiskModel
+		state  []VirtualMachineDiskModel
+		errors int
+	}{
+		{
+			name:   "shrink",
+			plan:   []VirtualMachineDiskModel{{Interface: types.StringValue("scsi0"), Storage: types.StringValue("local-lvm"), Size: types.Int64Value(16)}},
+			state:  state,
+			errors: 1,
+		},
+		{
+			name: "invalid interface",
+			plan: []VirtualMachineDiskModel{
+				{Interface: types.StringValue("scsi0"), Storage: types.StringValue("local-lvm"), Size: types.Int64Value(64)},
+				{Interface: types.StringValue("nvme0"), Storage: types.StringValue("local-lvm"), Size: types.Int64Value(8)},
+			},
+			state:  state,
+			errors: 1,
+		},
+		{
+			name: "duplicate interface",
+			plan: []VirtualMachineDiskModel{
+				{Interface: types.StringValue("scsi0"), Storage: types.StringValue("local-lvm"), Size: types.Int64Value(64)},
+				{Interface: types.StringValue("scsi0"), Storage: types.StringValue("local-lvm"), Size: types.Int64Value(8)},
+			},
+			errors: 1,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			diags := validateDiskPlan(testCase.plan, testCase.state)
+			if diags.ErrorsCount() != testCase.errors {
+				t.Errorf("Expected %d errors, got %v", testCase.errors, diags)
+			}
+		})
+	}
+}
+
+func TestUnusedDisks(t *testing.T) {
+	devices := map[string]string{
+		"unused0": "local-lvm:vm-100-disk-1",
+		"unused1": "local-lvm:vm-100-disk-2",
+		"scsi0":   "local-lvm:vm-100-disk-0,size=32G",
+	}
+	unused := unusedDisks(devices, []string{"local-lvm:vm-100-disk-2"})
+	if !reflect.DeepEqual(unused, []string{"unused1"}) {
+		t.Errorf("Incorrect unused disks returned: %v", unused)
+	}
+}
@@ -0,0 +1,241 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"strings"
+	"terraform-provider-proxmox/internal/api"
+)
+
+// The EFI variables and the TPM state are small volumes that Proxmox creates with the virtual machine. Their
+// options can not be changed afterwards and recreating them loses the enrolled keys or the secrets sealed in the
+// TPM, such as BitLocker keys, so any change replaces the virtual machine.
+
+// VirtualMachineEFIDiskModel The volume that holds the EFI variables of an OVMF virtual machine
+type VirtualMachineEFIDiskModel struct {
+	Storage         types.String `tfsdk:"storage"`
+	EFIType         types.String `tfsdk:"efitype"`
+	PreEnrolledKeys types.Bool   `tfsdk:"pre_enrolled_keys"`
+	Volume          types.String `tfsdk:"volume"`
+}
+
+// VirtualMachineTPMStateModel The volume that holds the state of the emulated TPM
+type VirtualMachineTPMStateModel struct {
+	Storage types.String `tfsdk:"storage"`
+	Version types.String `tfsdk:"version"`
+	Volume  types.String `tfsdk:"volume"`
+}
+
+func virtualMachineEFIDiskBlock() schema.SingleNestedBlock {
+	return schema.SingleNestedBlock{
+		Description: "The EFI disk, which needs bios to be ovmf. Changes replace the virtual machine",
+		PlanModifiers: []planmodifier.Object{
+			requiresReplaceWhenRemoved(),
+		},
+		Attributes: map[string]schema.Attribute{
+			"storage": schema.StringAttribute{
+				Optional:    true,
+				Description: "The storage of the EFI disk, for example local-lvm. Required when the block is set",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"efitype": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString("4m"),
+				Description: "The size of the EFI variable store, either 2m or 4m. Secure boot needs 4m",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"pre_enrolled_keys": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Enroll the Microsoft and distribution keys, which enables secure boot",
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"volume": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func virtualMachineTPMStateBlock() schema.SingleNestedBlock {
+	return schema.SingleNestedBlock{
+		Description: "The state of the emulated TPM. Changes replace the virtual machine",
+		PlanModifiers: []planmodifier.Object{
+			requiresReplaceWhenRemoved(),
+		},
+		Attributes: map[string]schema.Attribute{
+			"storage": schema.StringAttribute{
+				Optional:    true,
+				Description: "The storage of the TPM state, for example local-lvm. Required when the block is set",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"version": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString("v2.0"),
+				Description: "The TPM version, either v1.2 or v2.0. Windows 11 needs v2.0",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"volume": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+// requiresReplaceWhenRemoved replaces the virtual machine when the block is removed. The attributes replace it
+// when the block is added or changed, but their plan modifiers do not run once the block is gone.
+func requiresReplaceWhenRemoved() planmodifier.Object {
+	return objectplanmodifier.RequiresReplaceIf(
+		func(_ context.Context, request planmodifier.ObjectRequest, response *objectplanmodifier.RequiresReplaceIfFuncResponse) {
+			response.RequiresReplace = request.PlanValue.IsNull() && !request.StateValue.IsNull()
+		},
+		"Removing the block replaces the virtual machine",
+		"Removing the block replaces the virtual machine",
+	)
+}
+
+// efiDiskOptions returns the properties of the EFI disk other than the volume
+func efiDiskOptions(efiDisk *VirtualMachineEFIDiskModel) api.PropertyString {
+	properties := api.PropertyString{"efitype": efiDisk.EFIType.ValueString()}
+	properties.SetFlag("pre-enrolled-keys", efiDisk.PreEnrolledKeys.ValueBool())
+	return properties
+}
+
+// newEFIDiskValue is the property string that allocates the EFI disk, for example local-lvm:1,efitype=4m
+func newEFIDiskValue(efiDisk *VirtualMachineEFIDiskModel) string {
+	properties := efiDiskOptions(efiDisk)
+	properties[""] = efiDisk.Storage.ValueString() + ":1"
+	return properties.String()
+}
+
+// newTPMStateValue is the property string that allocates the TPM state, for example local-lvm:1,version=v2.0
+func newTPMStateValue(tpmState *VirtualMachineTPMStateModel) string {
+	properties := api.PropertyString{"": tpmState.Storage.ValueString() + ":1", "version": tpmState.Version.ValueString()}
+	return properties.String()
+}
+
+func readEFIDisk(devices map[string]string) *VirtualMachineEFIDiskModel {
+	value, ok := devices["efidisk0"]
+	if !ok {
+		return nil
+	}
+
+	// Proxmox leaves out the options that are at their default
+	properties := api.ParsePropertyString(value)
+	storage, _, _ := strings.Cut(properties[""], ":")
+	efiType := "2m"
+	if properties["efitype"] != "" {
+		efiType = properties["efitype"]
+	}
+	return &VirtualMachineEFIDiskModel{
+		Storage:         types.StringValue(storage),
+		EFIType:         types.StringValue(efiType),
+		PreEnrolledKeys: types.BoolValue(properties.Flag("pre-enrolled-keys", false)),
+		Volume:          types.StringValue(properties[""]),
+	}
+}
+
+func readTPMState(devices map[string]string) *VirtualMachineTPMStateModel {
+	value, ok := devices["tpmstate0"]
+	if !ok {
+		return nil
+	}
+
+	properties := api.ParsePropertyString(value)
+	storage, _, _ := strings.Cut(properties[""], ":")
+	version := "v1.2"
+	if properties["version"] != "" {
+		version = properties["version"]
+	}
+	return &VirtualMachineTPMStateModel{
+		Storage: types.StringValue(storage),
+		Version: types.StringValue(version),
+		Volume:  types.StringValue(properties[""]),
+	}
+}
+
+// planEFIChanges adds and removes the EFI disk and TPM state. Changes to their options replace the virtual machine,
+// so an existing volume only changes when a virtual machine is cloned from a template that has one. It can then be
+// moved to the planned storage, but its options can not change.
+func planEFIChanges(plan VirtualMachineResourceModel, state VirtualMachineResourceModel, changes *diskChanges) error {
+	switch {
+	case plan.EFIDisk != nil && state.EFIDisk == nil:
+		changes.devices["efidisk0"] = newEFIDiskValue(plan.EFIDisk)
+	case plan.EFIDisk == nil && state.EFIDisk != nil:
+		changes.removed = append(changes.removed, "efidisk0")
+		changes.removedVolumes = append(changes.removedVolumes, state.EFIDisk.Volume.ValueString())
+	case plan.EFIDisk != nil:
+		if efiDiskOptions(plan.EFIDisk).String() != efiDiskOptions(state.EFIDisk).String() {
+			return fmt.Errorf("the options of the existing EFI disk %s can not be changed", state.EFIDisk.Volume.ValueString())
+		}
+		if plan.EFIDisk.Storage.ValueString() != state.EFIDisk.Storage.ValueString() {
+			changes.moves["efidisk0"] = plan.EFIDisk.Storage.ValueString()
+		}
+	}
+
+	switch {
+	case plan.TPMState != nil && state.TPMState == nil:
+		changes.devices["tpmstate0"] = newTPMStateValue(plan.TPMState)
+	case plan.TPMState == nil && state.TPMState != nil:
+		changes.removed = append(changes.removed, "tpmstate0")
+		changes.removedVolumes = append(changes.removedVolumes, state.TPMState.Volume.ValueString())
+	case plan.TPMState != nil:
+		if plan.TPMState.Version.ValueString() != state.TPMState.Version.ValueString() {
+			return fmt.Errorf("the version of the existing TPM state %s can not be changed", state.TPMState.Volume.ValueString())
+		}
+		if plan.TPMState.Storage.ValueString() != state.TPMState.Storage.ValueString() {
+			changes.moves["tpmstate0"] = plan.TPMState.Storage.ValueString()
+		}
+	}
+	return nil
+}
+
+// validateEFI checks the EFI disk and TPM state when the configuration is validated
+func validateEFI(config VirtualMachineResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if config.EFIDisk != nil {
+		if config.EFIDisk.Storage.IsNull() {
+			diags.AddAttributeError(path.Root("efidisk0").AtName("storage"), "Missing EFI disk storage", "The storage of the EFI disk must be set, for example local-lvm")
+		}
+		// The bios defaults to seabios when it is not set
+		if !config.BIOS.IsUnknown() && config.BIOS.ValueString() != "ovmf" {
+			diags.AddAttributeError(path.Root("bios"), "EFI disk needs OVMF", "Set bios to ovmf to use an EFI disk")
+		}
+	}
+
+	if config.TPMState != nil && config.TPMState.Storage.IsNull() {
+		diags.AddAttributeError(path.Root("tpmstate0").AtName("storage"), "Missing TPM state storage", "The storage of the TPM state must be set, for example local-lvm")
+	}
+
+	return diags
+}
@@ -0,0 +1,105 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"reflect"
+	"testing"
+)
+
+func TestEFIValues(t *testing.T) {
+	efiDisk := &VirtualMachineEFIDiskModel{
+		Storage:         types.StringValue("local-lvm"),
+		EFIType:         types.StringValue("4m"),
+		PreEnrolledKeys: types.BoolValue(true),
+	}
+	if value := newEFIDiskValue(efiDisk); value != "local-lvm:1,efitype=4m,pre-enrolled-keys=1" {
+		t.Errorf("Incorrect EFI disk value: %s", value)
+	}
+
+	tpmState := &VirtualMachineTPMStateModel{Storage: types.StringValue("local-lvm"), Version: types.StringValue("v2.0")}
+	if value := newTPMStateValue(tpmState); value != "local-lvm:1,version=v2.0" {
+		t.Errorf("Incorrect TPM state value: %s", value)
+	}
+}
+
+func TestReadEFI(t *testing.T) {
+	devices := map[string]string{
+		"efidisk0":  "local-lvm:vm-100-disk-1,efitype=4m,pre-enrolled-keys=1,size=4M",
+		"tpmstate0": "local-lvm:vm-100-disk-2,size=4M,version=v2.0",
+	}
+
+	expectedEFIDisk := &VirtualMachineEFIDiskModel{
+		Storage:         types.StringValue("local-lvm"),
+		EFIType:         types.StringValue("4m"),
+		PreEnrolledKeys: types.BoolValue(true),
+		Volume:          types.StringValue("local-lvm:vm-100-disk-1"),
+	}
+	if efiDisk := readEFIDisk(devices); !reflect.DeepEqual(efiDisk, expectedEFIDisk) {
+		t.Errorf("Incorrect EFI disk. Expected %+v, got %+v", expectedEFIDisk, efiDisk)
+	}
+	expectedTPMState := &VirtualMachineTPMStateModel{
+		Storage: types.StringValue("local-lvm"),
+		Version: types.StringValue("v2.0"),
+		Volume:  types.StringValue("local-lvm:vm-100-disk-2"),
+	}
+	if tpmState := readTPMState(devices); !reflect.DeepEqual(tpmState, expectedTPMState) {
+		t.Errorf("Incorrect TPM state. Expected %+v, got %+v", expectedTPMState, tpmState)
+	}
+
+	// Proxmox leaves out the options that are at their default
+	efiDisk := readEFIDisk(map[string]string{"efidisk0": "local-lvm:vm-100-disk-1,size=128K"})
+	if efiDisk.EFIType.ValueString() != "2m" || efiDisk.PreEnrolledKeys.ValueBool() {
+		t.Errorf("Incorrect default EFI disk options: %+v", efiDisk)
+	}
+	if readEFIDisk(map[string]string{}) != nil || readTPMState(map[string]string{}) != nil {
+		t.Errorf("Expected no EFI disk or TPM state")
+	}
+}
+
+func TestPlanEFIChanges(t *testing.T) {
+	state := VirtualMachineResourceModel{
+		EFIDisk:  readEFIDisk(map[string]string{"efidisk0": "local:100/vm-100-disk-1.qcow2,efitype=4m,size=528K"}),
+		TPMState: readTPMState(map[string]string{"tpmstate0": "local-lvm:vm-100-disk-2,size=4M,version=v2.0"}),
+	}
+	plan := VirtualMachineResourceModel{
+		EFIDisk: &VirtualMachineEFIDiskModel{
+			Storage:         types.StringValue("local-lvm"),
+			EFIType:         types.StringValue("4m"),
+			PreEnrolledKeys: types.BoolValue(false),
+		},
+	}
+
+	changes := planDiskChanges(nil, nil)
+	if err := planEFIChanges(plan, state, &changes); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(changes.moves, map[string]string{"efidisk0": "local-lvm"}) {
+		t.Errorf("Incorrect moves: %v", changes.moves)
+	}
+	if !reflect.DeepEqual(changes.removed, []string{"tpmstate0"}) || !reflect.DeepEqual(changes.removedVolumes, []string{"local-lvm:vm-100-disk-2"}) {
+		t.Errorf("Incorrect removed devices: %v %v", changes.removed, changes.removedVolumes)
+	}
+
+	plan.EFIDisk.PreEnrolledKeys = types.BoolValue(true)
+	changes = planDiskChanges(nil, nil)
+	if err := planEFIChanges(plan, state, &changes); err == nil {
+		t.Errorf("Expected an error when the options of the existing EFI disk change")
+	}
+}
+
+func TestValidateEFI(t *testing.T) {
+	config := VirtualMachineResourceModel{
+		BIOS:     types.StringNull(),
+		EFIDisk:  &VirtualMachineEFIDiskModel{Storage: types.StringValue("local-lvm")},
+		TPMState: &VirtualMachineTPMStateModel{Storage: types.StringNull()},
+	}
+	if diags := validateEFI(config); diags.ErrorsCount() != 2 {
+		t.Errorf("Expected errors for the bios and the TPM state storage, got %v", diags)
+	}
+
+	config.BIOS = types.StringValue("ovmf")
+	config.TPMState.Storage = types.StringValue("local-lvm")
+	if diags := validateEFI(config); diags.HasError() {
+		t.Errorf("Expected the EFI disk to be valid, got %v", diags)
+	}
+}
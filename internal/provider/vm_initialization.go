@@ -0,0 +1,324 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"terraform-provider-proxmox/internal/api"
+)
+
+// ipConfigKey matches the configuration keys that hold the cloud-init network configuration
+var ipConfigKey = regexp.MustCompile(`^ipconfig(\d+)$`)
+
+// VirtualMachineInitializationModel The cloud-init settings and the drive that Proxmox generates from them
+type VirtualMachineInitializationModel struct {
+	Interface    types.String                  `tfsdk:"interface"`
+	Storage      types.String                  `tfsdk:"storage"`
+	CIUser       types.String                  `tfsdk:"ciuser"`
+	CIPassword   types.String                  `tfsdk:"cipassword"`
+	SSHKeys      types.List                    `tfsdk:"sshkeys"`
+	Nameserver   types.String                  `tfsdk:"nameserver"`
+	Searchdomain types.String                  `tfsdk:"searchdomain"`
+	CICustom     types.String                  `tfsdk:"cicustom"`
+	IPConfigs    []VirtualMachineIPConfigModel `tfsdk:"ipconfig"`
+}
+
+// VirtualMachineIPConfigModel The addresses of the network device with the same position, the first block is ipconfig0
+type VirtualMachineIPConfigModel struct {
+	IP       types.String `tfsdk:"ip"`
+	Gateway  types.String `tfsdk:"gw"`
+	IP6      types.String `tfsdk:"ip6"`
+	Gateway6 types.String `tfsdk:"gw6"`
+}
+
+func virtualMachineInitializationBlock() schema.SingleNestedBlock {
+	return schema.SingleNestedBlock{
+		Description: "Configure the virtual machine with cloud-init",
+		Attributes: map[string]schema.Attribute{
+			"interface": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString("ide2"),
+				Description: "Where the cloud-init drive is attached, for example ide2 or scsi1",
+			},
+			"storage": schema.StringAttribute{
+				Optional:    true,
+				Description: "The storage of the cloud-init drive, for example local-lvm. Required when the block is set",
+			},
+			"ciuser": schema.StringAttribute{
+				Optional:    true,
+				Description: "The user to create instead of the default user of the image",
+			},
+			"cipassword": schema.StringAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				Description: "The password of the user. Proxmox does not return the password so changes made outside of Terraform are not detected",
+			},
+			"sshkeys": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "The public SSH keys of the user",
+			},
+			"nameserver": schema.StringAttribute{
+				Optional:    true,
+				Description: "The DNS servers separated by spaces. Defaults to the settings of the node",
+			},
+			"searchdomain": schema.StringAttribute{
+				Optional:    true,
+				Description: "The DNS search domains. Defaults to the settings of the node",
+			},
+			"cicustom": schema.StringAttribute{
+				Optional:    true,
+				Description: "Snippets that replace the generated files, for example user=local:snippets/user.yaml",
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"ipconfig": schema.ListNestedBlock{
+				Description: "The first block configures net0, the second net1 and so on",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"ip": schema.StringAttribute{
+							Optional:    true,
+							Description: "The IPv4 address in CIDR format, or dhcp",
+						},
+						"gw": schema.StringAttribute{
+							Optional:    true,
+							Description: "The IPv4 gateway",
+						},
+						"ip6": schema.StringAttribute{
+							Optional:    true,
+							Description: "The IPv6 address in CIDR format, dhcp or auto",
+						},
+						"gw6": schema.StringAttribute{
+							Optional:    true,
+							Description: "The IPv6 gateway",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// validateInitialization checks the initialization block when the configuration is validated
+func validateInitialization(initialization *VirtualMachineInitializationModel, disks []VirtualMachineDiskModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if initialization == nil {
+		return diags
+	}
+
+	if initialization.Storage.IsNull() {
+		diags.AddAttributeError(
+			path.Root("initialization").AtName("storage"),
+			"Missing cloud-init storage",
+			"The storage of the cloud-init drive must be set, for example local-lvm",
+		)
+	}
+
+	if initialization.Interface.IsNull() || initialization.Interface.IsUnknown() {
+		return diags
+	}
+	drive := initialization.Interface.ValueString()
+	if !diskInterface.MatchString(drive) {
+		diags.AddAttributeError(
+			path.Root("initialization").AtName("interface"),
+			"Invalid cloud-init interface",
+			fmt.Sprintf("The interface %q must be scsi, virtio, sata or ide followed by a number, for example ide2", drive),
+		)
+	}
+	for _, disk := range disks {
+		if disk.Interface.ValueString() == drive {
+			diags.AddAttributeError(
+				path.Root("initialization").AtName("interface"),
+				"Duplicate disk interface",
+				fmt.Sprintf("The interface %s is used by a disk and the cloud-init drive", drive),
+			)
+		}
+	}
+	return diags
+}
+
+// initializationValues returns the configuration keys and values that the cloud-init settings are sent as.
+// The cloud-init drive is returned under its interface.
+func initializationValues(ctx context.Context, initialization *VirtualMachineInitializationModel) (map[string]string, diag.Diagnostics) {
+	values := map[string]string{}
+	if initialization == nil {
+		return values, nil
+	}
+
+	values[initialization.Interface.ValueString()] = initialization.Storage.ValueString() + ":cloudinit"
+	for key, value := range map[string]types.String{
+		"ciuser":       initialization.CIUser,
+		"cipassword":   initialization.CIPassword,
+		"nameserver":   initialization.Nameserver,
+		"searchdomain": initialization.Searchdomain,
+		"cicustom":     initialization.CICustom,
+	} {
+		if !value.IsNull() && !value.IsUnknown() {
+			values[key] = value.ValueString()
+		}
+	}
+
+	for i, ipConfig := range initialization.IPConfigs {
+		properties := api.PropertyString{}
+		for key, value := range map[string]types.String{"ip": ipConfig.IP, "gw": ipConfig.Gateway, "ip6": ipConfig.IP6, "gw6": ipConfig.Gateway6} {
+			if !value.IsNull() && !value.IsUnknown() {
+				properties[key] = value.ValueString()
+			}
+		}
+		values[fmt.Sprintf("ipconfig%d", i)] = properties.String()
+	}
+
+	if initialization.SSHKeys.IsNull() || initialization.SSHKeys.IsUnknown() {
+		return values, nil
+	}
+	var keys []string
+	diags := initialization.SSHKeys.ElementsAs(ctx, &keys, false)
+	// Proxmox expects the keys URL encoded and does not decode + as a space
+	values["sshkeys"] = strings.ReplaceAll(url.QueryEscape(strings.Join(keys, "\n")), "+", "%20")
+	return values, diags
+}
+
+// initializationChanges describes how the cloud-init settings of a virtual machine need to change
+type initializationChanges struct {
+	// values are the keys that are new or have changed
+	values map[string]string
+	// removed are the keys that are no longer set
+	removed []string
+	// replaceDrive is the interface of a cloud-init drive that has to be removed before it is added on another storage
+	replaceDrive string
+}
+
+func (c initializationChanges) changed() bool {
+	return len(c.values) > 0 || len(c.removed) > 0
+}
+
+func planInitializationChanges(ctx context.Context, plan *VirtualMachineInitializationModel, state *VirtualMachineInitializationModel) (initializationChanges, diag.Diagnostics) {
+	planned, diags := initializationValues(ctx, plan)
+	current, currentDiags := initializationValues(ctx, state)
+	diags.Append(currentDiags...)
+
+	changes := initializationChanges{values: map[string]string{}}
+	for key, value := range planned {
+		if current[key] != value {
+			changes.values[key] = value
+		}
+	}
+	for key := range current {
+		if _, ok := planned[key]; !ok {
+			changes.removed = append(changes.removed, key)
+		}
+	}
+	sort.Strings(changes.removed)
+
+	if plan != nil && state != nil && plan.Interface.ValueString() == state.Interface.ValueString() &&
+		plan.Storage.ValueString() != state.Storage.ValueString() {
+		changes.replaceDrive = plan.Interface.ValueString()
+	}
+
+	return changes, diags
+}
+
+// addInitializationValues puts the cloud-init values into the request
+func addInitializationValues(vmRequest *api.VirtualMachineRequest, values map[string]string) {
+	fields := map[string]**string{
+		"ciuser":       &vmRequest.CIUser,
+		"cipassword":   &vmRequest.CIPassword,
+		"sshkeys":      &vmRequest.SSHKeys,
+		"nameserver":   &vmRequest.Nameserver,
+		"searchdomain": &vmRequest.Searchdomain,
+		"cicustom":     &vmRequest.CICustom,
+	}
+	for key, value := range values {
+		value := value
+		if field, ok := fields[key]; ok {
+			*field = &value
+		} else {
+			vmRequest.Devices[key] = value
+		}
+	}
+}
+
+// readInitialization converts the cloud-init settings in the configuration into the model. Proxmox does
+// not return the password, so it is kept from the prior model.
+func readInitialization(config api.VirtualMachineConfig, prior *VirtualMachineInitializationModel) (*VirtualMachineInitializationModel, error) {
+	var drive string
+	for key, value := range config.Devices {
+		if diskInterface.MatchString(key) && strings.Contains(api.ParsePropertyString(value)[""], "cloudinit") {
+			drive = key
+		}
+	}
+	if drive == "" {
+		return nil, nil
+	}
+
+	storage, _, _ := strings.Cut(api.ParsePropertyString(config.Devices[drive])[""], ":")
+	initialization := VirtualMachineInitializationModel{
+		Interface:    types.StringValue(drive),
+		Storage:      types.StringValue(storage),
+		CIUser:       types.StringPointerValue(config.CIUser),
+		CIPassword:   types.StringNull(),
+		SSHKeys:      types.ListNull(types.StringType),
+		Nameserver:   types.StringPointerValue(config.Nameserver),
+		Searchdomain: types.StringPointerValue(config.Searchdomain),
+		CICustom:     types.StringPointerValue(config.CICustom),
+		IPConfigs:    []VirtualMachineIPConfigModel{},
+	}
+	if config.CIPassword != nil && prior != nil {
+		initialization.CIPassword = prior.CIPassword
+	}
+
+	if config.SSHKeys != nil {
+		decoded, err := url.PathUnescape(*config.SSHKeys)
+		if err != nil {
+			return nil, fmt.Errorf("the SSH keys are not URL encoded: %w", err)
+		}
+		var keys []attr.Value
+		for _, key := range strings.Split(decoded, "\n") {
+			if strings.TrimSpace(key) != "" {
+				keys = append(keys, types.StringValue(key))
+			}
+		}
+		if len(keys) > 0 {
+			initialization.SSHKeys = types.ListValueMust(types.StringType, keys)
+		}
+	}
+
+	var keys []string
+	for key := range config.Devices {
+		if ipConfigKey.MatchString(key) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return ipConfigNumber(keys[i]) < ipConfigNumber(keys[j])
+	})
+	for _, key := range keys {
+		properties := api.ParsePropertyString(config.Devices[key])
+		ipConfig := VirtualMachineIPConfigModel{}
+		for name, value := range map[string]*types.String{"ip": &ipConfig.IP, "gw": &ipConfig.Gateway, "ip6": &ipConfig.IP6, "gw6": &ipConfig.Gateway6} {
+			*value = types.StringNull()
+			if property, ok := properties[name]; ok {
+				*value = types.StringValue(property)
+			}
+		}
+		initialization.IPConfigs = append(initialization.IPConfigs, ipConfig)
+	}
+
+	return &initialization, nil
+}
+
+func ipConfigNumber(key string) int {
+	number, _ := strconv.Atoi(ipConfigKey.FindStringSubmatch(key)[1])
+	return number
+}
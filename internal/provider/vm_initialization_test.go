@@ -0,0 +1,127 @@
+package provider
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"reflect"
+	"terraform-provider-proxmox/internal/api"
+	"testing"
+)
+
+func TestInitializationValues(t *testing.T) {
+	initialization := &VirtualMachineInitializationModel{
+		Interface:  types.StringValue("ide2"),
+		Storage:    types.StringValue("local-lvm"),
+		CIUser:     types.StringValue("debian"),
+		CIPassword: types.StringValue("secret"),
+		SSHKeys:    types.ListValueMust(types.StringType, []attr.Value{types.StringValue("ssh-ed25519 AAAA user@host")}),
+		IPConfigs: []VirtualMachineIPConfigModel{
+			{IP: types.StringValue("10.0.0.10/24"), Gateway: types.StringValue("10.0.0.1")},
+			{IP: types.StringValue("dhcp"), IP6: types.StringValue("auto")},
+		},
+	}
+
+	values, diags := initializationValues(context.Background(), initialization)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+
+	expected := map[string]string{
+		"ide2":       "local-lvm:cloudinit",
+		"ciuser":     "debian",
+		"cipassword": "secret",
+		"sshkeys":    "ssh-ed25519%20AAAA%20user%40host",
+		"ipconfig0":  "gw=10.0.0.1,ip=10.0.0.10/24",
+		"ipconfig1":  "ip=dhcp,ip6=auto",
+	}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("Incorrect values. Expected %v, got %v", expected, values)
+	}
+}
+
+func TestReadInitialization(t *testing.T) {
+	user := "debian"
+	masked := "**********"
+	keys := "ssh-ed25519%20AAAA%20user%40host%0Assh-rsa%20BBBB%20other%40host%0A"
+	config := api.VirtualMachineConfig{
+		CIUser:     &user,
+		CIPassword: &masked,
+		SSHKeys:    &keys,
+		Devices: map[string]string{
+			"ide2":      "local-lvm:vm-100-cloudinit,media=cdrom",
+			"ipconfig0": "ip=10.0.0.10/24,gw=10.0.0.1",
+			"ipconfig1": "ip=dhcp,ip6=auto",
+		},
+	}
+	prior := &VirtualMachineInitializationModel{CIPassword: types.StringValue("secret")}
+
+	initialization, err := readInitialization(config, prior)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if initialization.CIPassword.ValueString() != "secret" {
+		t.Errorf("Expected the password to be kept from the prior state, got %v", initialization.CIPassword)
+	}
+	if initialization.Storage.ValueString() != "local-lvm" || initialization.Interface.ValueString() != "ide2" {
+		t.Errorf("Incorrect drive returned: %v %v", initialization.Interface, initialization.Storage)
+	}
+	expectedKeys := types.ListValueMust(types.StringType, []attr.Value{types.StringValue("ssh-ed25519 AAAA user@host"), types.StringValue("ssh-rsa BBBB other@host")})
+	if !initialization.SSHKeys.Equal(expectedKeys) {
+		t.Errorf("Incorrect SSH keys. Expected %v, got %v", expectedKeys, initialization.SSHKeys)
+	}
+	expectedIPConfigs := []VirtualMachineIPConfigModel{
+		{IP: types.StringValue("10.0.0.10/24"), Gateway: types.StringValue("10.0.0.1")},
+		{IP: types.StringValue("dhcp"), IP6: types.StringValue("auto")},
+	}
+	if !reflect.DeepEqual(initialization.IPConfigs, expectedIPConfigs) {
+		t.Errorf("Incorrect IP configuration returned: %+v", initialization.IPConfigs)
+	}
+
+	delete(config.Devices, "ide2")
+	initialization, err = readInitialization(config, prior)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if initialization != nil {
+		t.Errorf("Expected no cloud-init settings without a cloud-init drive, got %+v", initialization)
+	}
+}
+
+func TestPlanInitializationChanges(t *testing.T) {
+	state := &VirtualMachineInitializationModel{
+		Interface: types.StringValue("ide2"),
+		Storage:   types.StringValue("local-lvm"),
+		CIUser:    types.StringValue("debian"),
+		IPConfigs: []VirtualMachineIPConfigModel{
+			{IP: types.StringValue("10.0.0.10/24"), Gateway: types.StringValue("10.0.0.1")},
+			{IP: types.StringValue("dhcp")},
+		},
+	}
+	plan := &VirtualMachineInitializationModel{
+		Interface: types.StringValue("ide2"),
+		Storage:   types.StringValue("ceph"),
+		IPConfigs: state.IPConfigs[:1],
+	}
+
+	changes, diags := planInitializationChanges(context.Background(), plan, state)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+
+	if !reflect.DeepEqual(changes.values, map[string]string{"ide2": "ceph:cloudinit"}) {
+		t.Errorf("Incorrect values: %v", changes.values)
+	}
+	if !reflect.DeepEqual(changes.removed, []string{"ciuser", "ipconfig1"}) {
+		t.Errorf("Incorrect removed keys: %v", changes.removed)
+	}
+	if changes.replaceDrive != "ide2" {
+		t.Errorf("Expected the drive to be replaced, got %q", changes.replaceDrive)
+	}
+
+	changes, _ = planInitializationChanges(context.Background(), state, state)
+	if changes.changed() {
+		t.Errorf("Expected no changes, got %+v", changes)
+	}
+}
@@ -0,0 +1,52 @@
+package provider
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"terraform-provider-proxmox/internal/api"
+	"time"
+)
+
+// requiresReplaceUnlessMigrated replaces the guest when its node changes and migrate is false.
+// Otherwise the guest is migrated to the new node, which keeps its disks.
+func requiresReplaceUnlessMigrated() planmodifier.String {
+	return stringplanmodifier.RequiresReplaceIf(
+		func(ctx context.Context, request planmodifier.StringRequest, response *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+			var migrate types.Bool
+			response.Diagnostics.Append(request.Plan.GetAttribute(ctx, path.Root("migrate"), &migrate)...)
+			response.RequiresReplace = !migrate.IsUnknown() && !migrate.ValueBool()
+		},
+		"Changing the node migrates the guest, or replaces it when migrate is false",
+		"Changing the node migrates the guest, or replaces it when migrate is false",
+	)
+}
+
+// migrateVirtualMachine moves the virtual machine from the node in the state to the node in the plan.
+// A running virtual machine is migrated online, so the guest keeps running.
+func (r *virtualMachineResource) migrateVirtualMachine(plan VirtualMachineResourceModel, state VirtualMachineResourceModel) error {
+	node := state.Node.ValueString()
+	vmid := state.VMID.ValueInt64()
+
+	status, err := api.GetVirtualMachineStatus(r.client, node, vmid)
+	if err != nil {
+		return err
+	}
+
+	online := api.Bool(status.Status == "running")
+	withLocalDisks := api.Bool(plan.MigrateWithLocalDisks.ValueBool())
+	migrateRequest := api.VirtualMachineMigrateRequest{
+		Target:         plan.Node.ValueString(),
+		Online:         &online,
+		WithLocalDisks: &withLocalDisks,
+		TargetStorage:  plan.MigrateTargetStorage.ValueStringPointer(),
+	}
+
+	upid, err := api.MigrateVirtualMachine(r.client, node, vmid, &migrateRequest)
+	if err != nil {
+		return err
+	}
+	return api.WaitForTask(r.client, upid, time.Duration(plan.MigrateTimeout.ValueInt64())*time.Second)
+}
@@ -0,0 +1,277 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"terraform-provider-proxmox/internal/api"
+)
+
+// networkInterface matches the configuration keys that hold a network device
+var networkInterface = regexp.MustCompile(`^net(\d+)$`)
+
+// networkModels are the emulated network cards. Proxmox returns the model as the key of the MAC address,
+// for example virtio=BC:24:11:2E:61:1A.
+var networkModels = []string{
+	"virtio", "e1000", "e1000e", "e1000-82540em", "e1000-82544gc", "e1000-82545em", "rtl8139", "vmxnet3",
+	"i82551", "i82557b", "i82559er", "ne2k_isa", "ne2k_pci", "pcnet",
+}
+
+// VirtualMachineNetworkDeviceModel The network device at position N of the list is configured as netN, so the
+// devices of a virtual machine must be numbered without gaps
+type VirtualMachineNetworkDeviceModel struct {
+	Model    types.String  `tfsdk:"model"`
+	Bridge   types.String  `tfsdk:"bridge"`
+	Tag      types.Int64   `tfsdk:"tag"`
+	Trunks   types.List    `tfsdk:"trunks"`
+	Firewall types.Bool    `tfsdk:"firewall"`
+	MAC      types.String  `tfsdk:"mac"`
+	Rate     types.Float64 `tfsdk:"rate"`
+	MTU      types.Int64   `tfsdk:"mtu"`
+	Queues   types.Int64   `tfsdk:"queues"`
+	LinkDown types.Bool    `tfsdk:"link_down"`
+}
+
+func virtualMachineNetworkDeviceBlock() schema.ListNestedBlock {
+	return schema.ListNestedBlock{
+		Description: "The first block is net0, the second net1 and so on",
+		NestedObject: schema.NestedBlockObject{
+			Attributes: map[string]schema.Attribute{
+				"model": schema.StringAttribute{
+					Optional:    true,
+					Computed:    true,
+					Default:     stringdefault.StaticString("virtio"),
+					Description: "The emulated network card, for example virtio, e1000 or vmxnet3",
+				},
+				"bridge": schema.StringAttribute{
+					Required:    true,
+					Description: "The bridge to connect the device to, for example vmbr0",
+				},
+				"tag": schema.Int64Attribute{
+					Optional:    true,
+					Description: "The VLAN tag of the traffic on the device",
+				},
+				"trunks": schema.ListAttribute{
+					Optional:    true,
+					ElementType: types.Int64Type,
+					Description: "The VLANs to pass through the device",
+				},
+				"firewall": schema.BoolAttribute{
+					Optional: true,
+					Computed: true,
+					Default:  booldefault.StaticBool(false),
+				},
+				"mac": schema.StringAttribute{
+					Optional:    true,
+					Computed:    true,
+					Description: "Proxmox generates a MAC address when none is set, it is kept when the device changes",
+					PlanModifiers: []planmodifier.String{
+						stringplanmodifier.UseStateForUnknown(),
+					},
+				},
+				"rate": schema.Float64Attribute{
+					Optional:    true,
+					Description: "The rate limit in MB/s",
+				},
+				"mtu": schema.Int64Attribute{
+					Optional:    true,
+					Description: "Only used by virtio devices. Set to 1 to use the MTU of the bridge",
+				},
+				"queues": schema.Int64Attribute{
+					Optional:    true,
+					Description: "The number of packet queues",
+				},
+				"link_down": schema.BoolAttribute{
+					Optional:    true,
+					Computed:    true,
+					Default:     booldefault.StaticBool(false),
+					Description: "Disconnect the device, like pulling out the cable",
+				},
+			},
+		},
+	}
+}
+
+// networkDeviceValue is the property string of the device, for example model=virtio,bridge=vmbr0,tag=10
+func networkDeviceValue(ctx context.Context, device VirtualMachineNetworkDeviceModel) (string, diag.Diagnostics) {
+	properties := api.PropertyString{
+		"model":  device.Model.ValueString(),
+		"bridge": device.Bridge.ValueString(),
+	}
+	if !device.MAC.IsNull() && !device.MAC.IsUnknown() {
+		properties["macaddr"] = device.MAC.ValueString()
+	}
+	if !device.Tag.IsNull() {
+		properties["tag"] = strconv.FormatInt(device.Tag.ValueInt64(), 10)
+	}
+	if device.Firewall.ValueBool() {
+		properties.SetFlag("firewall", true)
+	}
+	if device.LinkDown.ValueBool() {
+		properties.SetFlag("link_down", true)
+	}
+	if !device.Rate.IsNull() {
+		properties["rate"] = strconv.FormatFloat(device.Rate.ValueFloat64(), 'f', -1, 64)
+	}
+	if !device.MTU.IsNull() {
+		properties["mtu"] = strconv.FormatInt(device.MTU.ValueInt64(), 10)
+	}
+	if !device.Queues.IsNull() {
+		properties["queues"] = strconv.FormatInt(device.Queues.ValueInt64(), 10)
+	}
+
+	if device.Trunks.IsNull() || device.Trunks.IsUnknown() {
+		return properties.String(), nil
+	}
+	var trunks []int64
+	diags := device.Trunks.ElementsAs(ctx, &trunks, false)
+	var vlans []string
+	for _, trunk := range trunks {
+		vlans = append(vlans, strconv.FormatInt(trunk, 10))
+	}
+	properties["trunks"] = strings.Join(vlans, ";")
+	return properties.String(), diags
+}
+
+// readNetworkDevices converts the network devices in the configuration into the model, ordered by their number
+func readNetworkDevices(devices map[string]string) ([]VirtualMachineNetworkDeviceModel, error) {
+	keys, err := networkDeviceKeys(devices)
+	if err != nil {
+		return nil, err
+	}
+
+	networkDevices := []VirtualMachineNetworkDeviceModel{}
+	for _, key := range keys {
+		device, err := readNetworkDevice(api.ParsePropertyString(devices[key]))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		networkDevices = append(networkDevices, device)
+	}
+	return networkDevices, nil
+}
+
+// networkDeviceKeys returns the network devices in the configuration ordered by their number. The position of a
+// device in the list is its number, so a gap would move the devices after it onto other devices and is an error.
+func networkDeviceKeys(devices map[string]string) ([]string, error) {
+	var keys []string
+	for key := range devices {
+		if networkInterface.MatchString(key) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return networkDeviceNumber(keys[i]) < networkDeviceNumber(keys[j])
+	})
+
+	for i, key := range keys {
+		if networkDeviceNumber(key) != i {
+			return nil, fmt.Errorf("%s is configured but net%d is not. The network devices must be numbered from net0 without gaps, renumber them in Proxmox", key, i)
+		}
+	}
+	return keys, nil
+}
+
+func networkDeviceNumber(key string) int {
+	number, _ := strconv.Atoi(networkInterface.FindStringSubmatch(key)[1])
+	return number
+}
+
+func readNetworkDevice(properties api.PropertyString) (VirtualMachineNetworkDeviceModel, error) {
+	device := VirtualMachineNetworkDeviceModel{
+		Model:    types.StringValue(properties["model"]),
+		Bridge:   types.StringValue(properties["bridge"]),
+		Tag:      types.Int64Null(),
+		Trunks:   types.ListNull(types.Int64Type),
+		Firewall: types.BoolValue(properties.Flag("firewall", false)),
+		MAC:      types.StringValue(properties["macaddr"]),
+		Rate:     types.Float64Null(),
+		MTU:      types.Int64Null(),
+		Queues:   types.Int64Null(),
+		LinkDown: types.BoolValue(properties.Flag("link_down", false)),
+	}
+	for _, model := range networkModels {
+		if mac, ok := properties[model]; ok {
+			device.Model = types.StringValue(model)
+			device.MAC = types.StringValue(mac)
+		}
+	}
+	if model, ok := properties[""]; ok {
+		device.Model = types.StringValue(model)
+	}
+
+	for key, value := range map[string]*types.Int64{"tag": &device.Tag, "mtu": &device.MTU, "queues": &device.Queues} {
+		if properties[key] == "" {
+			continue
+		}
+		number, err := strconv.ParseInt(properties[key], 10, 64)
+		if err != nil {
+			return device, fmt.Errorf("the %s %q is not a number", key, properties[key])
+		}
+		*value = types.Int64Value(number)
+	}
+
+	if properties["rate"] != "" {
+		rate, err := strconv.ParseFloat(properties["rate"], 64)
+		if err != nil {
+			return device, fmt.Errorf("the rate %q is not a number", properties["rate"])
+		}
+		device.Rate = types.Float64Value(rate)
+	}
+
+	if properties["trunks"] != "" {
+		var trunks []attr.Value
+		for _, vlan := range strings.Split(properties["trunks"], ";") {
+			trunk, err := strconv.ParseInt(vlan, 10, 64)
+			if err != nil {
+				return device, fmt.Errorf("the trunk %q is not a VLAN", vlan)
+			}
+			trunks = append(trunks, types.Int64Value(trunk))
+		}
+		device.Trunks = types.ListValueMust(types.Int64Type, trunks)
+	}
+
+	return device, nil
+}
+
+// planNetworkDeviceChanges returns the network devices that are new or have changed and the devices that were removed
+func planNetworkDeviceChanges(ctx context.Context, plan []VirtualMachineNetworkDeviceModel, state []VirtualMachineNetworkDeviceModel) (map[string]string, []string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	devices := map[string]string{}
+
+	for i, device := range plan {
+		key := fmt.Sprintf("net%d", i)
+		// A cloned virtual machine already has a MAC address for its devices, keep it
+		if device.MAC.IsUnknown() && i < len(state) {
+			device.MAC = state[i].MAC
+		}
+		value, valueDiags := networkDeviceValue(ctx, device)
+		diags.Append(valueDiags...)
+		if i < len(state) {
+			current, currentDiags := networkDeviceValue(ctx, state[i])
+			diags.Append(currentDiags...)
+			if current == value {
+				continue
+			}
+		}
+		devices[key] = value
+	}
+
+	var removed []string
+	for i := len(plan); i < len(state); i++ {
+		removed = append(removed, fmt.Sprintf("net%d", i))
+	}
+
+	return devices, removed, diags
+}
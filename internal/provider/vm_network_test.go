@@ -0,0 +1,101 @@
+package provider
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"reflect"
+	"testing"
+)
+
+func TestReadNetworkDevices(t *testing.T) {
+	devices := map[string]string{
+		"net2":  "e1000=BC:24:11:00:00:03,bridge=vmbr2",
+		"net0":  "virtio=BC:24:11:00:00:01,bridge=vmbr0,firewall=1,tag=10,trunks=20;30,rate=12.5,mtu=1,queues=4",
+		"net1":  "model=vmxnet3,macaddr=BC:24:11:00:00:02,bridge=vmbr1,link_down=1",
+		"scsi0": "local-lvm:vm-100-disk-0,size=32G",
+	}
+
+	networkDevices, err := readNetworkDevices(devices)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(networkDevices) != 3 {
+		t.Fatalf("Expected 3 network devices, got %d", len(networkDevices))
+	}
+
+	expected := VirtualMachineNetworkDeviceModel{
+		Model:    types.StringValue("virtio"),
+		Bridge:   types.StringValue("vmbr0"),
+		Tag:      types.Int64Value(10),
+		Trunks:   types.ListValueMust(types.Int64Type, []attr.Value{types.Int64Value(20), types.Int64Value(30)}),
+		Firewall: types.BoolValue(true),
+		MAC:      types.StringValue("BC:24:11:00:00:01"),
+		Rate:     types.Float64Value(12.5),
+		MTU:      types.Int64Value(1),
+		Queues:   types.Int64Value(4),
+		LinkDown: types.BoolValue(false),
+	}
+	if !reflect.DeepEqual(networkDevices[0], expected) {
+		t.Errorf("Incorrect net0 returned. Expected %+v, got %+v", expected, networkDevices[0])
+	}
+
+	if networkDevices[1].Model.ValueString() != "vmxnet3" || networkDevices[1].MAC.ValueString() != "BC:24:11:00:00:02" || !networkDevices[1].LinkDown.ValueBool() {
+		t.Errorf("Incorrect net1 returned: %+v", networkDevices[1])
+	}
+	if networkDevices[2].Model.ValueString() != "e1000" || networkDevices[2].Bridge.ValueString() != "vmbr2" {
+		t.Errorf("Expected net2 to be last, got %+v", networkDevices[2])
+	}
+
+	delete(devices, "net1")
+	_, err = readNetworkDevices(devices)
+	if err == nil {
+		t.Error("Expected an error for a gap in the device numbers")
+	}
+}
+
+func TestNetworkDeviceValue(t *testing.T) {
+	device := VirtualMachineNetworkDeviceModel{
+		Model:  types.StringValue("virtio"),
+		Bridge: types.StringValue("vmbr0"),
+		Tag:    types.Int64Value(10),
+		Trunks: types.ListValueMust(types.Int64Type, []attr.Value{types.Int64Value(20), types.Int64Value(30)}),
+		MAC:    types.StringUnknown(),
+		Rate:   types.Float64Value(12.5),
+	}
+
+	value, diags := networkDeviceValue(context.Background(), device)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	expected := "bridge=vmbr0,model=virtio,rate=12.5,tag=10,trunks=20;30"
+	if value != expected {
+		t.Errorf("Incorrect value. Expected %s, got %s", expected, value)
+	}
+}
+
+func TestPlanNetworkDeviceChanges(t *testing.T) {
+	state := []VirtualMachineNetworkDeviceModel{
+		{Model: types.StringValue("virtio"), Bridge: types.StringValue("vmbr0"), MAC: types.StringValue("BC:24:11:00:00:01")},
+		{Model: types.StringValue("virtio"), Bridge: types.StringValue("vmbr1"), MAC: types.StringValue("BC:24:11:00:00:02")},
+		{Model: types.StringValue("virtio"), Bridge: types.StringValue("vmbr2"), MAC: types.StringValue("BC:24:11:00:00:03")},
+	}
+	plan := []VirtualMachineNetworkDeviceModel{
+		state[0],
+		{Model: types.StringValue("virtio"), Bridge: types.StringValue("vmbr88"), MAC: types.StringValue("BC:24:11:00:00:02")},
+	}
+
+	devices, removed, diags := planNetworkDeviceChanges(context.Background(), plan, state)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+
+	expected := map[string]string{"net1": "bridge=vmbr88,macaddr=BC:24:11:00:00:02,model=virtio"}
+	if !reflect.DeepEqual(devices, expected) {
+		t.Errorf("Incorrect devices. Expected %v, got %v", expected, devices)
+	}
+	if !reflect.DeepEqual(removed, []string{"net2"}) {
+		t.Errorf("Incorrect removed devices: %v", removed)
+	}
+}
@@ -0,0 +1,294 @@
+package provider
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"terraform-provider-proxmox/internal/api"
+)
+
+// passthroughKey matches the configuration keys of the devices that are passed through to the guest
+var passthroughKey = regexp.MustCompile(`^(hostpci|usb|serial)(\d+)$`)
+
+// VirtualMachineHostPCIModel A PCI device of the host, the first block is hostpci0
+type VirtualMachineHostPCIModel struct {
+	Device  types.String `tfsdk:"device"`
+	Mapping types.String `tfsdk:"mapping"`
+	PCIe    types.Bool   `tfsdk:"pcie"`
+	ROMBar  types.Bool   `tfsdk:"rombar"`
+	XVGA    types.Bool   `tfsdk:"x_vga"`
+	MDev    types.String `tfsdk:"mdev"`
+}
+
+// VirtualMachineUSBModel A USB device of the host, the first block is usb0
+type VirtualMachineUSBModel struct {
+	Host    types.String `tfsdk:"host"`
+	Mapping types.String `tfsdk:"mapping"`
+	USB3    types.Bool   `tfsdk:"usb3"`
+}
+
+// VirtualMachineSerialModel A serial port, the first block is serial0
+type VirtualMachineSerialModel struct {
+	Device types.String `tfsdk:"device"`
+}
+
+func virtualMachineHostPCIBlock() schema.ListNestedBlock {
+	return schema.ListNestedBlock{
+		Description: "Pass a PCI device of the host through to the guest. The first block is hostpci0, the second hostpci1 and so on",
+		NestedObject: schema.NestedBlockObject{
+			Attributes: map[string]schema.Attribute{
+				"device": schema.StringAttribute{
+					Optional:    true,
+					Description: "The PCI ID of the device, for example 0000:01:00.0, or 01:00 for all its functions. Exactly one of device and mapping must be set",
+				},
+				"mapping": schema.StringAttribute{
+					Optional:    true,
+					Description: "The name of a cluster resource mapping, which lets the virtual machine use the device on any node",
+				},
+				"pcie": schema.BoolAttribute{
+					Optional:    true,
+					Computed:    true,
+					Default:     booldefault.StaticBool(false),
+					Description: "Pass the device through as PCI Express, this needs the q35 machine type",
+				},
+				"rombar": schema.BoolAttribute{
+					Optional:    true,
+					Computed:    true,
+					Default:     booldefault.StaticBool(true),
+					Description: "Make the ROM of the device visible to the guest",
+				},
+				"x_vga": schema.BoolAttribute{
+					Optional:    true,
+					Computed:    true,
+					Default:     booldefault.StaticBool(false),
+					Description: "Use the device as the primary GPU of the guest",
+				},
+				"mdev": schema.StringAttribute{
+					Optional:    true,
+					Description: "The mediated device type, for example nvidia-63, to share the device between guests",
+				},
+			},
+		},
+	}
+}
+
+func virtualMachineUSBBlock() schema.ListNestedBlock {
+	return schema.ListNestedBlock{
+		Description: "Pass a USB device of the host through to the guest. The first block is usb0, the second usb1 and so on",
+		NestedObject: schema.NestedBlockObject{
+			Attributes: map[string]schema.Attribute{
+				"host": schema.StringAttribute{
+					Optional:    true,
+					Description: "The vendor and product ID, for example 046d:c52b, or the bus and port, for example 1-2. Exactly one of host and mapping must be set",
+				},
+				"mapping": schema.StringAttribute{
+					Optional:    true,
+					Description: "The name of a cluster resource mapping, which lets the virtual machine use the device on any node",
+				},
+				"usb3": schema.BoolAttribute{
+					Optional:    true,
+					Computed:    true,
+					Default:     booldefault.StaticBool(false),
+					Description: "Attach the device to a USB 3 controller",
+				},
+			},
+		},
+	}
+}
+
+func virtualMachineSerialBlock() schema.ListNestedBlock {
+	return schema.ListNestedBlock{
+		Description: "A serial port. The first block is serial0, the second serial1 and so on",
+		NestedObject: schema.NestedBlockObject{
+			Attributes: map[string]schema.Attribute{
+				"device": schema.StringAttribute{
+					Required:    true,
+					Description: "Either socket for a serial console, or a serial device of the host such as /dev/ttyS0",
+				},
+			},
+		},
+	}
+}
+
+func hostPCIValue(hostPCI VirtualMachineHostPCIModel) string {
+	properties := api.PropertyString{}
+	if !hostPCI.Device.IsNull() {
+		properties[""] = hostPCI.Device.ValueString()
+	}
+	if !hostPCI.Mapping.IsNull() {
+		properties["mapping"] = hostPCI.Mapping.ValueString()
+	}
+	if hostPCI.PCIe.ValueBool() {
+		properties.SetFlag("pcie", true)
+	}
+	if !hostPCI.ROMBar.IsNull() && !hostPCI.ROMBar.ValueBool() {
+		properties.SetFlag("rombar", false)
+	}
+	if hostPCI.XVGA.ValueBool() {
+		properties.SetFlag("x-vga", true)
+	}
+	if !hostPCI.MDev.IsNull() {
+		properties["mdev"] = hostPCI.MDev.ValueString()
+	}
+	return properties.String()
+}
+
+func readHostPCI(properties api.PropertyString) VirtualMachineHostPCIModel {
+	hostPCI := VirtualMachineHostPCIModel{
+		Device:  types.StringNull(),
+		Mapping: types.StringNull(),
+		PCIe:    types.BoolValue(properties.Flag("pcie", false)),
+		ROMBar:  types.BoolValue(properties.Flag("rombar", true)),
+		XVGA:    types.BoolValue(properties.Flag("x-vga", false)),
+		MDev:    types.StringNull(),
+	}
+	for _, key := range []string{"", "host"} {
+		if device, ok := properties[key]; ok {
+			hostPCI.Device = types.StringValue(device)
+		}
+	}
+	if mapping, ok := properties["mapping"]; ok {
+		hostPCI.Mapping = types.StringValue(mapping)
+	}
+	if mdev, ok := properties["mdev"]; ok {
+		hostPCI.MDev = types.StringValue(mdev)
+	}
+	return hostPCI
+}
+
+func usbValue(usb VirtualMachineUSBModel) string {
+	properties := api.PropertyString{}
+	if !usb.Host.IsNull() {
+		properties["host"] = usb.Host.ValueString()
+	}
+	if !usb.Mapping.IsNull() {
+		properties["mapping"] = usb.Mapping.ValueString()
+	}
+	if usb.USB3.ValueBool() {
+		properties.SetFlag("usb3", true)
+	}
+	return properties.String()
+}
+
+func readUSB(properties api.PropertyString) VirtualMachineUSBModel {
+	usb := VirtualMachineUSBModel{
+		Host:    types.StringNull(),
+		Mapping: types.StringNull(),
+		USB3:    types.BoolValue(properties.Flag("usb3", false)),
+	}
+	for _, key := range []string{"", "host"} {
+		if host, ok := properties[key]; ok {
+			usb.Host = types.StringValue(host)
+		}
+	}
+	if mapping, ok := properties["mapping"]; ok {
+		usb.Mapping = types.StringValue(mapping)
+	}
+	return usb
+}
+
+// passthroughValues returns the configuration keys and values of the passthrough devices in the model
+func passthroughValues(model VirtualMachineResourceModel) map[string]string {
+	values := map[string]string{}
+	for i, hostPCI := range model.HostPCI {
+		values[fmt.Sprintf("hostpci%d", i)] = hostPCIValue(hostPCI)
+	}
+	for i, usb := range model.USB {
+		values[fmt.Sprintf("usb%d", i)] = usbValue(usb)
+	}
+	for i, serial := range model.Serial {
+		values[fmt.Sprintf("serial%d", i)] = serial.Device.ValueString()
+	}
+	return values
+}
+
+// readPassthrough converts the passthrough devices in the configuration into the model, ordered by their number
+func readPassthrough(devices map[string]string, model *VirtualMachineResourceModel) {
+	var keys []string
+	for key := range devices {
+		if passthroughKey.MatchString(key) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return passthroughNumber(keys[i]) < passthroughNumber(keys[j])
+	})
+
+	model.HostPCI = []VirtualMachineHostPCIModel{}
+	model.USB = []VirtualMachineUSBModel{}
+	model.Serial = []VirtualMachineSerialModel{}
+	for _, key := range keys {
+		properties := api.ParsePropertyString(devices[key])
+		switch passthroughKey.FindStringSubmatch(key)[1] {
+		case "hostpci":
+			model.HostPCI = append(model.HostPCI, readHostPCI(properties))
+		case "usb":
+			model.USB = append(model.USB, readUSB(properties))
+		case "serial":
+			model.Serial = append(model.Serial, VirtualMachineSerialModel{Device: types.StringValue(devices[key])})
+		}
+	}
+}
+
+func passthroughNumber(key string) int {
+	number, _ := strconv.Atoi(passthroughKey.FindStringSubmatch(key)[2])
+	return number
+}
+
+// planPassthroughChanges returns the passthrough devices that are new or have changed and the devices that were removed
+func planPassthroughChanges(plan VirtualMachineResourceModel, state VirtualMachineResourceModel) (map[string]string, []string) {
+	planned := passthroughValues(plan)
+	current := passthroughValues(state)
+
+	devices := map[string]string{}
+	for key, value := range planned {
+		if current[key] != value {
+			devices[key] = value
+		}
+	}
+
+	var removed []string
+	for key := range current {
+		if _, ok := planned[key]; !ok {
+			removed = append(removed, key)
+		}
+	}
+	sort.Strings(removed)
+
+	return devices, removed
+}
+
+// validatePassthrough checks the passthrough devices when the configuration is validated. PCI Express
+// passthrough is only available on the q35 machine type.
+func validatePassthrough(config VirtualMachineResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	for i, hostPCI := range config.HostPCI {
+		hostPCIPath := path.Root("hostpci").AtListIndex(i)
+		if !hostPCI.Device.IsUnknown() && !hostPCI.Mapping.IsUnknown() && hostPCI.Device.IsNull() == hostPCI.Mapping.IsNull() {
+			diags.AddAttributeError(hostPCIPath, "Invalid PCI device", "Exactly one of device and mapping must be set")
+		}
+		if hostPCI.PCIe.ValueBool() && !config.Machine.IsUnknown() && !strings.Contains(config.Machine.ValueString(), "q35") {
+			diags.AddAttributeError(
+				hostPCIPath.AtName("pcie"),
+				"PCI Express passthrough needs the q35 machine type",
+				fmt.Sprintf("Set machine to q35 to pass hostpci%d through as PCI Express", i),
+			)
+		}
+	}
+
+	for i, usb := range config.USB {
+		if !usb.Host.IsUnknown() && !usb.Mapping.IsUnknown() && usb.Host.IsNull() == usb.Mapping.IsNull() {
+			diags.AddAttributeError(path.Root("usb").AtListIndex(i), "Invalid USB device", "Exactly one of host and mapping must be set")
+		}
+	}
+
+	return diags
+}
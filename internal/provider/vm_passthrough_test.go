@@ -0,0 +1,85 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"reflect"
+	"testing"
+)
+
+func TestReadPassthrough(t *testing.T) {
+	devices := map[string]string{
+		"hostpci0": "0000:01:00,pcie=1,x-vga=1",
+		"hostpci1": "mapping=hsm,rombar=0",
+		"usb0":     "host=046d:c52b,usb3=1",
+		"usb1":     "mapping=token",
+		"serial0":  "socket",
+		"net0":     "virtio=BC:24:11:00:00:01,bridge=vmbr0",
+	}
+
+	var model VirtualMachineResourceModel
+	readPassthrough(devices, &model)
+
+	expectedHostPCI := []VirtualMachineHostPCIModel{
+		{Device: types.StringValue("0000:01:00"), Mapping: types.StringNull(), PCIe: types.BoolValue(true), ROMBar: types.BoolValue(true), XVGA: types.BoolValue(true), MDev: types.StringNull()},
+		{Device: types.StringNull(), Mapping: types.StringValue("hsm"), PCIe: types.BoolValue(false), ROMBar: types.BoolValue(false), XVGA: types.BoolValue(false), MDev: types.StringNull()},
+	}
+	if !reflect.DeepEqual(model.HostPCI, expectedHostPCI) {
+		t.Errorf("Incorrect PCI devices. Expected %+v, got %+v", expectedHostPCI, model.HostPCI)
+	}
+	expectedUSB := []VirtualMachineUSBModel{
+		{Host: types.StringValue("046d:c52b"), Mapping: types.StringNull(), USB3: types.BoolValue(true)},
+		{Host: types.StringNull(), Mapping: types.StringValue("token"), USB3: types.BoolValue(false)},
+	}
+	if !reflect.DeepEqual(model.USB, expectedUSB) {
+		t.Errorf("Incorrect USB devices. Expected %+v, got %+v", expectedUSB, model.USB)
+	}
+	if len(model.Serial) != 1 || model.Serial[0].Device.ValueString() != "socket" {
+		t.Errorf("Incorrect serial ports returned: %+v", model.Serial)
+	}
+
+	// Reading and writing the devices should give the same configuration
+	values := passthroughValues(model)
+	delete(devices, "net0")
+	if !reflect.DeepEqual(values, devices) {
+		t.Errorf("Incorrect values. Expected %v, got %v", devices, values)
+	}
+}
+
+func TestPlanPassthroughChanges(t *testing.T) {
+	state := VirtualMachineResourceModel{
+		USB:    []VirtualMachineUSBModel{{Host: types.StringValue("046d:c52b"), Mapping: types.StringNull(), USB3: types.BoolValue(false)}},
+		Serial: []VirtualMachineSerialModel{{Device: types.StringValue("socket")}},
+	}
+	plan := VirtualMachineResourceModel{
+		USB: []VirtualMachineUSBModel{{Host: types.StringValue("046d:c52b"), Mapping: types.StringNull(), USB3: types.BoolValue(true)}},
+		HostPCI: []VirtualMachineHostPCIModel{
+			{Device: types.StringNull(), Mapping: types.StringValue("gpu"), PCIe: types.BoolValue(true), ROMBar: types.BoolValue(true), XVGA: types.BoolValue(false), MDev: types.StringValue("nvidia-63")},
+		},
+	}
+
+	devices, removed := planPassthroughChanges(plan, state)
+	expected := map[string]string{"usb0": "host=046d:c52b,usb3=1", "hostpci0": "mapping=gpu,mdev=nvidia-63,pcie=1"}
+	if !reflect.DeepEqual(devices, expected) {
+		t.Errorf("Incorrect devices. Expected %v, got %v", expected, devices)
+	}
+	if !reflect.DeepEqual(removed, []string{"serial0"}) {
+		t.Errorf("Incorrect removed devices: %v", removed)
+	}
+}
+
+func TestValidatePassthrough(t *testing.T) {
+	config := VirtualMachineResourceModel{
+		Machine: types.StringNull(),
+		HostPCI: []VirtualMachineHostPCIModel{{Device: types.StringValue("0000:01:00"), Mapping: types.StringNull(), PCIe: types.BoolValue(true)}},
+		USB:     []VirtualMachineUSBModel{{Host: types.StringValue("046d:c52b"), Mapping: types.StringValue("token")}},
+	}
+	if diags := validatePassthrough(config); diags.ErrorsCount() != 2 {
+		t.Errorf("Expected errors for PCI Express without q35 and the USB device, got %v", diags)
+	}
+
+	config.Machine = types.StringValue("pc-q35-8.1")
+	config.USB[0].Mapping = types.StringNull()
+	if diags := validatePassthrough(config); diags.HasError() {
+		t.Errorf("Expected the passthrough to be valid, got %v", diags)
+	}
+}
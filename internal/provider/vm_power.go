@@ -0,0 +1,102 @@
+package provider
+
+import (
+	"terraform-provider-proxmox/internal/api"
+	"time"
+)
+
+// startVirtualMachine starts the virtual machine and waits for it to be running
+func (r *virtualMachineResource) startVirtualMachine(node string, vmid int64) error {
+	upid, err := api.StartVirtualMachine(r.client, node, vmid)
+	if err != nil {
+		return err
+	}
+	return api.WaitForTask(r.client, upid, vmTaskTimeout)
+}
+
+// shutdownVirtualMachine asks the guest to shut down. When the guest has not stopped within the timeout
+// the virtual machine is stopped if forceStop is set, otherwise an error is returned.
+func (r *virtualMachineResource) shutdownVirtualMachine(node string, vmid int64, timeout int64, forceStop bool) error {
+	upid, err := api.ShutdownVirtualMachine(r.client, node, vmid, timeout, forceStop)
+	if err != nil {
+		return err
+	}
+	// Give Proxmox time to stop the virtual machine after the guest has timed out
+	return api.WaitForTask(r.client, upid, time.Duration(timeout)*time.Second+time.Minute)
+}
+
+// setPowerState starts or shuts down the virtual machine so that its power state matches started.
+// When reboot is set a running virtual machine is restarted to apply pending changes. Finally the
+// virtual machine is converted into a template if the plan asks for one.
+func (r *virtualMachineResource) setPowerState(plan VirtualMachineResourceModel, reboot bool) error {
+	node := plan.Node.ValueString()
+	vmid := plan.VMID.ValueInt64()
+
+	status, err := api.GetVirtualMachineStatus(r.client, node, vmid)
+	if err != nil {
+		return err
+	}
+	running := status.Status == "running"
+
+	started := running
+	if !plan.Started.IsNull() && !plan.Started.IsUnknown() {
+		started = plan.Started.ValueBool()
+	}
+
+	if running && (!started || reboot) {
+		err = r.shutdownVirtualMachine(node, vmid, plan.ShutdownTimeout.ValueInt64(), plan.ForceStop.ValueBool())
+		if err != nil {
+			return err
+		}
+		running = false
+	}
+
+	if !running && started {
+		return r.startVirtualMachine(node, vmid)
+	}
+	return r.convertToTemplate(plan, running)
+}
+
+// convertToTemplate turns the virtual machine into a template when the plan asks for one.
+// Only a stopped virtual machine can be converted.
+func (r *virtualMachineResource) convertToTemplate(plan VirtualMachineResourceModel, running bool) error {
+	if !plan.Template.ValueBool() {
+		return nil
+	}
+
+	node := plan.Node.ValueString()
+	vmid := plan.VMID.ValueInt64()
+	config, err := api.GetVirtualMachineConfig(r.client, node, vmid)
+	if err != nil || (config.Template != nil && bool(*config.Template)) {
+		return err
+	}
+
+	if running {
+		err = r.shutdownVirtualMachine(node, vmid, plan.ShutdownTimeout.ValueInt64(), plan.ForceStop.ValueBool())
+		if err != nil {
+			return err
+		}
+	}
+
+	upid, err := api.ConvertToTemplate(r.client, node, vmid)
+	if err != nil {
+		return err
+	}
+	return api.WaitForTask(r.client, upid, vmTaskTimeout)
+}
+
+// updatePowerState sets the power state after an update. The virtual machine is restarted when reboot_after_update
+// is set and some of the changes could not be hot-plugged, which is reported by the returned flag.
+func (r *virtualMachineResource) updatePowerState(plan VirtualMachineResourceModel) (bool, error) {
+	reboot := false
+	if plan.RebootAfterUpdate.ValueBool() {
+		changes, err := api.GetVirtualMachinePending(r.client, plan.Node.ValueString(), plan.VMID.ValueInt64())
+		if err != nil {
+			return false, err
+		}
+		for _, change := range changes {
+			reboot = reboot || change.IsPending()
+		}
+	}
+	return reboot, r.setPowerState(plan, reboot)
+}
@@ -0,0 +1,1014 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/clincha-org/proxmox-api/pkg/proxmox"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"terraform-provider-proxmox/internal/api"
+	"time"
+)
+
+var (
+	_ resource.Resource                   = &virtualMachineResource{}
+	_ resource.ResourceWithConfigure      = &virtualMachineResource{}
+	_ resource.ResourceWithImportState    = &virtualMachineResource{}
+	_ resource.ResourceWithModifyPlan     = &virtualMachineResource{}
+	_ resource.ResourceWithValidateConfig = &virtualMachineResource{}
+)
+
+// vmTaskTimeout is how long to wait for the create, update and delete tasks of a virtual machine
+const vmTaskTimeout = 10 * time.Minute
+
+type VirtualMachineResourceModel struct {
+	ID                    types.String                       `tfsdk:"id"`
+	Node                  types.String                       `tfsdk:"node"`
+	VMID                  types.Int64                        `tfsdk:"vmid"`
+	Name                  types.String                       `tfsdk:"name"`
+	Cores                 types.Int64                        `tfsdk:"cores"`
+	Sockets               types.Int64                        `tfsdk:"sockets"`
+	Memory                types.Int64                        `tfsdk:"memory"`
+	Balloon               types.Int64                        `tfsdk:"balloon"`
+	CPUType               types.String                       `tfsdk:"cpu_type"`
+	Machine               types.String                       `tfsdk:"machine"`
+	BIOS                  types.String                       `tfsdk:"bios"`
+	Boot                  types.String                       `tfsdk:"boot"`
+	OSType                types.String                       `tfsdk:"ostype"`
+	Tags                  types.Set                          `tfsdk:"tags"`
+	Description           types.String                       `tfsdk:"description"`
+	OnBoot                types.Bool                         `tfsdk:"onboot"`
+	Disks                 []VirtualMachineDiskModel          `tfsdk:"disk"`
+	DeleteUnused          types.Bool                         `tfsdk:"delete_unused"`
+	NetworkDevices        []VirtualMachineNetworkDeviceModel `tfsdk:"network_device"`
+	Pending               types.Set                          `tfsdk:"pending"`
+	Clone                 *VirtualMachineCloneModel          `tfsdk:"clone"`
+	Initialization        *VirtualMachineInitializationModel `tfsdk:"initialization"`
+	Started               types.Bool                         `tfsdk:"started"`
+	RebootAfterUpdate     types.Bool                         `tfsdk:"reboot_after_update"`
+	ShutdownTimeout       types.Int64                        `tfsdk:"shutdown_timeout"`
+	ForceStop             types.Bool                         `tfsdk:"force_stop"`
+	Template              types.Bool                         `tfsdk:"template"`
+	Agent                 *VirtualMachineAgentModel          `tfsdk:"agent"`
+	NetworkInterfaceNames types.List                         `tfsdk:"network_interface_names"`
+	MACAddresses          types.List                         `tfsdk:"mac_addresses"`
+	IPv4Addresses         types.List                         `tfsdk:"ipv4_addresses"`
+	IPv6Addresses         types.List                         `tfsdk:"ipv6_addresses"`
+	HostPCI               []VirtualMachineHostPCIModel       `tfsdk:"hostpci"`
+	USB                   []VirtualMachineUSBModel           `tfsdk:"usb"`
+	Serial                []VirtualMachineSerialModel        `tfsdk:"serial"`
+	EFIDisk               *VirtualMachineEFIDiskModel        `tfsdk:"efidisk0"`
+	TPMState              *VirtualMachineTPMStateModel       `tfsdk:"tpmstate0"`
+	Migrate               types.Bool                         `tfsdk:"migrate"`
+	MigrateWithLocalDisks types.Bool                         `tfsdk:"migrate_with_local_disks"`
+	MigrateTargetStorage  types.String                       `tfsdk:"migrate_target_storage"`
+	MigrateTimeout        types.Int64                        `tfsdk:"migrate_timeout"`
+}
+
+type virtualMachineResource struct {
+	client *proxmox.Client
+}
+
+func NewVirtualMachineResource() resource.Resource {
+	return &virtualMachineResource{}
+}
+
+func (r *virtualMachineResource) Metadata(_ context.Context, request resource.MetadataRequest, response *resource.MetadataResponse) {
+	response.TypeName = request.ProviderTypeName + "_vm"
+}
+
+func (r *virtualMachineResource) Configure(_ context.Context, request resource.ConfigureRequest, response *resource.ConfigureResponse) {
+	if request.ProviderData == nil {
+		return
+	}
+
+	client, ok := request.ProviderData.(*proxmox.Client)
+
+	if !ok {
+		response.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *proxmox.Client, got %T. Please report this issue to the developers", request.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+func (r *virtualMachineResource) Schema(_ context.Context, _ resource.SchemaRequest, response *resource.SchemaResponse) {
+	response.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"node": schema.StringAttribute{
+				Required:    true,
+				Description: "Changing the node migrates the virtual machine, unless migrate is false",
+				PlanModifiers: []planmodifier.String{
+					requiresReplaceUnlessMigrated(),
+				},
+			},
+			"vmid": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "The VMID of the virtual machine. Defaults to the lowest free VMID in the cluster",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Optional: true,
+			},
+			"cores": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(1),
+				Description: "The number of cores per socket",
+			},
+			"sockets": schema.Int64Attribute{
+				Optional: true,
+				Computed: true,
+				Default:  int64default.StaticInt64(1),
+			},
+			"memory": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(512),
+				Description: "The memory in MiB",
+			},
+			"balloon": schema.Int64Attribute{
+				Optional:    true,
+				Description: "The minimum memory in MiB when ballooning. Set to 0 to disable the balloon device",
+			},
+			"cpu_type": schema.StringAttribute{
+				Optional:    true,
+				Description: "The emulated CPU type, for example host or x86-64-v2-AES",
+			},
+			"machine": schema.StringAttribute{
+				Optional:    true,
+				Description: "The machine type, for example q35 or pc",
+			},
+			"bios": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString("seabios"),
+				Description: "Either seabios or ovmf",
+			},
+			"boot": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "The boot order, for example order=scsi0;net0",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"ostype": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString("other"),
+				Description: "The guest operating system, for example l26 or win11",
+			},
+			"tags": schema.SetAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"description": schema.StringAttribute{
+				Optional: true,
+			},
+			"onboot": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Start the virtual machine when the node boots",
+			},
+			"delete_unused": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Destroy the volumes of disks that are removed from the configuration instead of keeping them as unused disks",
+			},
+			"template": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Convert the virtual machine into a template. A template can not be turned back into a virtual machine, so it is replaced instead",
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplaceIf(
+						func(_ context.Context, request planmodifier.BoolRequest, response *boolplanmodifier.RequiresReplaceIfFuncResponse) {
+							response.RequiresReplace = request.StateValue.ValueBool() && !request.PlanValue.ValueBool()
+						},
+						"A template can not be turned back into a virtual machine",
+						"A template can not be turned back into a virtual machine",
+					),
+				},
+			},
+			"started": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Whether the virtual machine should be running. When not set the power state is left alone",
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"reboot_after_update": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Restart a running virtual machine when an update leaves changes pending",
+			},
+			"shutdown_timeout": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(180),
+				Description: "The number of seconds to wait for the guest to shut down",
+			},
+			"force_stop": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Stop the virtual machine when the guest has not shut down within the shutdown timeout",
+			},
+			"migrate": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(true),
+				Description: "Migrate the virtual machine when the node changes. When false it is replaced instead",
+			},
+			"migrate_with_local_disks": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(true),
+				Description: "Copy disks on local storage to the new node when the virtual machine is migrated",
+			},
+			"migrate_target_storage": schema.StringAttribute{
+				Optional:    true,
+				Description: "The storage on the new node for the local disks. Defaults to a storage with the same name",
+			},
+			"migrate_timeout": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(1800),
+				Description: "The number of seconds to wait for a migration to finish",
+			},
+			"network_interface_names": schema.ListAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "The names of the network interfaces in the guest, reported by the guest agent",
+			},
+			"mac_addresses": schema.ListAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "The MAC address of each network interface in the guest",
+			},
+			"ipv4_addresses": schema.ListAttribute{
+				Computed:    true,
+				ElementType: types.ListType{ElemType: types.StringType},
+				Description: "The IPv4 addresses of each network interface in the guest, without loopback and link-local addresses",
+			},
+			"ipv6_addresses": schema.ListAttribute{
+				Computed:    true,
+				ElementType: types.ListType{ElemType: types.StringType},
+				Description: "The IPv6 addresses of each network interface in the guest, without loopback and link-local addresses",
+			},
+			"pending": schema.SetAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "The configuration keys, for example net0, with changes that could not be hot-plugged and take effect when the virtual machine is restarted",
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"disk":           virtualMachineDiskBlock(),
+			"network_device": virtualMachineNetworkDeviceBlock(),
+			"clone":          virtualMachineCloneBlock(),
+			"initialization": virtualMachineInitializationBlock(),
+			"agent":          virtualMachineAgentBlock(),
+			"hostpci":        virtualMachineHostPCIBlock(),
+			"usb":            virtualMachineUSBBlock(),
+			"serial":         virtualMachineSerialBlock(),
+			"efidisk0":       virtualMachineEFIDiskBlock(),
+			"tpmstate0":      virtualMachineTPMStateBlock(),
+		},
+	}
+}
+
+func (r *virtualMachineResource) ValidateConfig(ctx context.Context, request resource.ValidateConfigRequest, response *resource.ValidateConfigResponse) {
+	var config VirtualMachineResourceModel
+	response.Diagnostics.Append(request.Config.Get(ctx, &config)...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	response.Diagnostics.Append(validateClone(config.Clone)...)
+	response.Diagnostics.Append(validateInitialization(config.Initialization, config.Disks)...)
+	response.Diagnostics.Append(validatePassthrough(config)...)
+	response.Diagnostics.Append(validateEFI(config)...)
+
+	if config.Template.ValueBool() && config.Started.ValueBool() {
+		response.Diagnostics.AddAttributeError(
+			path.Root("started"),
+			"Templates can not be started",
+			"Remove started or set it to false when template is true",
+		)
+	}
+}
+
+func (r *virtualMachineResource) ModifyPlan(ctx context.Context, request resource.ModifyPlanRequest, response *resource.ModifyPlanResponse) {
+	// Nothing to check when the virtual machine is being destroyed
+	if request.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan, state VirtualMachineResourceModel
+	response.Diagnostics.Append(request.Plan.Get(ctx, &plan)...)
+	if !request.State.Raw.IsNull() {
+		response.Diagnostics.Append(request.State.Get(ctx, &state)...)
+	}
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	response.Diagnostics.Append(validateDiskPlan(plan.Disks, state.Disks)...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+	// A migration can move local disks to another storage, so their volumes are only known afterwards
+	if !request.State.Raw.IsNull() && plan.Node.ValueString() != state.Node.ValueString() {
+		planDiskVolumes(plan.Disks, nil)
+		if plan.EFIDisk != nil {
+			plan.EFIDisk.Volume = types.StringUnknown()
+		}
+		if plan.TPMState != nil {
+			plan.TPMState.Volume = types.StringUnknown()
+		}
+	} else {
+		planDiskVolumes(plan.Disks, state.Disks)
+	}
+
+	if plan.Clone != nil {
+		var config VirtualMachineResourceModel
+		response.Diagnostics.Append(request.Config.Get(ctx, &config)...)
+		planCloneDefaults(&plan, config, state, !request.State.Raw.IsNull())
+	}
+
+	// Templates are never running
+	if plan.Template.ValueBool() {
+		plan.Started = types.BoolValue(false)
+	}
+
+	response.Diagnostics.Append(response.Plan.Set(ctx, plan)...)
+}
+
+func (r *virtualMachineResource) ImportState(ctx context.Context, request resource.ImportStateRequest, response *resource.ImportStateResponse) {
+	importGuest(ctx, request, response)
+}
+
+// importGuest imports a virtual machine or container. The ID is a combination of the name of the node and the VMID.
+func importGuest(ctx context.Context, request resource.ImportStateRequest, response *resource.ImportStateResponse) {
+	idParts := strings.Split(request.ID, "/")
+	var vmid int64
+	var err error
+	if len(idParts) == 2 && idParts[0] != "" {
+		vmid, err = strconv.ParseInt(idParts[1], 10, 64)
+	}
+	if len(idParts) != 2 || idParts[0] == "" || err != nil {
+		response.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Please provide the identifier in the format: node/vmid. For example: pve/100. Got: %q", request.ID),
+		)
+		return
+	}
+	response.Diagnostics.Append(response.State.SetAttribute(ctx, path.Root("node"), idParts[0])...)
+	response.Diagnostics.Append(response.State.SetAttribute(ctx, path.Root("vmid"), vmid)...)
+}
+
+// virtualMachineRequest builds the API request from the plan. Attributes that are null in the plan
+// are not sent, removing them from an existing virtual machine is handled by the caller.
+func virtualMachineRequest(ctx context.Context, plan VirtualMachineResourceModel) (api.VirtualMachineRequest, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	vmRequest := api.VirtualMachineRequest{
+		Name:        plan.Name.ValueStringPointer(),
+		Cores:       plan.Cores.ValueInt64Pointer(),
+		Sockets:     plan.Sockets.ValueInt64Pointer(),
+		Memory:      plan.Memory.ValueInt64Pointer(),
+		Balloon:     plan.Balloon.ValueInt64Pointer(),
+		CPU:         plan.CPUType.ValueStringPointer(),
+		Machine:     plan.Machine.ValueStringPointer(),
+		BIOS:        plan.BIOS.ValueStringPointer(),
+		OSType:      plan.OSType.ValueStringPointer(),
+		Description: plan.Description.ValueStringPointer(),
+		OnBoot:      api.NewBool(plan.OnBoot.ValueBoolPointer()),
+		Agent:       agentValue(plan.Agent),
+	}
+
+	if !plan.Boot.IsUnknown() {
+		vmRequest.Boot = plan.Boot.ValueStringPointer()
+	}
+
+	if !plan.Tags.IsNull() && !plan.Tags.IsUnknown() {
+		var tags []string
+		diags.Append(plan.Tags.ElementsAs(ctx, &tags, false)...)
+		joined := strings.Join(tags, ";")
+		vmRequest.Tags = &joined
+	}
+
+	return vmRequest, diags
+}
+
+// readVirtualMachine refreshes the model from the configuration that Proxmox has for the virtual machine.
+// Proxmox leaves out attributes that are at their default, those are set to the defaults of the schema.
+// A clone only has the settings and devices of its source that the model declares.
+func (r *virtualMachineResource) readVirtualMachine(model *VirtualMachineResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+	prior := *model
+
+	node := model.Node.ValueString()
+	vmid := model.VMID.ValueInt64()
+	config, err := api.GetVirtualMachineConfig(r.client, node, vmid)
+	if err != nil {
+		diags.AddError(
+			"Error reading Proxmox virtual machine",
+			fmt.Sprintf("Could not read the Proxmox virtual machine: %s/%d: %s", node, vmid, err.Error()),
+		)
+		return diags
+	}
+
+	status, err := api.GetVirtualMachineStatus(r.client, node, vmid)
+	if err != nil {
+		diags.AddError(
+			"Error reading Proxmox virtual machine",
+			fmt.Sprintf("Could not read the status of the Proxmox virtual machine: %s/%d: %s", node, vmid, err.Error()),
+		)
+		return diags
+	}
+
+	model.ID = types.StringValue(strconv.FormatInt(vmid, 10))
+	model.Started = types.BoolValue(status.Status == "running")
+
+	// Settings of the provider are not stored by Proxmox, use the defaults after an import
+	if model.DeleteUnused.IsNull() {
+		model.DeleteUnused = types.BoolValue(false)
+	}
+	if model.RebootAfterUpdate.IsNull() {
+		model.RebootAfterUpdate = types.BoolValue(false)
+	}
+	if model.ShutdownTimeout.IsNull() {
+		model.ShutdownTimeout = types.Int64Value(180)
+	}
+	if model.ForceStop.IsNull() {
+		model.ForceStop = types.BoolValue(false)
+	}
+	if model.Migrate.IsNull() {
+		model.Migrate = types.BoolValue(true)
+	}
+	if model.MigrateWithLocalDisks.IsNull() {
+		model.MigrateWithLocalDisks = types.BoolValue(true)
+	}
+	if model.MigrateTimeout.IsNull() {
+		model.MigrateTimeout = types.Int64Value(1800)
+	}
+	model.Name = types.StringPointerValue(config.Name)
+	model.Cores = types.Int64Value(valueOrDefault(config.Cores.Pointer(), 1))
+	model.Sockets = types.Int64Value(valueOrDefault(config.Sockets.Pointer(), 1))
+	model.Memory = types.Int64Value(valueOrDefault(config.Memory.Pointer(), 512))
+	model.Balloon = types.Int64PointerValue(config.Balloon.Pointer())
+	model.CPUType = types.StringPointerValue(config.CPU)
+	model.Machine = types.StringPointerValue(config.Machine)
+	model.BIOS = types.StringValue(valueOrDefault(config.BIOS, "seabios"))
+	model.Boot = types.StringPointerValue(config.Boot)
+	model.OSType = types.StringValue(valueOrDefault(config.OSType, "other"))
+	model.OnBoot = types.BoolValue(config.OnBoot != nil && bool(*config.OnBoot))
+	model.Template = types.BoolValue(config.Template != nil && bool(*config.Template))
+	model.Agent = readAgent(config.Agent, model.Agent)
+
+	// Proxmox stores the description as a comment and adds a newline to the end
+	model.Description = types.StringNull()
+	if config.Description != nil {
+		model.Description = types.StringValue(strings.TrimRight(*config.Description, "\n"))
+	}
+
+	disks, err := readDisks(config.Devices, model.Disks)
+	if err != nil {
+		diags.AddError(
+			"Error reading Proxmox virtual machine",
+			fmt.Sprintf("Could not read the disks of the Proxmox virtual machine: %s/%d: %s", node, vmid, err.Error()),
+		)
+		return diags
+	}
+	model.Disks = disks
+
+	networkDevices, err := readNetworkDevices(config.Devices)
+	if err != nil {
+		diags.AddError(
+			"Error reading Proxmox virtual machine",
+			fmt.Sprintf("Could not read the network devices of the Proxmox virtual machine: %s/%d: %s", node, vmid, err.Error()),
+		)
+		return diags
+	}
+	model.NetworkDevices = networkDevices
+	readPassthrough(config.Devices, model)
+	model.EFIDisk = readEFIDisk(config.Devices)
+	model.TPMState = readTPMState(config.Devices)
+
+	model.Initialization, err = readInitialization(config, model.Initialization)
+	if err != nil {
+		diags.AddError(
+			"Error reading Proxmox virtual machine",
+			fmt.Sprintf("Could not read the cloud-init settings of the Proxmox virtual machine: %s/%d: %s", node, vmid, err.Error()),
+		)
+		return diags
+	}
+
+	changes, err := api.GetVirtualMachinePending(r.client, node, vmid)
+	if err != nil {
+		diags.AddError(
+			"Error reading Proxmox virtual machine",
+			fmt.Sprintf("Could not read the pending changes of the Proxmox virtual machine: %s/%d: %s", node, vmid, err.Error()),
+		)
+		return diags
+	}
+	var pending []attr.Value
+	for _, change := range changes {
+		if change.IsPending() {
+			pending = append(pending, types.StringValue(change.Key))
+		}
+	}
+	var setDiags diag.Diagnostics
+	model.Pending, setDiags = types.SetValue(types.StringType, pending)
+	diags.Append(setDiags...)
+
+	tags, listDiags := splitList(config.Tags)
+	diags.Append(listDiags...)
+	model.Tags = types.SetNull(types.StringType)
+	if !tags.IsNull() {
+		var setDiags diag.Diagnostics
+		model.Tags, setDiags = types.SetValue(types.StringType, tags.Elements())
+		diags.Append(setDiags...)
+	}
+
+	if model.Clone != nil {
+		keepDeclared(model, prior)
+	}
+	r.readGuestAddresses(model)
+
+	return diags
+}
+
+func valueOrDefault[T any](value *T, defaultValue T) T {
+	if value == nil {
+		return defaultValue
+	}
+	return *value
+}
+
+func (r *virtualMachineResource) Create(ctx context.Context, request resource.CreateRequest, response *resource.CreateResponse) {
+	var plan VirtualMachineResourceModel
+	diags := request.Plan.Get(ctx, &plan)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.VMID.IsUnknown() {
+		vmid, err := allocator.allocate(r.client, 0, 0)
+		if err != nil {
+			response.Diagnostics.AddError(
+				"Error creating Proxmox virtual machine",
+				"Could not find a free VMID for the Proxmox virtual machine: "+err.Error(),
+			)
+			return
+		}
+		plan.VMID = types.Int64Value(vmid)
+	}
+
+	if plan.Clone != nil {
+		r.createClone(ctx, plan, response)
+		return
+	}
+
+	vmRequest, diags := virtualMachineRequest(ctx, plan)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+	vmRequest.VMID = plan.VMID.ValueInt64Pointer()
+	vmRequest.Devices = map[string]string{}
+	for _, disk := range plan.Disks {
+		vmRequest.Devices[disk.Interface.ValueString()] = newDiskValue(disk)
+	}
+	for i, device := range plan.NetworkDevices {
+		value, diags := networkDeviceValue(ctx, device)
+		response.Diagnostics.Append(diags...)
+		vmRequest.Devices[fmt.Sprintf("net%d", i)] = value
+	}
+	for key, value := range passthroughValues(plan) {
+		vmRequest.Devices[key] = value
+	}
+	if plan.EFIDisk != nil {
+		vmRequest.Devices["efidisk0"] = newEFIDiskValue(plan.EFIDisk)
+	}
+	if plan.TPMState != nil {
+		vmRequest.Devices["tpmstate0"] = newTPMStateValue(plan.TPMState)
+	}
+	initialization, diags := initializationValues(ctx, plan.Initialization)
+	response.Diagnostics.Append(diags...)
+	addInitializationValues(&vmRequest, initialization)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	upid, err := api.CreateVirtualMachine(r.client, plan.Node.ValueString(), &vmRequest)
+	if err == nil {
+		err = api.WaitForTask(r.client, upid, vmTaskTimeout)
+	}
+	if err == nil {
+		err = r.setPowerState(plan, false)
+	}
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error creating Proxmox virtual machine",
+			fmt.Sprintf("Could not create the Proxmox virtual machine: %d: %s", plan.VMID.ValueInt64(), err.Error()),
+		)
+		return
+	}
+
+	response.Diagnostics.Append(r.waitForGuestAddresses(plan)...)
+	response.Diagnostics.Append(r.readVirtualMachine(&plan)...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	diags = response.State.Set(ctx, plan)
+	response.Diagnostics.Append(diags...)
+}
+
+// createClone clones the source of the clone block and then applies the rest of the plan to the clone.
+// The clone is saved to the state when applying the plan fails, so that Terraform can replace it.
+func (r *virtualMachineResource) createClone(ctx context.Context, plan VirtualMachineResourceModel, response *resource.CreateResponse) {
+	err := r.cloneVirtualMachine(plan)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error creating Proxmox virtual machine",
+			fmt.Sprintf("Could not clone the Proxmox virtual machine: %d: %s", plan.VMID.ValueInt64(), err.Error()),
+		)
+		return
+	}
+
+	clone := plan
+	response.Diagnostics.Append(r.readVirtualMachine(&clone)...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+	// The password of the source can not be compared, so always set the planned one
+	if clone.Initialization != nil {
+		clone.Initialization.CIPassword = types.StringNull()
+	}
+	setCloneDefaults(&plan, clone)
+
+	// The size of the cloned disks is only known now, they can grow but not shrink
+	diags := validateDiskPlan(plan.Disks, clone.Disks)
+	if !diags.HasError() {
+		var err error
+		diags, err = r.applyChanges(ctx, plan, clone)
+		if err == nil && !diags.HasError() {
+			err = r.setPowerState(plan, false)
+		}
+		if err != nil {
+			diags.AddError(
+				"Error creating Proxmox virtual machine",
+				fmt.Sprintf("Could not configure the clone of the Proxmox virtual machine: %d: %s", plan.VMID.ValueInt64(), err.Error()),
+			)
+		}
+	}
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		response.Diagnostics.Append(response.State.Set(ctx, clone)...)
+		return
+	}
+
+	response.Diagnostics.Append(r.waitForGuestAddresses(plan)...)
+	response.Diagnostics.Append(r.readVirtualMachine(&plan)...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	response.Diagnostics.Append(response.State.Set(ctx, plan)...)
+}
+
+func (r *virtualMachineResource) Read(ctx context.Context, request resource.ReadRequest, response *resource.ReadResponse) {
+	var state VirtualMachineResourceModel
+	diags := request.State.Get(ctx, &state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	response.Diagnostics.Append(r.readVirtualMachine(&state)...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	diags = response.State.Set(ctx, &state)
+	response.Diagnostics.Append(diags...)
+}
+
+func (r *virtualMachineResource) Update(ctx context.Context, request resource.UpdateRequest, response *resource.UpdateResponse) {
+	var plan, state VirtualMachineResourceModel
+	response.Diagnostics.Append(request.Plan.Get(ctx, &plan)...)
+	response.Diagnostics.Append(request.State.Get(ctx, &state)...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	// The configuration is changed on the new node, so the virtual machine is migrated first.
+	// Its disks may have moved to another storage, so the state is read again.
+	migrated := plan.Node.ValueString() != state.Node.ValueString()
+	if migrated {
+		err := r.migrateVirtualMachine(plan, state)
+		if err != nil {
+			response.Diagnostics.AddError(
+				"Error migrating Proxmox virtual machine",
+				fmt.Sprintf("Could not migrate the Proxmox virtual machine %d from %s to %s: %s", state.VMID.ValueInt64(), state.Node.ValueString(), plan.Node.ValueString(), err.Error()),
+			)
+			return
+		}
+		state.Node = plan.Node
+		response.Diagnostics.Append(r.readVirtualMachine(&state)...)
+		if response.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	diags, err := r.applyChanges(ctx, plan, state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+	rebooted := false
+	if err == nil {
+		rebooted, err = r.updatePowerState(plan)
+	}
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error updating Proxmox virtual machine",
+			fmt.Sprintf("Could not update the Proxmox virtual machine: %d. Got this error: %s", plan.VMID.ValueInt64(), err.Error()),
+		)
+		return
+	}
+
+	if migrated || rebooted || guestAddressesMayChange(plan, state) {
+		response.Diagnostics.Append(r.waitForGuestAddresses(plan)...)
+	}
+	response.Diagnostics.Append(r.readVirtualMachine(&plan)...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	var pending []string
+	response.Diagnostics.Append(plan.Pending.ElementsAs(ctx, &pending, false)...)
+	if len(pending) > 0 {
+		sort.Strings(pending)
+		response.Diagnostics.AddWarning(
+			"Proxmox virtual machine has pending changes",
+			fmt.Sprintf("Some changes to the Proxmox virtual machine %d could not be hot-plugged and take effect when it is restarted: %s", plan.VMID.ValueInt64(), strings.Join(pending, ", ")),
+		)
+	}
+
+	diags = response.State.Set(ctx, plan)
+	response.Diagnostics.Append(diags...)
+}
+
+// virtualMachineChanges describes how the configuration of an existing virtual machine needs to change
+type virtualMachineChanges struct {
+	// request has the attributes and devices that are new or have changed and the ones to delete
+	request        api.VirtualMachineRequest
+	disks          diskChanges
+	initialization initializationChanges
+}
+
+// empty reports whether the request has nothing to change
+func (c virtualMachineChanges) empty() bool {
+	request := c.request
+	request.Devices = nil
+	return len(c.request.Devices) == 0 && reflect.DeepEqual(request, api.VirtualMachineRequest{})
+}
+
+// planVirtualMachineChanges returns the changes from the state to the plan. Only the attributes that changed are
+// sent, so the settings of a clone that are not configured keep the values of its source.
+func planVirtualMachineChanges(ctx context.Context, plan VirtualMachineResourceModel, state VirtualMachineResourceModel) (virtualMachineChanges, diag.Diagnostics, error) {
+	var changes virtualMachineChanges
+	vmRequest, diags := virtualMachineRequest(ctx, plan)
+	current, currentDiags := virtualMachineRequest(ctx, state)
+	diags.Append(currentDiags...)
+	if diags.HasError() {
+		return changes, diags, nil
+	}
+
+	var removed removedAttributes
+	removed.check("name", plan.Name, state.Name)
+	removed.check("balloon", plan.Balloon, state.Balloon)
+	removed.check("cpu", plan.CPUType, state.CPUType)
+	removed.check("machine", plan.Machine, state.Machine)
+	removed.check("tags", plan.Tags, state.Tags)
+	removed.check("description", plan.Description, state.Description)
+	if plan.Agent == nil && state.Agent != nil {
+		removed = append(removed, "agent")
+	}
+
+	keepChanged(&vmRequest.Name, current.Name)
+	keepChanged(&vmRequest.Cores, current.Cores)
+	keepChanged(&vmRequest.Sockets, current.Sockets)
+	keepChanged(&vmRequest.Memory, current.Memory)
+	keepChanged(&vmRequest.Balloon, current.Balloon)
+	keepChanged(&vmRequest.CPU, current.CPU)
+	keepChanged(&vmRequest.Machine, current.Machine)
+	keepChanged(&vmRequest.BIOS, current.BIOS)
+	keepChanged(&vmRequest.Boot, current.Boot)
+	keepChanged(&vmRequest.OSType, current.OSType)
+	keepChanged(&vmRequest.Tags, current.Tags)
+	keepChanged(&vmRequest.Description, current.Description)
+	keepChanged(&vmRequest.OnBoot, current.OnBoot)
+	keepChanged(&vmRequest.Agent, current.Agent)
+
+	disks := planDiskChanges(plan.Disks, state.Disks)
+	if err := planEFIChanges(plan, state, &disks); err != nil {
+		return changes, diags, err
+	}
+	vmRequest.Devices = disks.devices
+	removed = append(removed, disks.removed...)
+
+	networkDevices, removedNetworkDevices, networkDiags := planNetworkDeviceChanges(ctx, plan.NetworkDevices, state.NetworkDevices)
+	diags.Append(networkDiags...)
+	if diags.HasError() {
+		return changes, diags, nil
+	}
+	for key, value := range networkDevices {
+		vmRequest.Devices[key] = value
+	}
+	removed = append(removed, removedNetworkDevices...)
+
+	passthroughDevices, removedPassthroughDevices := planPassthroughChanges(plan, state)
+	for key, value := range passthroughDevices {
+		vmRequest.Devices[key] = value
+	}
+	removed = append(removed, removedPassthroughDevices...)
+
+	initialization, initializationDiags := planInitializationChanges(ctx, plan.Initialization, state.Initialization)
+	diags.Append(initializationDiags...)
+	if diags.HasError() {
+		return changes, diags, nil
+	}
+	addInitializationValues(&vmRequest, initialization.values)
+	removed = append(removed, initialization.removed...)
+	vmRequest.Delete = removed.value()
+
+	return virtualMachineChanges{request: vmRequest, disks: disks, initialization: initialization}, diags, nil
+}
+
+// applyChanges changes the configuration of the virtual machine from the state to the plan. The diagnostics
+// report problems with the plan and the error reports problems with Proxmox.
+func (r *virtualMachineResource) applyChanges(ctx context.Context, plan VirtualMachineResourceModel, state VirtualMachineResourceModel) (diag.Diagnostics, error) {
+	changes, diags, err := planVirtualMachineChanges(ctx, plan, state)
+	if err != nil || diags.HasError() {
+		return diags, err
+	}
+	disks, initialization := changes.disks, changes.initialization
+
+	node := plan.Node.ValueString()
+	vmid := plan.VMID.ValueInt64()
+
+	// Proxmox can not replace a cloud-init drive in place, it has to be removed first
+	if initialization.replaceDrive != "" {
+		upid, err := api.UpdateVirtualMachineConfig(r.client, node, vmid, &api.VirtualMachineRequest{Delete: &initialization.replaceDrive})
+		if err == nil {
+			err = api.WaitForTask(r.client, upid, vmTaskTimeout)
+		}
+		if err != nil {
+			return diags, err
+		}
+	}
+
+	// Proxmox rejects an update without any options
+	if !changes.empty() {
+		var upid string
+		upid, err = api.UpdateVirtualMachineConfig(r.client, node, vmid, &changes.request)
+		if err == nil {
+			err = api.WaitForTask(r.client, upid, vmTaskTimeout)
+		}
+	}
+	if err == nil {
+		err = r.updateDisks(node, vmid, disks, plan.DeleteUnused.ValueBool())
+	}
+	if err == nil && plan.Initialization != nil && initialization.changed() {
+		err = api.RegenerateCloudInit(r.client, node, vmid)
+	}
+	return diags, err
+}
+
+// updateDisks moves and grows disks once the configuration has been updated. Detached disks are kept
+// by Proxmox as unused disks, their volumes are only destroyed when deleteUnused is set.
+func (r *virtualMachineResource) updateDisks(node string, vmid int64, disks diskChanges, deleteUnused bool) error {
+	for _, disk := range sortedKeys(disks.moves) {
+		upid, err := api.MoveVirtualMachineDisk(r.client, node, vmid, disk, disks.moves[disk])
+		if err == nil {
+			err = api.WaitForTask(r.client, upid, vmTaskTimeout)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, disk := range sortedKeys(disks.resizes) {
+		upid, err := api.ResizeVirtualMachineDisk(r.client, node, vmid, disk, fmt.Sprintf("%dG", disks.resizes[disk]))
+		if err == nil {
+			err = api.WaitForTask(r.client, upid, vmTaskTimeout)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if !deleteUnused || len(disks.removedVolumes) == 0 {
+		return nil
+	}
+
+	config, err := api.GetVirtualMachineConfig(r.client, node, vmid)
+	if err != nil {
+		return err
+	}
+	unused := unusedDisks(config.Devices, disks.removedVolumes)
+	if len(unused) == 0 {
+		return nil
+	}
+
+	remove := strings.Join(unused, ",")
+	upid, err := api.UpdateVirtualMachineConfig(r.client, node, vmid, &api.VirtualMachineRequest{Delete: &remove})
+	if err == nil {
+		err = api.WaitForTask(r.client, upid, vmTaskTimeout)
+	}
+	return err
+}
+
+func sortedKeys[T any](values map[string]T) []string {
+	var keys []string
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (r *virtualMachineResource) Delete(ctx context.Context, request resource.DeleteRequest, response *resource.DeleteResponse) {
+	var state VirtualMachineResourceModel
+	diags := request.State.Get(ctx, &state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	node := state.Node.ValueString()
+	vmid := state.VMID.ValueInt64()
+
+	// Proxmox refuses to destroy a running virtual machine
+	status, err := api.GetVirtualMachineStatus(r.client, node, vmid)
+	if err == nil && status.Status != "stopped" {
+		var upid string
+		upid, err = api.StopVirtualMachine(r.client, node, vmid)
+		if err == nil {
+			err = api.WaitForTask(r.client, upid, vmTaskTimeout)
+		}
+	}
+	if err == nil {
+		var upid string
+		upid, err = api.DeleteVirtualMachine(r.client, node, vmid)
+		if err == nil {
+			err = api.WaitForTask(r.client, upid, vmTaskTimeout)
+		}
+	}
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error deleting Proxmox virtual machine",
+			fmt.Sprintf("Could not delete the Proxmox virtual machine: %d. Got this error: %s", vmid, err.Error()),
+		)
+		return
+	}
+}
@@ -0,0 +1,196 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"testing"
+)
+
+func TestVirtualMachineResource_Create(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+resource "proxmox_vm" "test" {
+  node        = "pve"
+  vmid        = 9001
+  name        = "terraform-test"
+  cores       = 2
+  memory      = 1024
+  ostype      = "l26"
+  tags        = ["terraform", "test"]
+  description = "Test virtual machine"
+
+  disk {
+    interface = "scsi0"
+    storage   = "local-lvm"
+    size      = 8
+  }
+
+  network_device {
+    bridge   = "vmbr0"
+    firewall = true
+  }
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("proxmox_vm.test", "id", "9001"),
+					resource.TestCheckResourceAttr("proxmox_vm.test", "name", "terraform-test"),
+					resource.TestCheckResourceAttr("proxmox_vm.test", "cores", "2"),
+					resource.TestCheckResourceAttr("proxmox_vm.test", "sockets", "1"),
+					resource.TestCheckResourceAttr("proxmox_vm.test", "memory", "1024"),
+					resource.TestCheckResourceAttr("proxmox_vm.test", "bios", "seabios"),
+					resource.TestCheckResourceAttr("proxmox_vm.test", "ostype", "l26"),
+					resource.TestCheckResourceAttr("proxmox_vm.test", "tags.#", "2"),
+					resource.TestCheckResourceAttr("proxmox_vm.test", "onboot", "false"),
+					resource.TestCheckResourceAttr("proxmox_vm.test", "started", "false"),
+					resource.TestCheckResourceAttr("proxmox_vm.test", "disk.#", "1"),
+					resource.TestCheckResourceAttr("proxmox_vm.test", "disk.0.size", "8"),
+					resource.TestCheckResourceAttrSet("proxmox_vm.test", "disk.0.volume"),
+					resource.TestCheckResourceAttr("proxmox_vm.test", "network_device.#", "1"),
+					resource.TestCheckResourceAttr("proxmox_vm.test", "network_device.0.model", "virtio"),
+					resource.TestCheckResourceAttrSet("proxmox_vm.test", "network_device.0.mac"),
+					resource.TestCheckResourceAttr("proxmox_vm.test", "pending.#", "0"),
+				),
+			},
+			{
+				ResourceName:      "proxmox_vm.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateId:     "pve/9001",
+			},
+			{
+				Config: providerConfig + `
+resource "proxmox_network_bridge" "vmbr88" {
+  interface = "vmbr88"
+  node      = "pve"
+}
+
+resource "proxmox_vm" "test" {
+  node    = "pve"
+  vmid    = 9001
+  name    = "terraform-test-renamed"
+  cores   = 4
+  memory  = 2048
+  balloon = 1024
+  ostype  = "l26"
+  onboot  = true
+  started = true
+
+  shutdown_timeout = 60
+  force_stop       = true
+
+  disk {
+    interface = "scsi0"
+    storage   = "local-lvm"
+    size      = 16
+    ssd       = true
+  }
+
+  disk {
+    interface = "scsi1"
+    storage   = "local-lvm"
+    size      = 4
+  }
+
+  network_device {
+    bridge   = "vmbr0"
+    firewall = true
+  }
+
+  network_device {
+    model  = "e1000"
+    bridge = proxmox_network_bridge.vmbr88.interface
+    tag    = 88
+  }
+
+  serial {
+    device = "socket"
+  }
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("proxmox_vm.test", "name", "terraform-test-renamed"),
+					resource.TestCheckResourceAttr("proxmox_vm.test", "cores", "4"),
+					resource.TestCheckResourceAttr("proxmox_vm.test", "balloon", "1024"),
+					resource.TestCheckNoResourceAttr("proxmox_vm.test", "tags"),
+					resource.TestCheckNoResourceAttr("proxmox_vm.test", "description"),
+					resource.TestCheckResourceAttr("proxmox_vm.test", "onboot", "true"),
+					resource.TestCheckResourceAttr("proxmox_vm.test", "started", "true"),
+					resource.TestCheckResourceAttr("proxmox_vm.test", "disk.#", "2"),
+					resource.TestCheckResourceAttr("proxmox_vm.test", "disk.0.size", "16"),
+					resource.TestCheckResourceAttr("proxmox_vm.test", "disk.0.ssd", "true"),
+					resource.TestCheckResourceAttr("proxmox_vm.test", "network_device.#", "2"),
+					resource.TestCheckResourceAttr("proxmox_vm.test", "network_device.1.bridge", "vmbr88"),
+					resource.TestCheckResourceAttr("proxmox_vm.test", "network_device.1.tag", "88"),
+					resource.TestCheckResourceAttr("proxmox_vm.test", "serial.0.device", "socket"),
+				),
+			},
+		},
+	})
+}
+
+func TestVirtualMachineResource_Clone(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+resource "proxmox_vm" "test" {
+  node   = "pve"
+  vmid   = 9002
+  name   = "terraform-clone"
+  memory = 1024
+  ostype = "l26"
+
+  started = true
+
+  agent {
+    enabled = true
+  }
+
+  clone {
+    source_name = "debian-template"
+    full        = false
+  }
+
+  disk {
+    interface = "scsi0"
+    storage   = "local-lvm"
+    size      = 16
+  }
+
+  network_device {
+    bridge = "vmbr0"
+  }
+
+  initialization {
+    storage    = "local-lvm"
+    ciuser     = "terraform"
+    cipassword = "terraform"
+    sshkeys    = ["ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIFakeKeyForTesting terraform@test"]
+
+    ipconfig {
+      ip = "dhcp"
+    }
+  }
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("proxmox_vm.test", "id", "9002"),
+					resource.TestCheckResourceAttr("proxmox_vm.test", "initialization.interface", "ide2"),
+					resource.TestCheckResourceAttr("proxmox_vm.test", "initialization.ciuser", "terraform"),
+					resource.TestCheckResourceAttr("proxmox_vm.test", "initialization.sshkeys.#", "1"),
+					resource.TestCheckResourceAttr("proxmox_vm.test", "initialization.ipconfig.0.ip", "dhcp"),
+					resource.TestCheckResourceAttr("proxmox_vm.test", "agent.enabled", "true"),
+					resource.TestCheckResourceAttrSet("proxmox_vm.test", "ipv4_addresses.0.0"),
+					resource.TestCheckResourceAttr("proxmox_vm.test", "name", "terraform-clone"),
+					resource.TestCheckResourceAttr("proxmox_vm.test", "memory", "1024"),
+					resource.TestCheckResourceAttr("proxmox_vm.test", "clone.full", "false"),
+					resource.TestCheckResourceAttr("proxmox_vm.test", "disk.0.size", "16"),
+					resource.TestCheckResourceAttrSet("proxmox_vm.test", "network_device.0.mac"),
+				),
+			},
+		},
+	})
+}
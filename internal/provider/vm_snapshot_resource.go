@@ -0,0 +1,373 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/clincha-org/proxmox-api/pkg/proxmox"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"strconv"
+	"strings"
+	"sync"
+	"terraform-provider-proxmox/internal/api"
+)
+
+var (
+	_ resource.Resource                = &virtualMachineSnapshotResource{}
+	_ resource.ResourceWithConfigure   = &virtualMachineSnapshotResource{}
+	_ resource.ResourceWithImportState = &virtualMachineSnapshotResource{}
+	_ resource.ResourceWithModifyPlan  = &virtualMachineSnapshotResource{}
+)
+
+// snapshotMutex serialises the snapshot tasks. Proxmox locks a virtual machine while a snapshot is taken,
+// rolled back or deleted, so two snapshots of the same virtual machine can not be changed in parallel.
+var snapshotMutex sync.Mutex
+
+type VirtualMachineSnapshotResourceModel struct {
+	ID                types.String `tfsdk:"id"`
+	Node              types.String `tfsdk:"node"`
+	VMID              types.Int64  `tfsdk:"vmid"`
+	Name              types.String `tfsdk:"name"`
+	Description       types.String `tfsdk:"description"`
+	IncludeRAM        types.Bool   `tfsdk:"include_ram"`
+	RollbackOnDestroy types.Bool   `tfsdk:"rollback_on_destroy"`
+	SnapTime          types.Int64  `tfsdk:"snaptime"`
+}
+
+type virtualMachineSnapshotResource struct {
+	client *proxmox.Client
+}
+
+func NewVirtualMachineSnapshotResource() resource.Resource {
+	return &virtualMachineSnapshotResource{}
+}
+
+func (r *virtualMachineSnapshotResource) Metadata(_ context.Context, request resource.MetadataRequest, response *resource.MetadataResponse) {
+	response.TypeName = request.ProviderTypeName + "_vm_snapshot"
+}
+
+func (r *virtualMachineSnapshotResource) Configure(_ context.Context, request resource.ConfigureRequest, response *resource.ConfigureResponse) {
+	if request.ProviderData == nil {
+		return
+	}
+
+	client, ok := request.ProviderData.(*proxmox.Client)
+
+	if !ok {
+		response.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *proxmox.Client, got %T. Please report this issue to the developers", request.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+func (r *virtualMachineSnapshotResource) Schema(_ context.Context, _ resource.SchemaRequest, response *resource.SchemaResponse) {
+	response.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"node": schema.StringAttribute{
+				Required:    true,
+				Description: "The node of the virtual machine. The snapshot follows the virtual machine when it is migrated",
+			},
+			"vmid": schema.Int64Attribute{
+				Required:    true,
+				Description: "The VMID of the virtual machine",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "The name of the snapshot, it must start with a letter",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"description": schema.StringAttribute{
+				Optional: true,
+			},
+			"include_ram": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Save the memory of a running virtual machine, so that a rollback resumes it where it was",
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"rollback_on_destroy": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Roll the virtual machine back to the snapshot before the snapshot is deleted",
+			},
+			"snaptime": schema.Int64Attribute{
+				Computed:    true,
+				Description: "When the snapshot was taken, in seconds since the Unix epoch",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *virtualMachineSnapshotResource) ModifyPlan(ctx context.Context, request resource.ModifyPlanRequest, response *resource.ModifyPlanResponse) {
+	// Nothing to check when the snapshot is created or destroyed
+	if request.Plan.Raw.IsNull() || request.State.Raw.IsNull() {
+		return
+	}
+
+	var plan, state VirtualMachineSnapshotResourceModel
+	response.Diagnostics.Append(request.Plan.Get(ctx, &plan)...)
+	response.Diagnostics.Append(request.State.Get(ctx, &state)...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	// The ID contains the node, it changes when the virtual machine has been migrated
+	if !plan.Node.IsUnknown() && plan.Node.ValueString() != state.Node.ValueString() {
+		plan.ID = types.StringUnknown()
+	}
+
+	response.Diagnostics.Append(response.Plan.Set(ctx, plan)...)
+}
+
+func (r *virtualMachineSnapshotResource) ImportState(ctx context.Context, request resource.ImportStateRequest, response *resource.ImportStateResponse) {
+	// The ID is a combination of the name of the node, the VMID and the name of the snapshot
+	idParts := strings.Split(request.ID, "/")
+	var vmid int64
+	var err error
+	if len(idParts) == 3 {
+		vmid, err = strconv.ParseInt(idParts[1], 10, 64)
+	}
+	if len(idParts) != 3 || idParts[0] == "" || idParts[2] == "" || err != nil {
+		response.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Please provide the identifier in the format: node/vmid/name. For example: pve/100/pre-upgrade. Got: %q", request.ID),
+		)
+		return
+	}
+	response.Diagnostics.Append(response.State.SetAttribute(ctx, path.Root("node"), idParts[0])...)
+	response.Diagnostics.Append(response.State.SetAttribute(ctx, path.Root("vmid"), vmid)...)
+	response.Diagnostics.Append(response.State.SetAttribute(ctx, path.Root("name"), idParts[2])...)
+	response.Diagnostics.Append(response.State.SetAttribute(ctx, path.Root("rollback_on_destroy"), false)...)
+}
+
+// snapshotState converts the snapshot into state. Proxmox does not return an empty description.
+func snapshotState(snapshot api.VirtualMachineSnapshot, prior VirtualMachineSnapshotResourceModel) VirtualMachineSnapshotResourceModel {
+	state := prior
+	state.ID = types.StringValue(fmt.Sprintf("%s/%d/%s", prior.Node.ValueString(), prior.VMID.ValueInt64(), snapshot.Name))
+	state.Name = types.StringValue(snapshot.Name)
+	state.Description = types.StringNull()
+	if snapshot.Description != "" {
+		state.Description = types.StringValue(snapshot.Description)
+	}
+	state.IncludeRAM = types.BoolValue(snapshot.VMState != nil && bool(*snapshot.VMState))
+	state.SnapTime = types.Int64Value(int64(snapshot.SnapTime))
+	return state
+}
+
+// findSnapshot returns the snapshot with the name. The current entry is not a snapshot and is never returned.
+func findSnapshot(snapshots []api.VirtualMachineSnapshot, name string) (api.VirtualMachineSnapshot, error) {
+	for _, snapshot := range snapshots {
+		if snapshot.Name == name && name != "current" {
+			return snapshot, nil
+		}
+	}
+	return api.VirtualMachineSnapshot{}, fmt.Errorf("the snapshot %q does not exist", name)
+}
+
+// guestNode returns the node that the guest of the type, qemu or lxc, is currently on
+func guestNode(resources []api.ClusterResource, guestType string, vmid int64) (string, error) {
+	for _, resource := range resources {
+		if resource.Type == guestType && int64(resource.VMID) == vmid {
+			return resource.Node, nil
+		}
+	}
+	return "", fmt.Errorf("the guest %d does not exist in the cluster", vmid)
+}
+
+// locateSnapshot sets the node of the snapshot to the node that the virtual machine is on, which differs from
+// the node in the state once the virtual machine has been migrated
+func (r *virtualMachineSnapshotResource) locateSnapshot(snapshot VirtualMachineSnapshotResourceModel) (VirtualMachineSnapshotResourceModel, error) {
+	resources, err := api.GetClusterResources(r.client, "vm")
+	if err != nil {
+		return snapshot, err
+	}
+	node, err := guestNode(resources, "qemu", snapshot.VMID.ValueInt64())
+	if err != nil {
+		return snapshot, err
+	}
+	snapshot.Node = types.StringValue(node)
+	return snapshot, nil
+}
+
+func (r *virtualMachineSnapshotResource) readSnapshot(prior VirtualMachineSnapshotResourceModel) (VirtualMachineSnapshotResourceModel, error) {
+	snapshots, err := api.GetVirtualMachineSnapshots(r.client, prior.Node.ValueString(), prior.VMID.ValueInt64())
+	if err != nil {
+		return prior, err
+	}
+	snapshot, err := findSnapshot(snapshots, prior.Name.ValueString())
+	if err != nil {
+		return prior, err
+	}
+	return snapshotState(snapshot, prior), nil
+}
+
+func (r *virtualMachineSnapshotResource) Create(ctx context.Context, request resource.CreateRequest, response *resource.CreateResponse) {
+	var plan VirtualMachineSnapshotResourceModel
+	diags := request.Plan.Get(ctx, &plan)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	vmState := api.Bool(plan.IncludeRAM.ValueBool())
+	snapshotRequest := api.VirtualMachineSnapshotRequest{
+		Name:        plan.Name.ValueString(),
+		Description: plan.Description.ValueStringPointer(),
+		VMState:     &vmState,
+	}
+
+	snapshotMutex.Lock()
+	upid, err := api.CreateVirtualMachineSnapshot(r.client, plan.Node.ValueString(), plan.VMID.ValueInt64(), &snapshotRequest)
+	if err == nil {
+		err = api.WaitForTask(r.client, upid, vmTaskTimeout)
+	}
+	snapshotMutex.Unlock()
+	if err == nil {
+		plan, err = r.readSnapshot(plan)
+	}
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error creating Proxmox virtual machine snapshot",
+			fmt.Sprintf("Could not create the snapshot %s of the Proxmox virtual machine: %d: %s", plan.Name.ValueString(), plan.VMID.ValueInt64(), err.Error()),
+		)
+		return
+	}
+
+	diags = response.State.Set(ctx, plan)
+	response.Diagnostics.Append(diags...)
+}
+
+func (r *virtualMachineSnapshotResource) Read(ctx context.Context, request resource.ReadRequest, response *resource.ReadResponse) {
+	var state VirtualMachineSnapshotResourceModel
+	diags := request.State.Get(ctx, &state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	state, err := r.locateSnapshot(state)
+	if err == nil {
+		state, err = r.readSnapshot(state)
+	}
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error reading Proxmox virtual machine snapshot",
+			fmt.Sprintf("Could not read the snapshot %s of the Proxmox virtual machine: %d: %s", state.Name.ValueString(), state.VMID.ValueInt64(), err.Error()),
+		)
+		return
+	}
+
+	diags = response.State.Set(ctx, &state)
+	response.Diagnostics.Append(diags...)
+}
+
+// Update only changes the description. A new node follows a migration of the virtual machine and does not touch the
+// snapshot, every other attribute replaces the snapshot or is only used by the provider
+func (r *virtualMachineSnapshotResource) Update(ctx context.Context, request resource.UpdateRequest, response *resource.UpdateResponse) {
+	var plan, state VirtualMachineSnapshotResourceModel
+	response.Diagnostics.Append(request.Plan.Get(ctx, &plan)...)
+	response.Diagnostics.Append(request.State.Get(ctx, &state)...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	var err error
+	if !plan.Description.Equal(state.Description) {
+		snapshotMutex.Lock()
+		err = api.UpdateVirtualMachineSnapshot(r.client, plan.Node.ValueString(), plan.VMID.ValueInt64(), plan.Name.ValueString(), plan.Description.ValueString())
+		snapshotMutex.Unlock()
+	}
+	if err == nil {
+		plan, err = r.readSnapshot(plan)
+	}
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error updating Proxmox virtual machine snapshot",
+			fmt.Sprintf("Could not update the snapshot %s of the Proxmox virtual machine: %d: %s", plan.Name.ValueString(), plan.VMID.ValueInt64(), err.Error()),
+		)
+		return
+	}
+
+	diags := response.State.Set(ctx, plan)
+	response.Diagnostics.Append(diags...)
+}
+
+func (r *virtualMachineSnapshotResource) Delete(ctx context.Context, request resource.DeleteRequest, response *resource.DeleteResponse) {
+	var state VirtualMachineSnapshotResourceModel
+	diags := request.State.Get(ctx, &state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	state, err := r.locateSnapshot(state)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error deleting Proxmox virtual machine snapshot",
+			fmt.Sprintf("Could not find the Proxmox virtual machine %d of the snapshot %s: %s", state.VMID.ValueInt64(), state.Name.ValueString(), err.Error()),
+		)
+		return
+	}
+
+	node := state.Node.ValueString()
+	vmid := state.VMID.ValueInt64()
+	name := state.Name.ValueString()
+
+	snapshotMutex.Lock()
+	defer snapshotMutex.Unlock()
+
+	if state.RollbackOnDestroy.ValueBool() {
+		upid, err := api.RollbackVirtualMachineSnapshot(r.client, node, vmid, name)
+		if err == nil {
+			err = api.WaitForTask(r.client, upid, vmTaskTimeout)
+		}
+		if err != nil {
+			response.Diagnostics.AddError(
+				"Error rolling back Proxmox virtual machine snapshot",
+				fmt.Sprintf("Could not roll the Proxmox virtual machine %d back to the snapshot %s: %s", vmid, name, err.Error()),
+			)
+			return
+		}
+	}
+
+	upid, err := api.DeleteVirtualMachineSnapshot(r.client, node, vmid, name)
+	if err == nil {
+		err = api.WaitForTask(r.client, upid, vmTaskTimeout)
+	}
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error deleting Proxmox virtual machine snapshot",
+			fmt.Sprintf("Could not delete the snapshot %s of the Proxmox virtual machine: %d: %s", name, vmid, err.Error()),
+		)
+		return
+	}
+}
@@ -0,0 +1,141 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"terraform-provider-proxmox/internal/api"
+	"testing"
+)
+
+func TestVirtualMachineSnapshotResource_Create(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+resource "proxmox_vm" "test" {
+  node = "pve"
+  vmid = 9004
+  name = "terraform-snapshot"
+
+  disk {
+    interface = "scsi0"
+    storage   = "local-lvm"
+    size      = 4
+  }
+}
+
+resource "proxmox_vm_snapshot" "test" {
+  node        = proxmox_vm.test.node
+  vmid        = proxmox_vm.test.vmid
+  name        = "pre_upgrade"
+  description = "Before the upgrade"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("proxmox_vm_snapshot.test", "id", "pve/9004/pre_upgrade"),
+					resource.TestCheckResourceAttr("proxmox_vm_snapshot.test", "description", "Before the upgrade"),
+					resource.TestCheckResourceAttr("proxmox_vm_snapshot.test", "include_ram", "false"),
+					resource.TestCheckResourceAttrSet("proxmox_vm_snapshot.test", "snaptime"),
+				),
+			},
+			{
+				ResourceName:      "proxmox_vm_snapshot.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateId:     "pve/9004/pre_upgrade",
+			},
+			{
+				Config: providerConfig + `
+resource "proxmox_vm" "test" {
+  node = "pve"
+  vmid = 9004
+  name = "terraform-snapshot"
+
+  disk {
+    interface = "scsi0"
+    storage   = "local-lvm"
+    size      = 4
+  }
+}
+
+resource "proxmox_vm_snapshot" "test" {
+  node                = proxmox_vm.test.node
+  vmid                = proxmox_vm.test.vmid
+  name                = "pre_upgrade"
+  description         = "Before upgrading to bookworm"
+  rollback_on_destroy = true
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("proxmox_vm_snapshot.test", "description", "Before upgrading to bookworm"),
+					resource.TestCheckResourceAttr("proxmox_vm_snapshot.test", "rollback_on_destroy", "true"),
+				),
+			},
+		},
+	})
+}
+
+func TestSnapshotState(t *testing.T) {
+	vmState := api.Bool(true)
+	snapshots := []api.VirtualMachineSnapshot{
+		{Name: "pre_upgrade", Description: "Before the upgrade", SnapTime: 1700000000, VMState: &vmState},
+		{Name: "current", Description: "You are here!", Parent: "pre_upgrade"},
+	}
+
+	if _, err := findSnapshot(snapshots, "current"); err == nil {
+		t.Errorf("Expected the current entry not to be found")
+	}
+	snapshot, err := findSnapshot(snapshots, "pre_upgrade")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prior := VirtualMachineSnapshotResourceModel{
+		Node:              types.StringValue("pve"),
+		VMID:              types.Int64Value(100),
+		Name:              types.StringValue("pre_upgrade"),
+		RollbackOnDestroy: types.BoolValue(true),
+	}
+	state := snapshotState(snapshot, prior)
+	if state.ID.ValueString() != "pve/100/pre_upgrade" || state.Description.ValueString() != "Before the upgrade" ||
+		!state.IncludeRAM.ValueBool() || state.SnapTime.ValueInt64() != 1700000000 || !state.RollbackOnDestroy.ValueBool() {
+		t.Errorf("Incorrect state: %+v", state)
+	}
+
+	state = snapshotState(api.VirtualMachineSnapshot{Name: "pre_upgrade"}, prior)
+	if !state.Description.IsNull() || state.IncludeRAM.ValueBool() {
+		t.Errorf("Expected no description and no RAM, got %+v", state)
+	}
+}
+
+func TestGuestNode(t *testing.T) {
+	resources := []api.ClusterResource{
+		{ID: "lxc/100", Type: "lxc", Node: "pve1", VMID: 100},
+		{ID: "qemu/100", Type: "qemu", Node: "pve2", VMID: 100},
+		{ID: "qemu/101", Type: "qemu", Node: "pve1", VMID: 101},
+	}
+
+	node, err := guestNode(resources, "qemu", 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if node != "pve2" {
+		t.Errorf("Incorrect node returned. Expected pve2, got %v", node)
+	}
+
+	if _, err := guestNode(resources, "qemu", 102); err == nil {
+		t.Errorf("Expected an error for a virtual machine that does not exist")
+	}
+
+	// After a migration the snapshot is read from the new node and its ID follows
+	prior := VirtualMachineSnapshotResourceModel{
+		Node: types.StringValue(node),
+		VMID: types.Int64Value(100),
+		Name: types.StringValue("pre_upgrade"),
+	}
+	state := snapshotState(api.VirtualMachineSnapshot{Name: "pre_upgrade"}, prior)
+	if state.ID.ValueString() != "pve2/100/pre_upgrade" {
+		t.Errorf("Incorrect ID after the migration: %v", state.ID.ValueString())
+	}
+}
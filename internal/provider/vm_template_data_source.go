@@ -0,0 +1,227 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/clincha-org/proxmox-api/pkg/proxmox"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"strings"
+	"terraform-provider-proxmox/internal/api"
+)
+
+var (
+	_ datasource.DataSource                   = &virtualMachineTemplateDataSource{}
+	_ datasource.DataSourceWithConfigure      = &virtualMachineTemplateDataSource{}
+	_ datasource.DataSourceWithValidateConfig = &virtualMachineTemplateDataSource{}
+)
+
+type VirtualMachineTemplateDataSourceModel struct {
+	ID   types.String `tfsdk:"id"`
+	Name types.String `tfsdk:"name"`
+	Tag  types.String `tfsdk:"tag"`
+	Node types.String `tfsdk:"node"`
+	VMID types.Int64  `tfsdk:"vmid"`
+	Tags types.List   `tfsdk:"tags"`
+}
+
+// nodeVirtualMachine A virtual machine and the node that it is on
+type nodeVirtualMachine struct {
+	node string
+	api.VirtualMachineStatus
+}
+
+// virtualMachineTemplateDataSource finds a single template in the cluster, so that it can be used as the
+// source of a clone without hard coding its VMID
+type virtualMachineTemplateDataSource struct {
+	client *proxmox.Client
+}
+
+func NewVirtualMachineTemplateDataSource() datasource.DataSource {
+	return &virtualMachineTemplateDataSource{}
+}
+
+func (d *virtualMachineTemplateDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_vm_template"
+}
+
+func (d *virtualMachineTemplateDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+			},
+			"name": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "The name of the template",
+			},
+			"tag": schema.StringAttribute{
+				Optional:    true,
+				Description: "A tag that the template must have",
+			},
+			"node": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Only search this node. Defaults to every online node in the cluster",
+			},
+			"vmid": schema.Int64Attribute{
+				Computed: true,
+			},
+			"tags": schema.ListAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+		},
+	}
+}
+
+func (d *virtualMachineTemplateDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	var config VirtualMachineTemplateDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.Name.IsNull() && config.Tag.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("name"),
+			"Missing template search",
+			"At least one of name and tag must be set",
+		)
+	}
+}
+
+func (d *virtualMachineTemplateDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config VirtualMachineTemplateDataSourceModel
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	virtualMachines, err := d.virtualMachines(config.Node.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to read Proxmox virtual machines",
+			err.Error(),
+		)
+		return
+	}
+
+	template, err := findTemplate(virtualMachines, config.Name.ValueString(), config.Tag.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Proxmox template not found", err.Error())
+		return
+	}
+
+	var tags []attr.Value
+	for _, tag := range splitTags(template.Tags) {
+		tags = append(tags, types.StringValue(tag))
+	}
+
+	config.ID = types.StringValue(fmt.Sprintf("%s/%d", template.node, template.VMID))
+	config.Name = types.StringValue(template.Name)
+	config.Node = types.StringValue(template.node)
+	config.VMID = types.Int64Value(int64(template.VMID))
+	config.Tags, diags = types.ListValue(types.StringType, tags)
+	resp.Diagnostics.Append(diags...)
+
+	diags = resp.State.Set(ctx, config)
+	resp.Diagnostics.Append(diags...)
+}
+
+// virtualMachines lists the virtual machines on the node, or on every online node when node is empty
+func (d *virtualMachineTemplateDataSource) virtualMachines(node string) ([]nodeVirtualMachine, error) {
+	nodes := []string{node}
+	if node == "" {
+		clusterNodes, err := d.client.GetNodes()
+		if err != nil {
+			return nil, err
+		}
+		nodes = nil
+		for _, clusterNode := range clusterNodes {
+			if clusterNode.Status == "online" {
+				nodes = append(nodes, clusterNode.Node)
+			}
+		}
+	}
+
+	var virtualMachines []nodeVirtualMachine
+	for _, node := range nodes {
+		nodeVirtualMachines, err := api.GetVirtualMachines(d.client, node)
+		if err != nil {
+			return nil, err
+		}
+		for _, virtualMachine := range nodeVirtualMachines {
+			virtualMachines = append(virtualMachines, nodeVirtualMachine{node: node, VirtualMachineStatus: virtualMachine})
+		}
+	}
+	return virtualMachines, nil
+}
+
+// findTemplate returns the only template with the name and tag. An empty name or tag matches any template.
+func findTemplate(virtualMachines []nodeVirtualMachine, name string, tag string) (nodeVirtualMachine, error) {
+	var matches []nodeVirtualMachine
+	for _, virtualMachine := range virtualMachines {
+		if virtualMachine.Template == nil || !bool(*virtualMachine.Template) {
+			continue
+		}
+		if name != "" && virtualMachine.Name != name {
+			continue
+		}
+		if tag != "" && !hasTag(virtualMachine.Tags, tag) {
+			continue
+		}
+		matches = append(matches, virtualMachine)
+	}
+
+	switch len(matches) {
+	case 0:
+		return nodeVirtualMachine{}, fmt.Errorf("no template matches the name %q and tag %q", name, tag)
+	case 1:
+		return matches[0], nil
+	default:
+		var found []string
+		for _, match := range matches {
+			found = append(found, fmt.Sprintf("%s/%d", match.node, match.VMID))
+		}
+		return nodeVirtualMachine{}, fmt.Errorf("%d templates match the name %q and tag %q: %s", len(matches), name, tag, strings.Join(found, ", "))
+	}
+}
+
+// splitTags splits the tags that Proxmox returns, which are separated by semicolons
+func splitTags(tags string) []string {
+	return strings.FieldsFunc(tags, func(r rune) bool {
+		return r == ';' || r == ',' || r == ' '
+	})
+}
+
+func hasTag(tags string, tag string) bool {
+	for _, candidate := range splitTags(tags) {
+		if candidate == tag {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *virtualMachineTemplateDataSource) Configure(_ context.Context, request datasource.ConfigureRequest, response *datasource.ConfigureResponse) {
+	if request.ProviderData == nil {
+		return
+	}
+
+	client, ok := request.ProviderData.(*proxmox.Client)
+	if !ok {
+		response.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *proxmox.Client, got %T. Please report this error to the developer", request.ProviderData),
+		)
+		return
+	}
+
+	d.client = client
+}
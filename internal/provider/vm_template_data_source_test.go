@@ -0,0 +1,82 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"terraform-provider-proxmox/internal/api"
+	"testing"
+)
+
+func TestVirtualMachineTemplateDataSource_Read(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+resource "proxmox_vm" "template" {
+  node     = "pve"
+  vmid     = 9003
+  name     = "terraform-template"
+  tags     = ["terraform-template"]
+  template = true
+
+  disk {
+    interface = "scsi0"
+    storage   = "local-lvm"
+    size      = 4
+  }
+}
+
+data "proxmox_vm_template" "test" {
+  tag = "terraform-template"
+
+  depends_on = [proxmox_vm.template]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("proxmox_vm.template", "template", "true"),
+					resource.TestCheckResourceAttr("proxmox_vm.template", "started", "false"),
+					resource.TestCheckResourceAttr("data.proxmox_vm_template.test", "id", "pve/9003"),
+					resource.TestCheckResourceAttr("data.proxmox_vm_template.test", "vmid", "9003"),
+					resource.TestCheckResourceAttr("data.proxmox_vm_template.test", "name", "terraform-template"),
+					resource.TestCheckResourceAttr("data.proxmox_vm_template.test", "node", "pve"),
+				),
+			},
+		},
+	})
+}
+
+func TestFindTemplate(t *testing.T) {
+	template := api.Bool(true)
+	virtualMachines := []nodeVirtualMachine{
+		{node: "pve", VirtualMachineStatus: api.VirtualMachineStatus{VMID: 9000, Name: "debian", Tags: "golden;debian", Template: &template}},
+		{node: "pve2", VirtualMachineStatus: api.VirtualMachineStatus{VMID: 9001, Name: "debian", Tags: "debian", Template: &template}},
+		{node: "pve", VirtualMachineStatus: api.VirtualMachineStatus{VMID: 9002, Name: "ubuntu", Tags: "golden", Template: &template}},
+		{node: "pve", VirtualMachineStatus: api.VirtualMachineStatus{VMID: 100, Name: "web", Tags: "golden"}},
+	}
+
+	found, err := findTemplate(virtualMachines, "debian", "golden")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found.VMID != 9000 || found.node != "pve" {
+		t.Errorf("Incorrect template returned: %+v", found)
+	}
+
+	found, err = findTemplate(virtualMachines, "ubuntu", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found.VMID != 9002 {
+		t.Errorf("Incorrect template returned: %+v", found)
+	}
+
+	_, err = findTemplate(virtualMachines, "debian", "")
+	if err == nil {
+		t.Error("Expected an error when more than one template matches")
+	}
+
+	_, err = findTemplate(virtualMachines, "web", "")
+	if err == nil {
+		t.Error("Expected an error when only a virtual machine matches")
+	}
+}
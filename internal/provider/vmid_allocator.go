@@ -0,0 +1,64 @@
+package provider
+
+import (
+	"fmt"
+	"github.com/clincha-org/proxmox-api/pkg/proxmox"
+	"sync"
+	"terraform-provider-proxmox/internal/api"
+)
+
+// Proxmox only reports the lowest free VMID, it does not reserve it. Terraform creates resources in
+// parallel, so two virtual machines in the same apply would otherwise be given the same VMID. The
+// allocator hands out each VMID once per cluster for the lifetime of the provider.
+
+// maxVMID is the highest VMID that Proxmox accepts
+const maxVMID = 999999999
+
+type vmidAllocator struct {
+	mu sync.Mutex
+	// reserved holds the VMIDs that have been handed out, by the host of the cluster
+	reserved map[string]map[int64]bool
+}
+
+var allocator = &vmidAllocator{reserved: map[string]map[int64]bool{}}
+
+// allocate returns the lowest free VMID between lower and upper and reserves it. Zero means no limit.
+func (a *vmidAllocator) allocate(client *proxmox.Client, lower int64, upper int64) (int64, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if upper == 0 {
+		upper = maxVMID
+	}
+	reserved := a.reserved[client.Host]
+	if reserved == nil {
+		reserved = map[int64]bool{}
+		a.reserved[client.Host] = reserved
+	}
+
+	// Proxmox skips the VMIDs that are in use, so there is nothing free below its answer
+	next, err := api.GetNextVMID(client)
+	if err != nil {
+		return 0, err
+	}
+	vmid := max(next, lower)
+
+	for ; vmid <= upper; vmid++ {
+		if reserved[vmid] {
+			continue
+		}
+		// The VMID from Proxmox is known to be free, the others are checked
+		free := vmid == next
+		if !free {
+			free, err = api.IsVMIDFree(client, vmid)
+			if err != nil {
+				return 0, err
+			}
+		}
+		if free {
+			reserved[vmid] = true
+			return vmid, nil
+		}
+	}
+	return 0, fmt.Errorf("there is no free VMID between %d and %d", lower, upper)
+}